@@ -5,6 +5,9 @@ package app
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -19,6 +22,8 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/crypto/ssh/terminal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gopkg.in/yaml.v3"
 
 	"github.com/sykesm/batik/pkg/atexit"
@@ -68,6 +73,7 @@ func Batik(args []string, stdin io.ReadCloser, stdout, stderr io.Writer) *cli.Ap
 	app.Commands = []*cli.Command{
 		startCommand(config, false),
 		dbCommand(config),
+		orderCommand(),
 	}
 
 	// Sort the flags and commands to make it easier to find things.
@@ -81,7 +87,7 @@ func Batik(args []string, stdin io.ReadCloser, stdout, stderr io.Writer) *cli.Ap
 	}
 
 	app.Before = func(ctx *cli.Context) error {
-		err := resolveConfig(ctx, config)
+		configPath, err := resolveConfig(ctx, config)
 		if err != nil {
 			return cli.Exit(errors.WithMessage(err, "unable to read config"), exitConfigLoadFailed)
 		}
@@ -93,24 +99,37 @@ func Batik(args []string, stdin io.ReadCloser, stdout, stderr io.Writer) *cli.Ap
 		SetLogger(ctx, logger)
 		SetLeveler(ctx, leveler)
 
-		totalOrders, err := newBatikTotalOrderComponents(ctx, config.TotalOrders)
+		totalOrders, stopTotalOrders, err := newBatikTotalOrderComponents(ctx, config.TotalOrders)
 		if err != nil {
 			return cli.Exit(err, exitConfigLoadFailed)
 		}
-		_ = totalOrders // TODO, wire into namespace
-		// TODO safely shut down the total orders and their dbs
+		atexit.Register(stopTotalOrders)
 
 		validators, err := newBatikValidatorComponents(config.Validators)
 		if err != nil {
 			return cli.Exit(err, exitConfigLoadFailed)
 		}
 
-		namespaces, err := newBatikNamespaceComponents(ctx, config.Namespaces, validators)
+		identity, err := config.Identity.PrivateKey()
+		if err != nil {
+			return cli.Exit(err, exitConfigLoadFailed)
+		}
+		if identity == nil {
+			logger.Debug("no identity key configured, commits will not be attested")
+		}
+
+		namespaces, err := newBatikNamespaceComponents(ctx, config.Namespaces, validators, totalOrders, identity)
 		if err != nil {
 			return cli.Exit(err, exitConfigLoadFailed)
 		}
 
-		SetNamespaces(ctx, namespaces)
+		registry := namespace.NewRegistry(namespaces)
+		// Namespaces stop delivering before the total orders are stopped.
+		atexit.Register(func() { stopNamespaces(registry) })
+
+		SetTotalOrders(ctx, totalOrders)
+		SetNamespaces(ctx, registry)
+		SetNamespaceManager(ctx, NewNamespaceManager(logger, config, configPath, registry, validators, totalOrders, identity))
 		// TODO safely shut down the DB
 		// atexit.Register(func() { namespaces.Close() })
 
@@ -163,7 +182,10 @@ func Batik(args []string, stdin io.ReadCloser, stdout, stderr io.Writer) *cli.Ap
 //   2. Load the configuration file
 //   3. Reapply the values to the flags
 //   4. Rerun tag resolution on the configuration file
-func resolveConfig(ctx *cli.Context, config *options.Batik) error {
+//
+// The path of the configuration file that was loaded is returned. The path is
+// empty when no configuration file was found.
+func resolveConfig(ctx *cli.Context, config *options.Batik) (string, error) {
 	// Save the top-level flags to push back to the config
 	flags := map[string]interface{}{}
 	for _, name := range ctx.LocalFlagNames() {
@@ -175,7 +197,7 @@ func resolveConfig(ctx *cli.Context, config *options.Batik) error {
 	if configPath == "" {
 		cf, err := conf.File(ctx.App.Name)
 		if err != nil {
-			return err
+			return "", err
 		}
 		configPath = cf
 	}
@@ -184,7 +206,7 @@ func resolveConfig(ctx *cli.Context, config *options.Batik) error {
 	if configPath != "" {
 		err := conf.LoadFile(configPath, config)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
@@ -196,7 +218,7 @@ func resolveConfig(ctx *cli.Context, config *options.Batik) error {
 
 	// Resolve the tags after re-applying flags
 	tr := &conf.TagResolver{SourcePath: filepath.Dir(configPath)}
-	return tr.Resolve(config)
+	return configPath, tr.Resolve(config)
 }
 
 // showConfigBefore returns a function that dumps the configuration and
@@ -238,7 +260,7 @@ func newBatikLoggerComponents(ctx *cli.Context, config options.Logging) (zapcore
 	return encoder, log.NewWriteSyncer(w), log.NewLeveler(config.LogSpec)
 }
 
-func newBatikNamespaceComponents(ctx *cli.Context, config []options.Namespace, validators map[string]namespace.Validator) (map[string]*namespace.Namespace, error) {
+func newBatikNamespaceComponents(ctx *cli.Context, config []options.Namespace, validators map[string]namespace.Validator, totalOrders map[string]namespace.TotalOrder, identity *ecdsa.PrivateKey) (map[string]*namespace.Namespace, error) {
 	logger, err := GetLogger(ctx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "could not retrieve logger")
@@ -246,20 +268,11 @@ func newBatikNamespaceComponents(ctx *cli.Context, config []options.Namespace, v
 
 	namespaces := map[string]*namespace.Namespace{}
 	for _, ns := range config {
-		namespaceLogger := logger.With(zap.String("namespace", ns.Name))
-
-		namespaceLogger.Debug("initializing namespace database", zap.String("data_dir", ns.DataDir))
-		db, err := store.NewLevelDB(ns.DataDir)
+		n, err := newNamespace(logger, ns, validators, totalOrders, identity)
 		if err != nil {
 			return nil, err
 		}
-
-		v, ok := validators[ns.Validator]
-		if !ok {
-			return nil, errors.Errorf("namespace %q requires validator %q which is not defined", ns.Name, ns.Validator)
-		}
-
-		namespaces[ns.Name] = namespace.New(namespaceLogger, crypto.SHA256, db, v)
+		namespaces[ns.Name] = n
 	}
 	return namespaces, nil
 }
@@ -302,30 +315,203 @@ func newBatikValidatorComponents(config []options.Validator) (map[string]namespa
 	return result, nil
 }
 
-// TODO, the map type is wrong, need to create our consumer and use the interface type there
-func newBatikTotalOrderComponents(ctx *cli.Context, config []options.TotalOrder) (map[string]*totalorder.InProcess, error) {
+func stopNamespaces(registry *namespace.Registry) {
+	for _, name := range registry.Names() {
+		if ns, ok := registry.Lookup(name); ok {
+			ns.Stop()
+		}
+	}
+}
+
+// newBatikTotalOrderComponents creates the configured total orders. The
+// returned shutdown function stops the total orders and closes their
+// databases and connections.
+func newBatikTotalOrderComponents(ctx *cli.Context, config []options.TotalOrder) (_ map[string]namespace.TotalOrder, _ func(), err error) {
 	logger, err := GetLogger(ctx)
 	if err != nil {
-		return nil, errors.WithMessage(err, "could not retrieve logger")
+		return nil, nil, errors.WithMessage(err, "could not retrieve logger")
+	}
+
+	var closers []func()
+	shutdown := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
 	}
+	defer func() {
+		if err != nil {
+			shutdown()
+		}
+	}()
 
-	result := map[string]*totalorder.InProcess{}
+	result := map[string]namespace.TotalOrder{}
 	for _, to := range config {
-		if to.Type != "in-process" {
-			return nil, errors.Errorf("totalorder %q has unknown type %q, must be \"in-process\"", to.Name, to.Type)
+		if to.Type != "in-process" && to.Type != "raft" && to.Type != "grpc" {
+			return nil, nil, errors.Errorf("totalorder %q has unknown type %q, must be \"in-process\", \"raft\", or \"grpc\"", to.Name, to.Type)
+		}
+
+		totalorderLogger := logger.With(zap.String("totalorder", to.Name))
+
+		if to.Type == "grpc" {
+			totalorderLogger.Debug("connecting to remote totalorder", zap.String("address", to.GRPC.Address))
+			conn, err := dialGRPCTotalOrder(to.GRPC)
+			if err != nil {
+				return nil, nil, errors.WithMessagef(err, "could not connect totalorder %q", to.Name)
+			}
+			closers = append(closers, func() { conn.Close() })
+			result[to.Name] = totalorder.NewGRPC(conn, to.GRPC.TotalOrder)
+			continue
+		}
+
+		openDB, ok := storageEngines[to.Storage]
+		if !ok {
+			return nil, nil, errors.Errorf("totalorder %q has unknown storage %q, must be \"leveldb\", \"bbolt\", or \"memory\"", to.Name, to.Storage)
 		}
 
-		totalorderLogger := logger.With(zap.String("namespace", to.Name))
+		totalorderLogger.Debug("initializing totalorder database", zap.String("storage", to.Storage), zap.String("data_dir", to.DataDir))
+		db, err := openDB(to.DataDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		closers = append(closers, func() {
+			if err := db.Close(); err != nil {
+				totalorderLogger.Warn("failed to close totalorder database", zap.Error(err))
+			}
+		})
 
-		totalorderLogger.Debug("initializing totalorder database", zap.String("data_dir", to.DataDir))
-		db, err := store.NewLevelDB(to.DataDir)
+		orderStore, err := totalorder.NewStore(crypto.SHA256, db)
 		if err != nil {
-			return nil, err
+			return nil, nil, errors.WithMessagef(err, "could not open totalorder %q", to.Name)
 		}
 
-		ipo := totalorder.NewInProcess(totalorder.NewStore(crypto.SHA256, db))
-		result[to.Name] = ipo
+		switch to.Type {
+		case "raft":
+			r, err := newRaftTotalOrder(totalorderLogger, to.Name, to.Raft, orderStore, db)
+			if err != nil {
+				return nil, nil, errors.WithMessagef(err, "could not start totalorder %q", to.Name)
+			}
+			closers = append(closers, r.Stop)
+			result[to.Name] = r
+		default:
+			ipo := totalorder.NewBatchingInProcess(orderStore, totalorder.BatchConfig{
+				MaxEntries: to.Batch.MaxEntries,
+				MaxBytes:   to.Batch.MaxBytes,
+				Timeout:    to.Batch.Timeout,
+			})
+			closers = append(closers, ipo.Stop)
+			result[to.Name] = ipo
+		}
 	}
 
-	return result, nil
+	return result, shutdown, nil
+}
+
+// A raftTotalOrder is a raft node with the transport and connections it uses
+// to reach the other members of the cluster.
+type raftTotalOrder struct {
+	*totalorder.Raft
+	addresses map[uint64]string
+	transport *totalorder.GRPCTransport
+	conns     []*grpc.ClientConn
+}
+
+// PeerAddress returns the configured address of a member of the cluster
+// hosted by another node.
+func (r *raftTotalOrder) PeerAddress(id uint64) (string, bool) {
+	address, ok := r.addresses[id]
+	return address, ok
+}
+
+// HasPeers reports whether members of the cluster are hosted by other nodes.
+func (r *raftTotalOrder) HasPeers() bool {
+	return len(r.conns) != 0
+}
+
+// Stop stops the raft node and then closes the connections to its peers.
+func (r *raftTotalOrder) Stop() {
+	r.Raft.Stop()
+	r.transport.Close()
+	closeConns(r.conns)
+}
+
+func newRaftTotalOrder(logger *zap.Logger, name string, config options.RaftTotalOrder, orderStore *totalorder.Store, db store.KV) (*raftTotalOrder, error) {
+	var conns []*grpc.ClientConn
+	addresses := map[uint64]string{}
+	peers := map[uint64]grpc.ClientConnInterface{}
+	for _, peer := range config.Peers {
+		if peer == config.NodeID {
+			continue
+		}
+		address, ok := config.PeerAddresses[peer]
+		if !ok || address == "" {
+			closeConns(conns)
+			return nil, errors.Errorf("raft peer %d has no address", peer)
+		}
+		addresses[peer] = address
+	}
+
+	if len(addresses) != 0 {
+		clientCert, err := config.ClientCert.TLSCertificate()
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not load raft client certificate")
+		}
+		for peer, address := range addresses {
+			conn, err := dialTLS(address, config.RootCAFile, clientCert)
+			if err != nil {
+				closeConns(conns)
+				return nil, errors.WithMessagef(err, "could not connect to raft peer %d", peer)
+			}
+			conns = append(conns, conn)
+			peers[peer] = conn
+		}
+	}
+
+	transport := totalorder.NewGRPCTransport(logger, name, peers)
+	r, err := totalorder.NewRaft(totalorder.RaftConfig{
+		ID:             config.NodeID,
+		Peers:          config.Peers,
+		TickInterval:   config.TickInterval,
+		ElectionTicks:  config.ElectionTicks,
+		HeartbeatTicks: config.HeartbeatTicks,
+	}, logger, orderStore, db, transport)
+	if err != nil {
+		transport.Close()
+		closeConns(conns)
+		return nil, err
+	}
+
+	return &raftTotalOrder{Raft: r, addresses: addresses, transport: transport, conns: conns}, nil
+}
+
+func closeConns(conns []*grpc.ClientConn) {
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+func dialGRPCTotalOrder(config options.GRPCTotalOrder) (*grpc.ClientConn, error) {
+	return dialTLS(config.Address, config.RootCAFile)
+}
+
+// dialTLS connects to a gRPC server over TLS. The server is verified with the
+// certificates in rootCAFile or the system trust store when it is empty. The
+// client certificates, if any, are presented to the server.
+func dialTLS(address, rootCAFile string, clientCerts ...tls.Certificate) (*grpc.ClientConn, error) {
+	if address == "" {
+		return nil, errors.New("grpc address is required")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: clientCerts}
+	if rootCAFile != "" {
+		pem, err := ioutil.ReadFile(rootCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read root CA file")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in %s", rootCAFile)
+		}
+	}
+
+	return grpc.Dial(address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 }
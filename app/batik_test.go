@@ -5,6 +5,9 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -13,10 +16,13 @@ import (
 
 	. "github.com/onsi/gomega"
 	cli "github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 
 	"github.com/sykesm/batik/pkg/options"
+	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
 )
 
 func TestBatikWiring(t *testing.T) {
@@ -37,22 +43,26 @@ func TestBatikWiring(t *testing.T) {
 	gt.Expect(app.Flags[4].Names()[0]).To(Equal("log-spec"))
 
 	// Command implementations
-	gt.Expect(app.Commands).To(HaveLen(2))
+	gt.Expect(app.Commands).To(HaveLen(3))
 	gt.Expect(app.Commands[0].Name).To(Equal("db"))
-	gt.Expect(app.Commands[1].Name).To(Equal("start"))
+	gt.Expect(app.Commands[1].Name).To(Equal("order"))
+	gt.Expect(app.Commands[2].Name).To(Equal("start"))
 
 	// Subcommand implementations
-	gt.Expect(app.Commands[0].Subcommands).To(HaveLen(3))
+	gt.Expect(app.Commands[0].Subcommands).To(HaveLen(4))
 	gt.Expect(app.Commands[0].Subcommands[0].Name).To(Equal("get"))
-	gt.Expect(app.Commands[0].Subcommands[0].Subcommands).To(HaveLen(2))
-	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[0].Name).To(Equal("state"))
-	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[0].Flags).To(HaveLen(1))
-	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[0].Flags[0].Names()[0]).To(Equal("consumed"))
-	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[1].Name).To(Equal("tx"))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands).To(HaveLen(3))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[0].Name).To(Equal("rejected"))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[1].Name).To(Equal("state"))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[1].Flags).To(HaveLen(1))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[1].Flags[0].Names()[0]).To(Equal("consumed"))
+	gt.Expect(app.Commands[0].Subcommands[0].Subcommands[2].Name).To(Equal("tx"))
 	gt.Expect(app.Commands[0].Subcommands[1].Name).To(Equal("keys"))
-	gt.Expect(app.Commands[0].Subcommands[1].Flags).To(HaveLen(1))
+	gt.Expect(app.Commands[0].Subcommands[1].Flags).To(HaveLen(5))
 	gt.Expect(app.Commands[0].Subcommands[1].Flags[0].Names()[0]).To(Equal("prefix"))
+	gt.Expect(app.Commands[0].Subcommands[1].Flags[4].Names()[0]).To(Equal("reverse"))
 	gt.Expect(app.Commands[0].Subcommands[2].Name).To(Equal("put"))
+	gt.Expect(app.Commands[0].Subcommands[3].Name).To(Equal("reindex"))
 }
 
 func TestBatikCommandNotFound(t *testing.T) {
@@ -164,6 +174,203 @@ func TestBatikBadValidator(t *testing.T) {
 	gt.Expect(stderr.String()).To(MatchRegexp("could not load wasm binary for validator \"missing\".*"))
 }
 
+func TestBatikBadTotalOrder(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorders")
+	defer cleanup()
+
+	config := options.BatikDefaults()
+	config.DataDir = filepath.Join(path, "data")
+	config.TotalOrders = []options.TotalOrder{
+		{
+			Name: "default",
+			Type: "raft",
+			Raft: options.RaftTotalOrder{NodeID: 1, Peers: []uint64{1, 2, 3}},
+		},
+	}
+
+	configBytes, err := yaml.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configPath := filepath.Join(path, "batik.yaml")
+	err = ioutil.WriteFile(configPath, configBytes, 0o666)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	stdin := bytes.NewBuffer(nil)
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	app := Batik(nil, ioutil.NopCloser(stdin), stdout, stderr)
+	app.ExitErrHandler = func(ctx *cli.Context, err error) {
+		fmt.Fprintf(ctx.App.ErrWriter, "%+v\n", err)
+	}
+
+	err = app.Run([]string{"batik", "--config", configPath})
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(err.(cli.ExitCoder).ExitCode()).To(Equal(3))
+	gt.Expect(stdout.String()).To(BeEmpty())
+	gt.Expect(stderr.String()).To(ContainSubstring(`could not start totalorder "default": raft peer 2 has no address`))
+}
+
+func TestBatikBadTotalOrderStorage(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorders")
+	defer cleanup()
+
+	config := options.BatikDefaults()
+	config.DataDir = filepath.Join(path, "data")
+	config.TotalOrders[0].Storage = "missing"
+
+	configBytes, err := yaml.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configPath := filepath.Join(path, "batik.yaml")
+	err = ioutil.WriteFile(configPath, configBytes, 0o666)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	stdin := bytes.NewBuffer(nil)
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	app := Batik(nil, ioutil.NopCloser(stdin), stdout, stderr)
+	app.ExitErrHandler = func(ctx *cli.Context, err error) {
+		fmt.Fprintf(ctx.App.ErrWriter, "%+v\n", err)
+	}
+
+	err = app.Run([]string{"batik", "--config", configPath})
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(err.(cli.ExitCoder).ExitCode()).To(Equal(3))
+	gt.Expect(stdout.String()).To(BeEmpty())
+	gt.Expect(stderr.String()).To(ContainSubstring(`totalorder "default" has unknown storage "missing", must be "leveldb", "bbolt", or "memory"`))
+}
+
+func TestBatikTotalOrderShutdown(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorders")
+	defer cleanup()
+
+	ckp := tested.NewCA(t, "peer-ca").IssueClientCertificate(t, "raft-1", "127.0.0.1")
+	config := []options.TotalOrder{
+		{Name: "in-process", Type: "in-process"},
+		{Name: "raft", Type: "raft", Raft: options.RaftTotalOrder{
+			NodeID:        1,
+			Peers:         []uint64{1, 2},
+			PeerAddresses: map[uint64]string{2: "127.0.0.1:1"},
+			ClientCert:    options.CertKeyPair{CertData: string(ckp.CertChain), KeyData: string(ckp.Key)},
+		}},
+	}
+	for i := range config {
+		config[i].ApplyDefaults(path)
+	}
+
+	ctx := cli.NewContext(cli.NewApp(), nil, nil)
+	SetLogger(ctx, zap.NewNop())
+	totalOrders, shutdown, err := newBatikTotalOrderComponents(ctx, config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	totalOrders["raft"].(*raftTotalOrder).Start()
+
+	shutdown()
+	for name, to := range totalOrders {
+		err := to.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), make([]byte, 32)))
+		gt.Expect(err).To(MatchError("told to exit"), name)
+	}
+
+	// The databases were closed and may be opened again.
+	for _, to := range config {
+		db, err := store.NewLevelDB(to.DataDir)
+		gt.Expect(err).NotTo(HaveOccurred())
+		tested.Close(t, db)
+	}
+}
+
+func TestBatikRaftTotalOrderRequiresClientCert(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorders")
+	defer cleanup()
+
+	config := []options.TotalOrder{
+		{Name: "raft", Type: "raft", Raft: options.RaftTotalOrder{NodeID: 1, Peers: []uint64{1, 2}, PeerAddresses: map[uint64]string{2: "127.0.0.1:1"}}},
+	}
+	config[0].ApplyDefaults(path)
+
+	ctx := cli.NewContext(cli.NewApp(), nil, nil)
+	SetLogger(ctx, zap.NewNop())
+	_, _, err := newBatikTotalOrderComponents(ctx, config)
+	gt.Expect(err).To(MatchError(`could not start totalorder "raft": could not load raft client certificate: tls: failed to find any PEM data in certificate input`))
+}
+
+func TestBatikOrderVerify(t *testing.T) {
+	tests := map[string]struct {
+		entries  []string
+		args     []string
+		exitCode int
+		stderr   string
+	}{
+		"empty": {
+			args:   []string{"order", "verify"},
+			stderr: "total order \"default\" is empty\n",
+		},
+		"committed": {
+			entries: []string{"tx0", "tx1"},
+			args:    []string{"order", "verify"},
+			stderr:  "total order \"default\" verified through seq 1 with accumulator 024c6974609f9fd37e32643964ad7bf5aef12ab5dde4ba9397ef2d3757e3010b\n",
+		},
+		"missing total order": {
+			args:     []string{"order", "--total-order=missing", "verify"},
+			exitCode: exitOrderVerifyFailed,
+			stderr:   "total order \"missing\" is not defined\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			path, cleanup := tested.TempDir(t, "", "order")
+			defer cleanup()
+
+			config := options.BatikDefaults()
+			config.DataDir = filepath.Join(path, "data")
+			configBytes, err := yaml.Marshal(config)
+			gt.Expect(err).NotTo(HaveOccurred())
+			configPath := filepath.Join(path, "batik.yaml")
+			err = ioutil.WriteFile(configPath, configBytes, 0o666)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			db, err := store.NewLevelDB(filepath.Join(path, "data", "totalorders", "default"))
+			gt.Expect(err).NotTo(HaveOccurred())
+			orderStore, err := totalorder.NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+			for _, value := range tt.entries {
+				hash := sha256.Sum256([]byte(value))
+				gt.Expect(orderStore.Append(totalorder.TXIDAndHMAC{ID: hash[:], HMAC: hash[:]})).To(Succeed())
+			}
+			tested.Close(t, db)
+
+			stdout := bytes.NewBuffer(nil)
+			stderr := bytes.NewBuffer(nil)
+			app := Batik(nil, ioutil.NopCloser(strings.NewReader("")), stdout, stderr)
+			app.ExitErrHandler = func(ctx *cli.Context, err error) {
+				fmt.Fprintf(ctx.App.ErrWriter, "%v\n", err)
+			}
+
+			err = app.Run(append([]string{"batik", "--config", configPath}, tt.args...))
+			if tt.exitCode != 0 {
+				gt.Expect(err).To(HaveOccurred())
+				gt.Expect(err.(cli.ExitCoder).ExitCode()).To(Equal(tt.exitCode))
+			} else {
+				gt.Expect(err).NotTo(HaveOccurred())
+			}
+			gt.Expect(stdout.String()).To(BeEmpty())
+			gt.Expect(stderr.String()).To(Equal(tt.stderr))
+		})
+	}
+}
+
 func TestBatikInteractive(t *testing.T) {
 	gt := NewGomegaWithT(t)
 	app := cli.NewApp()
@@ -201,6 +408,112 @@ func TestBatikInteractive(t *testing.T) {
 	}
 }
 
+func TestBatikInteractiveResume(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "resume")
+	defer cleanup()
+
+	config := options.BatikDefaults()
+	config.DataDir = filepath.Join(path, "data")
+	config.Namespaces = []options.Namespace{{Name: "ns1", HMACSecret: "secret"}}
+	configBytes, err := yaml.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configPath := filepath.Join(path, "batik.yaml")
+	err = ioutil.WriteFile(configPath, configBytes, 0o666)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	stdin := strings.NewReader("resume --namespace=ns1\nresume --namespace=missing\n")
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	app := Batik(nil, ioutil.NopCloser(stdin), stdout, stderr)
+	err = app.Run([]string{"batik", "--config", configPath})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stdout.String()).To(BeEmpty())
+	gt.Expect(stderr.String()).To(Equal("namespace \"ns1\": namespace not halted\nnamespace \"missing\" is not defined\n"))
+}
+
+func TestBatikInteractiveDBKeys(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "keys")
+	defer cleanup()
+
+	config := options.BatikDefaults()
+	config.DataDir = filepath.Join(path, "data")
+	config.Namespaces = []options.Namespace{{Name: "ns1", HMACSecret: "secret", Storage: "memory"}}
+	configBytes, err := yaml.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configPath := filepath.Join(path, "batik.yaml")
+	err = ioutil.WriteFile(configPath, configBytes, 0o666)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var commands []string
+	for _, key := range []string{"f001", "f002", "f003", "f101"} {
+		commands = append(commands, "db --namespace=ns1 put "+key+" 00")
+	}
+	commands = append(commands,
+		"db --namespace=ns1 keys --prefix=f0",
+		"db --namespace=ns1 keys --start=f002 --end=f101",
+		"db --namespace=ns1 keys --prefix=f0 --reverse --limit=2",
+		"db --namespace=ns1 keys --start=zz",
+	)
+
+	stdin := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	app := Batik(nil, ioutil.NopCloser(stdin), stdout, stderr)
+	err = app.Run([]string{"batik", "--config", configPath})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stdout.String()).To(BeEmpty())
+	gt.Expect(strings.Split(strings.TrimSpace(stderr.String()), "\n")).To(Equal([]string{
+		"f001", "f002", "f003",
+		"f002", "f003",
+		"f003", "f002",
+		"encoding/hex: invalid byte: U+007A 'z'",
+	}))
+}
+
+func TestBatikInteractiveDBReindex(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "reindex")
+	defer cleanup()
+
+	config := options.BatikDefaults()
+	config.DataDir = filepath.Join(path, "data")
+	config.Namespaces = []options.Namespace{{Name: "ns1", HMACSecret: "secret", Storage: "memory"}}
+	configBytes, err := yaml.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configPath := filepath.Join(path, "batik.yaml")
+	err = ioutil.WriteFile(configPath, configBytes, 0o666)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	commands := []string{
+		// An unconsumed state of kind "k" and a stale owner index entry.
+		"db --namespace=ns1 put 02aa0000000000000000 00",
+		"db --namespace=ns1 put 03aa0000000000000000 0a016b",
+		"db --namespace=ns1 put 0b01ffaa0000000000000001 00",
+		"db --namespace=ns1 reindex",
+		"db --namespace=ns1 keys --start=0b",
+	}
+
+	stdin := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	app := Batik(nil, ioutil.NopCloser(stdin), stdout, stderr)
+	err = app.Run([]string{"batik", "--config", configPath})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stdout.String()).To(BeEmpty())
+	gt.Expect(strings.Split(strings.TrimSpace(stderr.String()), "\n")).To(Equal([]string{
+		"indexed 1 unconsumed states",
+		"0c016baa0000000000000000",
+	}))
+}
+
 func TestBatikInteractiveWiring(t *testing.T) {
 	gt := NewGomegaWithT(t)
 	app := cli.NewApp()
@@ -210,23 +523,31 @@ func TestBatikInteractiveWiring(t *testing.T) {
 
 	t.Run("AvailableCommands", func(t *testing.T) {
 		gt := NewGomegaWithT(t)
-		gt.Expect(sa.Commands).To(HaveLen(4))
+		gt.Expect(sa.Commands).To(HaveLen(6))
 		gt.Expect(sa.Commands[0].Name).To(Equal("db"))
 		gt.Expect(sa.Commands[1].Name).To(Equal("exit"))
 		gt.Expect(sa.Commands[2].Name).To(Equal("logspec"))
-		gt.Expect(sa.Commands[3].Name).To(Equal("start"))
+		gt.Expect(sa.Commands[3].Name).To(Equal("order"))
+		gt.Expect(sa.Commands[4].Name).To(Equal("resume"))
+		gt.Expect(sa.Commands[5].Name).To(Equal("start"))
 
-		gt.Expect(sa.Commands[0].Subcommands).To(HaveLen(3))
+		gt.Expect(sa.Commands[0].Subcommands).To(HaveLen(4))
 		gt.Expect(sa.Commands[0].Subcommands[0].Name).To(Equal("get"))
-		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands).To(HaveLen(2))
-		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[0].Name).To(Equal("state"))
-		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[0].Flags).To(HaveLen(1))
-		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[0].Flags[0].Names()[0]).To(Equal("consumed"))
-		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[1].Name).To(Equal("tx"))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands).To(HaveLen(3))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[0].Name).To(Equal("rejected"))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[1].Name).To(Equal("state"))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[1].Flags).To(HaveLen(1))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[1].Flags[0].Names()[0]).To(Equal("consumed"))
+		gt.Expect(sa.Commands[0].Subcommands[0].Subcommands[2].Name).To(Equal("tx"))
 		gt.Expect(sa.Commands[0].Subcommands[1].Name).To(Equal("keys"))
-		gt.Expect(sa.Commands[0].Subcommands[1].Flags).To(HaveLen(1))
+		gt.Expect(sa.Commands[0].Subcommands[1].Flags).To(HaveLen(5))
 		gt.Expect(sa.Commands[0].Subcommands[1].Flags[0].Names()[0]).To(Equal("prefix"))
+		gt.Expect(sa.Commands[0].Subcommands[1].Flags[1].Names()[0]).To(Equal("start"))
+		gt.Expect(sa.Commands[0].Subcommands[1].Flags[2].Names()[0]).To(Equal("end"))
+		gt.Expect(sa.Commands[0].Subcommands[1].Flags[3].Names()[0]).To(Equal("limit"))
+		gt.Expect(sa.Commands[0].Subcommands[1].Flags[4].Names()[0]).To(Equal("reverse"))
 		gt.Expect(sa.Commands[0].Subcommands[2].Name).To(Equal("put"))
+		gt.Expect(sa.Commands[0].Subcommands[3].Name).To(Equal("reindex"))
 	})
 
 	t.Run("HelpTemplate", func(t *testing.T) {
@@ -236,6 +557,8 @@ func TestBatikInteractiveWiring(t *testing.T) {
 			"    db       perform operations against a kv store",
 			"    exit     exit the shell",
 			"    logspec  change the logspec of the logger leveler to any supported log level (eg. debug, info)",
+			"    order    perform operations against a total order",
+			"    resume   resume commit processing for a halted namespace",
 			"    start    start the server",
 		))
 	})
@@ -24,6 +24,8 @@ const (
 	levelerKey
 	serverKey
 	namespacesKey
+	totalOrdersKey
+	namespaceManagerKey
 )
 
 // GetLogger retrieves a zap.Logger from the *cli.Context if one exists.
@@ -69,14 +71,14 @@ func SetLeveler(ctx *cli.Context, leveler zapcore.LevelEnabler) {
 	setOnCtx(ctx, levelerKey, leveler)
 }
 
-// GetNamespaces retrieves the namespaces map from the *cli.Context if one exists.
-func GetNamespaces(ctx *cli.Context) map[string]*namespace.Namespace {
+// GetNamespaces retrieves the namespace registry from the *cli.Context if one exists.
+func GetNamespaces(ctx *cli.Context) *namespace.Registry {
 	val := retrieveFromCtx(ctx, namespacesKey)
 	if val == nil {
 		return nil
 	}
 
-	namespaces, ok := val.(map[string]*namespace.Namespace)
+	namespaces, ok := val.(*namespace.Registry)
 	if !ok {
 		return nil
 	}
@@ -84,11 +86,51 @@ func GetNamespaces(ctx *cli.Context) map[string]*namespace.Namespace {
 	return namespaces
 }
 
-// SetNamespaces stores a map of namespaces on the *cli.Context.
-func SetNamespaces(ctx *cli.Context, namespaces map[string]*namespace.Namespace) {
+// SetNamespaces stores a namespace registry on the *cli.Context.
+func SetNamespaces(ctx *cli.Context, namespaces *namespace.Registry) {
 	setOnCtx(ctx, namespacesKey, namespaces)
 }
 
+// GetNamespaceManager retrieves the NamespaceManager from the *cli.Context if one exists.
+func GetNamespaceManager(ctx *cli.Context) *NamespaceManager {
+	val := retrieveFromCtx(ctx, namespaceManagerKey)
+	if val == nil {
+		return nil
+	}
+
+	manager, ok := val.(*NamespaceManager)
+	if !ok {
+		return nil
+	}
+
+	return manager
+}
+
+// SetNamespaceManager stores a NamespaceManager on the *cli.Context.
+func SetNamespaceManager(ctx *cli.Context, manager *NamespaceManager) {
+	setOnCtx(ctx, namespaceManagerKey, manager)
+}
+
+// GetTotalOrders retrieves the total orders map from the *cli.Context if one exists.
+func GetTotalOrders(ctx *cli.Context) map[string]namespace.TotalOrder {
+	val := retrieveFromCtx(ctx, totalOrdersKey)
+	if val == nil {
+		return nil
+	}
+
+	totalOrders, ok := val.(map[string]namespace.TotalOrder)
+	if !ok {
+		return nil
+	}
+
+	return totalOrders
+}
+
+// SetTotalOrders stores a map of total orders on the *cli.Context.
+func SetTotalOrders(ctx *cli.Context, totalOrders map[string]namespace.TotalOrder) {
+	setOnCtx(ctx, totalOrdersKey, totalOrders)
+}
+
 func GetCurrentNamespace(ctx *cli.Context) (*namespace.Namespace, error) {
 	namespaces := GetNamespaces(ctx)
 	if namespaces == nil {
@@ -100,7 +142,7 @@ func GetCurrentNamespace(ctx *cli.Context) (*namespace.Namespace, error) {
 		return nil, errors.Errorf("target namespace is not set")
 	}
 
-	ns, ok := namespaces[namespaceName]
+	ns, ok := namespaces.Lookup(namespaceName)
 	if !ok {
 		return nil, errors.Errorf("namespace %q is not defined", namespaceName)
 	}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/sykesm/batik/pkg/log"
 	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/totalorder"
 )
 
 func TestContext_Logger(t *testing.T) {
@@ -97,14 +98,15 @@ func TestContext_Namespaces(t *testing.T) {
 	gt.Expect(err).To(MatchError("could not find namespaces from context"))
 
 	configNSS := map[string]*namespace.Namespace{
-		"ns1": {},
-		"ns2": {},
+		"ns1": {Name: "ns1"},
+		"ns2": {Name: "ns2"},
 	}
+	registry := namespace.NewRegistry(configNSS)
 
-	SetNamespaces(ctx, configNSS)
+	SetNamespaces(ctx, registry)
 
 	nss = GetNamespaces(ctx)
-	gt.Expect(nss).To(Equal(configNSS))
+	gt.Expect(nss).To(BeIdenticalTo(registry))
 
 	_, err = GetCurrentNamespace(ctx)
 	gt.Expect(err).To(MatchError("target namespace is not set"))
@@ -120,5 +122,29 @@ func TestContext_Namespaces(t *testing.T) {
 
 	ns, err := GetCurrentNamespace(ctx)
 	gt.Expect(err).NotTo(HaveOccurred())
-	gt.Expect(ns).To(Equal(configNSS["ns1"]))
+	gt.Expect(ns).To(BeIdenticalTo(configNSS["ns1"]))
+}
+
+func TestContext_NamespaceManager(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	ctx := cli.NewContext(cli.NewApp(), nil, nil)
+	gt.Expect(GetNamespaceManager(ctx)).To(BeNil())
+
+	manager := &NamespaceManager{}
+	SetNamespaceManager(ctx, manager)
+	gt.Expect(GetNamespaceManager(ctx)).To(BeIdenticalTo(manager))
+}
+
+func TestContext_TotalOrders(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	ctx := cli.NewContext(cli.NewApp(), nil, nil)
+	gt.Expect(GetTotalOrders(ctx)).To(BeNil())
+
+	totalOrders := map[string]namespace.TotalOrder{
+		"order1": &totalorder.InProcess{},
+	}
+	SetTotalOrders(ctx, totalOrders)
+	gt.Expect(GetTotalOrders(ctx)).To(Equal(totalOrders))
 }
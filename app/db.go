@@ -12,6 +12,7 @@ import (
 	"github.com/hokaccha/go-prettyjson"
 	cli "github.com/urfave/cli/v2"
 	"github.com/sykesm/batik/pkg/options"
+	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
@@ -30,6 +31,7 @@ func dbCommand(config *options.Batik) *cli.Command {
 			getSubcommand(),
 			keysSubcommand(),
 			putSubcommand(),
+			reindexSubcommand(),
 		},
 	}
 
@@ -45,6 +47,7 @@ func getSubcommand() *cli.Command {
 		Subcommands: []*cli.Command{
 			getTransactionSubcommand(),
 			getStateSubcommand(),
+			getRejectedSubcommand(),
 		},
 		Action: func(ctx *cli.Context) error {
 			ns, err := GetCurrentNamespace(ctx)
@@ -59,7 +62,7 @@ func getSubcommand() *cli.Command {
 				return nil
 			}
 
-			val, err := ns.LevelDB.Get(key)
+			val, err := ns.KV.Get(key)
 			if err != nil {
 				fmt.Fprintln(ctx.App.ErrWriter, err)
 				return nil
@@ -160,16 +163,69 @@ func getStateSubcommand() *cli.Command {
 	}
 }
 
+func getRejectedSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:  "rejected",
+		Usage: "get the rejection record of a transaction from the db",
+		Action: func(ctx *cli.Context) error {
+			ns, err := GetCurrentNamespace(ctx)
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			txID, err := hex.DecodeString(ctx.Args().First())
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			val, err := ns.Repo.GetRejected(txID)
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			jsonOut, err := prettyjson.Marshal(val)
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			fmt.Fprintln(ctx.App.ErrWriter, string(jsonOut))
+			return nil
+		},
+	}
+}
+
 func keysSubcommand() *cli.Command {
 	return &cli.Command{
 		Name:  "keys",
-		Usage: "dump all keys in the db",
+		Usage: "dump the keys in the db",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "prefix",
 				Usage:       "prefix to range over",
 				DefaultText: "",
 			},
+			&cli.StringFlag{
+				Name:        "start",
+				Usage:       "inclusive lower bound of the keys",
+				DefaultText: "",
+			},
+			&cli.StringFlag{
+				Name:        "end",
+				Usage:       "exclusive upper bound of the keys",
+				DefaultText: "",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "maximum number of keys to dump, 0 for all keys",
+			},
+			&cli.BoolFlag{
+				Name:  "reverse",
+				Usage: "dump the keys in descending order",
+			},
 		},
 		Action: func(ctx *cli.Context) error {
 			ns, err := GetCurrentNamespace(ctx)
@@ -178,21 +234,27 @@ func keysSubcommand() *cli.Command {
 				return nil
 			}
 
-			prefix, err := hex.DecodeString(ctx.String("prefix"))
-			if err != nil {
-				fmt.Fprintln(ctx.App.ErrWriter, err)
-				return nil
+			opts := &store.IterateOptions{
+				Limit:   ctx.Int("limit"),
+				Reverse: ctx.Bool("reverse"),
 			}
-
-			iter := ns.LevelDB.NewIterator(prefix, nil)
-			keys, err := iter.Keys()
-			if err != nil {
-				fmt.Fprintln(ctx.App.ErrWriter, err)
-				return nil
+			for name, bound := range map[string]*[]byte{"prefix": &opts.Prefix, "start": &opts.Start, "end": &opts.End} {
+				if ctx.String(name) == "" {
+					continue
+				}
+				if *bound, err = hex.DecodeString(ctx.String(name)); err != nil {
+					fmt.Fprintln(ctx.App.ErrWriter, err)
+					return nil
+				}
 			}
 
-			for _, k := range keys {
-				fmt.Fprintln(ctx.App.ErrWriter, hex.EncodeToString(k))
+			iter := ns.KV.NewIterator(opts)
+			defer iter.Release()
+			for iter.Next() {
+				fmt.Fprintln(ctx.App.ErrWriter, hex.EncodeToString(iter.Key()))
+			}
+			if err := iter.Error(); err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
 			}
 			return nil
 		},
@@ -221,7 +283,7 @@ func putSubcommand() *cli.Command {
 				return nil
 			}
 
-			if err := ns.LevelDB.Put(key, val); err != nil {
+			if err := ns.KV.Put(key, val); err != nil {
 				fmt.Fprintln(ctx.App.ErrWriter, err)
 			}
 
@@ -229,3 +291,32 @@ func putSubcommand() *cli.Command {
 		},
 	}
 }
+
+func reindexSubcommand() *cli.Command {
+	return &cli.Command{
+		Name:  "reindex",
+		Usage: "rebuild the owner and kind indexes of unconsumed states",
+		Action: func(ctx *cli.Context) error {
+			ns, err := GetCurrentNamespace(ctx)
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			// The indexes can not be rebuilt while transactions are committed.
+			if ns.Started() {
+				fmt.Fprintf(ctx.App.ErrWriter, "namespace %q is started, the indexes can only be rebuilt when it is not serving\n", ns.Name)
+				return nil
+			}
+
+			count, err := store.NewRepository(ns.KV).RebuildIndexes()
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			fmt.Fprintf(ctx.App.ErrWriter, "indexed %d unconsumed states\n", count)
+			return nil
+		},
+	}
+}
@@ -16,4 +16,5 @@ const (
 	exitAppShutdownFailed
 	exitChangeLogspecFailed
 	exitConfigEncodeFailed
+	exitOrderVerifyFailed
 )
@@ -0,0 +1,383 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sykesm/batik/pkg/conf"
+	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/options"
+)
+
+// NamespaceManager creates and removes namespaces while the process is
+// running. Namespaces are added to and removed from the registry that routes
+// requests so changes take effect immediately. Changes can optionally be
+// written back to the configuration file so they survive a restart.
+type NamespaceManager struct {
+	logger      *zap.Logger
+	config      *options.Batik
+	configPath  string
+	registry    *namespace.Registry
+	validators  map[string]namespace.Validator
+	totalOrders map[string]namespace.TotalOrder
+	identity    *ecdsa.PrivateKey
+
+	mutex sync.Mutex
+}
+
+// NewNamespaceManager creates a NamespaceManager for the namespaces in the
+// registry. The namespaces in the configuration must be the namespaces in
+// the registry. When configPath is empty, changes can not be persisted. The
+// identity key, when not nil, attests commits of the namespaces it creates.
+func NewNamespaceManager(
+	logger *zap.Logger,
+	config *options.Batik,
+	configPath string,
+	registry *namespace.Registry,
+	validators map[string]namespace.Validator,
+	totalOrders map[string]namespace.TotalOrder,
+	identity *ecdsa.PrivateKey,
+) *NamespaceManager {
+	return &NamespaceManager{
+		logger:      logger,
+		config:      config,
+		configPath:  configPath,
+		registry:    registry,
+		validators:  validators,
+		totalOrders: totalOrders,
+		identity:    identity,
+	}
+}
+
+// CreateNamespace opens the database of a new namespace, starts commit
+// processing, and begins routing requests to it. Missing configuration
+// fields are assigned their defaults and relative paths are resolved against
+// the directory of the configuration file. The configuration of the new
+// namespace is returned.
+func (m *NamespaceManager) CreateNamespace(config options.Namespace, persist bool) (options.Namespace, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if config.Name == "" {
+		return options.Namespace{}, errors.WithMessage(namespace.ErrInvalidNamespace, "name is required")
+	}
+	if _, ok := m.registry.Lookup(config.Name); ok {
+		return options.Namespace{}, errors.WithMessagef(namespace.ErrNamespaceExists, "namespace %q", config.Name)
+	}
+	if persist && m.configPath == "" {
+		return options.Namespace{}, errors.New("no configuration file to update")
+	}
+
+	// The configuration is persisted as provided so defaults continue to
+	// track the configuration file.
+	fileConfig := config
+
+	config.ApplyDefaults(m.config.DataDir)
+	tr := &conf.TagResolver{SourcePath: m.sourcePath()}
+	if err := tr.Resolve(&config); err != nil {
+		return options.Namespace{}, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q: %s", config.Name, err)
+	}
+
+	ns, err := newNamespace(m.logger, config, m.validators, m.totalOrders, m.identity)
+	if err != nil {
+		return options.Namespace{}, err
+	}
+
+	if persist {
+		err := updateConfigFile(m.configPath, func(namespaces *yaml.Node) error {
+			var node yaml.Node
+			if err := node.Encode(fileConfig); err != nil {
+				return err
+			}
+			namespaces.Content = append(namespaces.Content, &node)
+			return nil
+		})
+		if err != nil {
+			if cerr := ns.Close(); cerr != nil {
+				m.logger.Warn("failed to close namespace", zap.String("namespace", config.Name), zap.Error(cerr))
+			}
+			return options.Namespace{}, errors.WithMessage(err, "failed to update configuration file")
+		}
+	}
+
+	if err := m.registry.Add(ns); err != nil {
+		return options.Namespace{}, err
+	}
+	ns.Start()
+	m.config.Namespaces = append(m.config.Namespaces, config)
+
+	m.logger.Info("created namespace", zap.String("namespace", config.Name), zap.String("data_dir", config.DataDir))
+	return config, nil
+}
+
+// RemoveNamespace stops routing requests to a namespace, stops its commit
+// processing, and closes its database. Submitters waiting for their
+// transactions are failed and requests in flight finish before the database
+// is closed. The data in the database is not deleted.
+func (m *NamespaceManager) RemoveNamespace(name string, persist bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if persist && m.configPath == "" {
+		return errors.New("no configuration file to update")
+	}
+
+	ns, err := m.registry.Remove(name)
+	if err != nil {
+		return err
+	}
+	for i, config := range m.config.Namespaces {
+		if config.Name == name {
+			m.config.Namespaces = append(m.config.Namespaces[:i], m.config.Namespaces[i+1:]...)
+			break
+		}
+	}
+
+	if err := ns.Close(); err != nil {
+		return errors.WithMessagef(err, "failed to close namespace %q", name)
+	}
+
+	if persist {
+		err := updateConfigFile(m.configPath, func(namespaces *yaml.Node) error {
+			var content []*yaml.Node
+			for _, node := range namespaces.Content {
+				var config options.Namespace
+				if err := node.Decode(&config); err != nil {
+					return err
+				}
+				if config.Name != name {
+					content = append(content, node)
+				}
+			}
+			namespaces.Content = content
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessage(err, "failed to update configuration file")
+		}
+	}
+
+	m.logger.Info("removed namespace", zap.String("namespace", name))
+	return nil
+}
+
+// ListNamespaces returns the configuration of the active namespaces sorted
+// by name.
+func (m *NamespaceManager) ListNamespaces() []options.Namespace {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	namespaces := append([]options.Namespace(nil), m.config.Namespaces...)
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces
+}
+
+// DescribeNamespace returns the configuration of a namespace and the
+// sequence number of the next ordered entry it will process.
+func (m *NamespaceManager) DescribeNamespace(name string) (options.Namespace, uint64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ns, ok := m.registry.Lookup(name)
+	if !ok {
+		return options.Namespace{}, 0, errors.WithMessagef(namespace.ErrNamespaceNotFound, "namespace %q", name)
+	}
+	nextSeq, err := ns.NextSeq()
+	if err != nil {
+		return options.Namespace{}, 0, errors.WithMessagef(err, "failed to determine the next sequence of namespace %q", name)
+	}
+
+	for _, config := range m.config.Namespaces {
+		if config.Name == name {
+			return config, nextSeq, nil
+		}
+	}
+	return options.Namespace{Name: name}, nextSeq, nil
+}
+
+// NamespaceHealth returns the health of an active namespace.
+func (m *NamespaceManager) NamespaceHealth(name string) (namespace.Health, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ns, ok := m.registry.Lookup(name)
+	if !ok {
+		return namespace.Health{}, errors.WithMessagef(namespace.ErrNamespaceNotFound, "namespace %q", name)
+	}
+	return ns.Health(), nil
+}
+
+// ResumeNamespace restarts commit processing for a halted namespace.
+func (m *NamespaceManager) ResumeNamespace(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ns, ok := m.registry.Lookup(name)
+	if !ok {
+		return errors.WithMessagef(namespace.ErrNamespaceNotFound, "namespace %q", name)
+	}
+	if err := ns.Resume(); err != nil {
+		return err
+	}
+
+	m.logger.Info("resumed namespace", zap.String("namespace", name))
+	return nil
+}
+
+func (m *NamespaceManager) sourcePath() string {
+	if m.configPath == "" {
+		return ""
+	}
+	return filepath.Dir(m.configPath)
+}
+
+// newNamespace opens the database of a namespace and creates it. The
+// namespace is not started.
+func newNamespace(logger *zap.Logger, config options.Namespace, validators map[string]namespace.Validator, totalOrders map[string]namespace.TotalOrder, identity *ecdsa.PrivateKey) (*namespace.Namespace, error) {
+	namespaceLogger := logger.With(zap.String("namespace", config.Name))
+
+	if config.Validator != "" && len(config.Validators) != 0 {
+		return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q must not specify both validator and validators", config.Name)
+	}
+	var chain namespace.ValidatorChain
+	for _, name := range config.ValidatorNames() {
+		v, ok := validators[name]
+		if !ok {
+			return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q requires validator %q which is not defined", config.Name, name)
+		}
+		chain = append(chain, namespace.NamedValidator{Name: name, Validator: v})
+	}
+	if len(chain) == 0 {
+		return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q does not specify a validator", config.Name)
+	}
+
+	kinds := namespace.KindValidators{}
+	for _, kv := range config.KindValidators {
+		if _, ok := kinds[kv.Kind]; ok {
+			return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q maps kind %q more than once", config.Name, kv.Kind)
+		}
+		if len(kv.Validators) == 0 {
+			return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q does not specify a validator for kind %q", config.Name, kv.Kind)
+		}
+		var kindChain namespace.ValidatorChain
+		for _, name := range kv.Validators {
+			v, ok := validators[name]
+			if !ok {
+				return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q requires validator %q for kind %q which is not defined", config.Name, name, kv.Kind)
+			}
+			kindChain = append(kindChain, namespace.NamedValidator{Name: name, Validator: v})
+		}
+		kinds[kv.Kind] = kindChain
+	}
+
+	to, ok := totalOrders[config.TotalOrder]
+	if !ok {
+		return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q requires total order %q which is not defined", config.Name, config.TotalOrder)
+	}
+
+	openDB, ok := storageEngines[config.Storage]
+	if !ok {
+		return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q has unknown storage %q, must be \"leveldb\", \"bbolt\", or \"memory\"", config.Name, config.Storage)
+	}
+
+	if config.HMACSecret == "" {
+		return nil, errors.WithMessagef(namespace.ErrInvalidNamespace, "namespace %q does not specify an hmac secret", config.Name)
+	}
+
+	namespaceLogger.Debug("initializing namespace database", zap.String("storage", config.Storage), zap.String("data_dir", config.DataDir))
+	db, err := openDB(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespace.New(config.Name, namespaceLogger, crypto.SHA256, db, chain, kinds, config.ValidationWorkers, to, []byte(config.HMACSecret), identity), nil
+}
+
+// updateConfigFile applies an update to the namespaces sequence of a YAML
+// configuration file. The rest of the document is preserved and the file is
+// replaced atomically.
+func updateConfigFile(path string, update func(namespaces *yaml.Node) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return errors.Errorf("configuration file %s does not contain a mapping", path)
+	}
+
+	var namespaces *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "namespaces" {
+			namespaces = root.Content[i+1]
+			break
+		}
+	}
+	if namespaces == nil {
+		namespaces = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "namespaces"}, namespaces)
+	}
+	if namespaces.Kind == yaml.ScalarNode && namespaces.Tag == "!!null" {
+		*namespaces = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	if namespaces.Kind != yaml.SequenceNode {
+		return errors.Errorf("namespaces in configuration file %s is not a sequence", path)
+	}
+
+	if err := update(namespaces); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,289 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/options"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
+	"github.com/sykesm/batik/pkg/validator"
+)
+
+func newTestNamespaceManager(t *testing.T, dir, configPath string) (*NamespaceManager, *namespace.Registry) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	orderStore, err := totalorder.NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	order := totalorder.NewInProcess(orderStore)
+	t.Cleanup(func() {
+		order.Stop()
+		tested.Close(t, db)
+	})
+
+	config := options.BatikDefaults()
+	config.DataDir = dir
+	registry := namespace.NewRegistry(nil)
+	manager := NewNamespaceManager(
+		zap.NewNop(),
+		config,
+		configPath,
+		registry,
+		map[string]namespace.Validator{"signature-builtin": validator.NewSignature()},
+		map[string]namespace.TotalOrder{"default": order},
+		nil,
+	)
+	t.Cleanup(func() {
+		for _, name := range registry.Names() {
+			manager.RemoveNamespace(name, false)
+		}
+	})
+	return manager, registry
+}
+
+func TestNamespaceManager(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	dir, cleanup := tested.TempDir(t, "", "namespaces")
+	defer cleanup()
+
+	manager, registry := newTestNamespaceManager(t, dir, "")
+
+	created, err := manager.CreateNamespace(options.Namespace{Name: "ns1", HMACSecret: "secret"}, false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(created.DataDir).To(Equal(filepath.Join(dir, "namespaces", "ns1")))
+	gt.Expect(created.Validator).To(Equal("signature-builtin"))
+	gt.Expect(created.TotalOrder).To(Equal("default"))
+
+	ns, ok := registry.Lookup("ns1")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(ns.Name).To(Equal("ns1"))
+	gt.Expect(ns.Started()).To(BeTrue())
+
+	chained, err := manager.CreateNamespace(options.Namespace{
+		Name:           "ns0",
+		HMACSecret:     "secret",
+		DataDir:        filepath.Join(dir, "other"),
+		Storage:        "memory",
+		Validators:     []string{"signature-builtin", "signature-builtin"},
+		KindValidators: []options.KindValidators{{Kind: "token", Validators: []string{"signature-builtin"}}},
+	}, false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(chained.Validator).To(BeEmpty())
+	gt.Expect(chained.ValidatorNames()).To(Equal([]string{"signature-builtin", "signature-builtin"}))
+
+	// In-memory namespaces do not use the data directory.
+	ns, ok = registry.Lookup("ns0")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(ns.KV).To(BeAssignableToTypeOf(&store.MemoryKV{}))
+	gt.Expect(filepath.Join(dir, "other")).NotTo(BeADirectory())
+
+	namespaces := manager.ListNamespaces()
+	gt.Expect(namespaces).To(HaveLen(2))
+	gt.Expect(namespaces[0].Name).To(Equal("ns0"))
+	gt.Expect(namespaces[1]).To(Equal(created))
+
+	config, nextSeq, err := manager.DescribeNamespace("ns1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(config).To(Equal(created))
+	gt.Expect(nextSeq).To(Equal(uint64(0)))
+
+	health, err := manager.NamespaceHealth("ns1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(health).To(Equal(namespace.Health{}))
+	err = manager.ResumeNamespace("ns1")
+	gt.Expect(err).To(MatchError(namespace.ErrNamespaceNotHalted))
+
+	err = manager.RemoveNamespace("ns1", false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, ok = registry.Lookup("ns1")
+	gt.Expect(ok).To(BeFalse())
+	gt.Expect(manager.ListNamespaces()).To(HaveLen(1))
+
+	_, _, err = manager.DescribeNamespace("ns1")
+	gt.Expect(err).To(MatchError(namespace.ErrNamespaceNotFound))
+	err = manager.RemoveNamespace("ns1", false)
+	gt.Expect(err).To(MatchError(namespace.ErrNamespaceNotFound))
+	_, err = manager.NamespaceHealth("ns1")
+	gt.Expect(err).To(MatchError(namespace.ErrNamespaceNotFound))
+	err = manager.ResumeNamespace("ns1")
+	gt.Expect(err).To(MatchError(namespace.ErrNamespaceNotFound))
+
+	// The database was closed so the namespace can be opened again.
+	_, err = manager.CreateNamespace(options.Namespace{Name: "ns1", HMACSecret: "secret"}, false)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestNamespaceManagerStorage(t *testing.T) {
+	tests := map[string]struct {
+		storage  string
+		expected store.KV
+		dataDir  types.GomegaMatcher
+	}{
+		"leveldb": {storage: "leveldb", expected: &store.LevelDBKV{}, dataDir: BeADirectory()},
+		"bbolt":   {storage: "bbolt", expected: &store.BoltKV{}, dataDir: BeADirectory()},
+		"memory":  {storage: "memory", expected: &store.MemoryKV{}, dataDir: Not(BeADirectory())},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			dir, cleanup := tested.TempDir(t, "", "namespaces")
+			defer cleanup()
+
+			manager, registry := newTestNamespaceManager(t, dir, "")
+			created, err := manager.CreateNamespace(options.Namespace{Name: "ns", HMACSecret: "secret", Storage: tt.storage}, false)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(created.Storage).To(Equal(tt.storage))
+
+			ns, ok := registry.Lookup("ns")
+			gt.Expect(ok).To(BeTrue())
+			gt.Expect(ns.KV).To(BeAssignableToTypeOf(tt.expected))
+			gt.Expect(created.DataDir).To(tt.dataDir)
+		})
+	}
+}
+
+func TestNamespaceManagerErrors(t *testing.T) {
+	dir, cleanup := tested.TempDir(t, "", "namespaces")
+	defer cleanup()
+
+	tests := map[string]struct {
+		config   options.Namespace
+		persist  bool
+		matchErr interface{}
+	}{
+		"missing name":           {config: options.Namespace{}, matchErr: namespace.ErrInvalidNamespace},
+		"duplicate":              {config: options.Namespace{Name: "existing"}, matchErr: namespace.ErrNamespaceExists},
+		"unknown validator":      {config: options.Namespace{Name: "ns", HMACSecret: "secret", Validator: "missing"}, matchErr: namespace.ErrInvalidNamespace},
+		"unknown chained":        {config: options.Namespace{Name: "ns", HMACSecret: "secret", Validators: []string{"signature-builtin", "missing"}}, matchErr: namespace.ErrInvalidNamespace},
+		"both validators":        {config: options.Namespace{Name: "ns", HMACSecret: "secret", Validator: "signature-builtin", Validators: []string{"signature-builtin"}}, matchErr: namespace.ErrInvalidNamespace},
+		"unknown kind validator": {config: options.Namespace{Name: "ns", HMACSecret: "secret", KindValidators: []options.KindValidators{{Kind: "token", Validators: []string{"missing"}}}}, matchErr: namespace.ErrInvalidNamespace},
+		"empty kind validators":  {config: options.Namespace{Name: "ns", HMACSecret: "secret", KindValidators: []options.KindValidators{{Kind: "token"}}}, matchErr: namespace.ErrInvalidNamespace},
+		"duplicate kind": {
+			config: options.Namespace{Name: "ns", HMACSecret: "secret", KindValidators: []options.KindValidators{
+				{Kind: "token", Validators: []string{"signature-builtin"}},
+				{Kind: "token", Validators: []string{"signature-builtin"}},
+			}},
+			matchErr: namespace.ErrInvalidNamespace,
+		},
+		"unknown total order": {config: options.Namespace{Name: "ns", HMACSecret: "secret", TotalOrder: "missing"}, matchErr: namespace.ErrInvalidNamespace},
+		"unknown storage":     {config: options.Namespace{Name: "ns", HMACSecret: "secret", Storage: "missing"}, matchErr: namespace.ErrInvalidNamespace},
+		"missing hmac secret": {config: options.Namespace{Name: "ns"}, matchErr: namespace.ErrInvalidNamespace},
+		"no config file":      {config: options.Namespace{Name: "ns"}, persist: true, matchErr: "no configuration file to update"},
+	}
+
+	manager, registry := newTestNamespaceManager(t, dir, "")
+	_, err := manager.CreateNamespace(options.Namespace{Name: "existing", HMACSecret: "secret"}, false)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			_, err := manager.CreateNamespace(tt.config, tt.persist)
+			gt.Expect(err).To(MatchError(tt.matchErr))
+			gt.Expect(registry.Names()).To(Equal([]string{"existing"}))
+		})
+	}
+
+	err = manager.RemoveNamespace("existing", true)
+	NewGomegaWithT(t).Expect(err).To(MatchError("no configuration file to update"))
+}
+
+func TestNamespaceManagerPersist(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	dir, cleanup := tested.TempDir(t, "", "namespaces")
+	defer cleanup()
+
+	configPath := filepath.Join(dir, "batik.yaml")
+	err := ioutil.WriteFile(configPath, []byte("# batik configuration\ndata_dir: data\nnamespaces:\n  - name: existing\n"), 0o600)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	readNamespaces := func() []options.Namespace {
+		data, err := ioutil.ReadFile(configPath)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(string(data)).To(HavePrefix("# batik configuration\ndata_dir: data\n"))
+
+		var config options.Batik
+		gt.Expect(yaml.Unmarshal(data, &config)).To(Succeed())
+		return config.Namespaces
+	}
+
+	manager, _ := newTestNamespaceManager(t, dir, configPath)
+
+	created, err := manager.CreateNamespace(options.Namespace{Name: "ns1", DataDir: "ns1-data", HMACSecret: "secret"}, true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(created.DataDir).To(Equal(filepath.Join(dir, "ns1-data")))
+	gt.Expect(readNamespaces()).To(Equal([]options.Namespace{
+		{Name: "existing"},
+		{Name: "ns1", DataDir: "ns1-data", HMACSecret: "secret"},
+	}))
+
+	err = manager.RemoveNamespace("ns1", true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(readNamespaces()).To(Equal([]options.Namespace{{Name: "existing"}}))
+}
+
+func TestUpdateConfigFile(t *testing.T) {
+	dir, cleanup := tested.TempDir(t, "", "namespaces")
+	defer cleanup()
+
+	appendNamespace := func(namespaces *yaml.Node) error {
+		var node yaml.Node
+		if err := node.Encode(options.Namespace{Name: "ns"}); err != nil {
+			return err
+		}
+		namespaces.Content = append(namespaces.Content, &node)
+		return nil
+	}
+
+	tests := map[string]struct {
+		contents string
+		expected string
+		errMatch string
+	}{
+		"empty file":       {contents: "", expected: "namespaces:\n  - name: ns\n"},
+		"no namespaces":    {contents: "data_dir: data\n", expected: "data_dir: data\nnamespaces:\n  - name: ns\n"},
+		"null namespaces":  {contents: "namespaces:\n", expected: "namespaces:\n  - name: ns\n"},
+		"scalar namespace": {contents: "namespaces: ns\n", errMatch: "namespaces in configuration file .* is not a sequence"},
+		"not a mapping":    {contents: "- ns\n", errMatch: "configuration file .* does not contain a mapping"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			path := filepath.Join(dir, name+".yaml")
+			err := ioutil.WriteFile(path, []byte(tt.contents), 0o600)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			err = updateConfigFile(path, appendNamespace)
+			if tt.errMatch != "" {
+				gt.Expect(err).To(MatchError(MatchRegexp(tt.errMatch)))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			data, err := ioutil.ReadFile(path)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(string(data)).To(Equal(tt.expected))
+		})
+	}
+}
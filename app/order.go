@@ -0,0 +1,113 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/sykesm/batik/pkg/options"
+	"github.com/sykesm/batik/pkg/totalorder"
+)
+
+func orderCommand() *cli.Command {
+	command := &cli.Command{
+		Name:  "order",
+		Usage: "perform operations against a total order",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "total-order",
+				Usage: "target total order for the subcommand",
+				Value: "default",
+			},
+		},
+		Subcommands: []*cli.Command{
+			verifySubcommand(),
+		},
+	}
+
+	sort.Sort(cli.CommandsByName(command.Subcommands))
+
+	return command
+}
+
+type verifier interface {
+	totalorder.Checkpointer
+	Verify() (*totalorder.Checkpoint, error)
+}
+
+func verifySubcommand() *cli.Command {
+	return &cli.Command{
+		Name:        "verify",
+		Usage:       "verify the hash chain of a total order",
+		Description: "Replay the local log of a total order to detect tampering and, optionally, compare it with the log of a peer to detect divergence.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "peer-address",
+				Usage: "address of a peer to compare the total order with",
+			},
+			&cli.StringFlag{
+				Name:      "peer-root-ca-file",
+				Usage:     "file containing the PEM encoded certificates trusted to verify the peer",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:        "peer-total-order",
+				Usage:       "name of the total order on the peer",
+				DefaultText: "the value of --total-order",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			name := ctx.String("total-order")
+			to, ok := GetTotalOrders(ctx)[name]
+			if !ok {
+				return cli.Exit(errors.Errorf("total order %q is not defined", name), exitOrderVerifyFailed)
+			}
+			local, ok := to.(verifier)
+			if !ok {
+				return cli.Exit(errors.Errorf("total order %q is not stored by this node", name), exitOrderVerifyFailed)
+			}
+
+			checkpoint, err := local.Verify()
+			if err != nil {
+				return cli.Exit(errors.WithMessagef(err, "total order %q failed verification", name), exitOrderVerifyFailed)
+			}
+			if checkpoint == nil {
+				fmt.Fprintf(ctx.App.ErrWriter, "total order %q is empty\n", name)
+				return nil
+			}
+			fmt.Fprintf(ctx.App.ErrWriter, "total order %q verified through seq %d with accumulator %x\n", name, checkpoint.Seq, checkpoint.Accumulator)
+
+			address := ctx.String("peer-address")
+			if address == "" {
+				return nil
+			}
+
+			peerName := ctx.String("peer-total-order")
+			if peerName == "" {
+				peerName = name
+			}
+			conn, err := dialGRPCTotalOrder(options.GRPCTotalOrder{
+				Address:    address,
+				TotalOrder: peerName,
+				RootCAFile: ctx.String("peer-root-ca-file"),
+			})
+			if err != nil {
+				return cli.Exit(errors.WithMessagef(err, "could not connect to peer %s", address), exitOrderVerifyFailed)
+			}
+			defer conn.Close()
+
+			common, err := totalorder.Compare(ctx.Context, local, totalorder.NewGRPC(conn, peerName))
+			if err != nil {
+				return cli.Exit(errors.WithMessagef(err, "total order %q does not match peer %s", name, address), exitOrderVerifyFailed)
+			}
+			fmt.Fprintf(ctx.App.ErrWriter, "total order %q matches peer %s through seq %d\n", name, address, common.Seq)
+
+			return nil
+		},
+	}
+}
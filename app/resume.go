@@ -0,0 +1,46 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func resumeCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "resume",
+		Usage:       "resume commit processing for a halted namespace",
+		Description: "Restart commit processing for a namespace that halted after a failure. The ordered entry that caused the halt is processed again so the cause should be fixed first.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "namespace",
+				Usage:    "target namespace for the command",
+				Required: true,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			ns, err := GetCurrentNamespace(ctx)
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			health := ns.Health()
+			if manager := GetNamespaceManager(ctx); manager != nil {
+				err = manager.ResumeNamespace(ns.Name)
+			} else {
+				err = ns.Resume()
+			}
+			if err != nil {
+				fmt.Fprintln(ctx.App.ErrWriter, err)
+				return nil
+			}
+
+			fmt.Fprintf(ctx.App.ErrWriter, "namespace %q resumed at seq %d after halting: %s\n", ns.Name, health.Seq, health.Cause)
+			return nil
+		},
+	}
+}
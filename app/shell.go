@@ -44,6 +44,8 @@ func shellApp(parentCtx *cli.Context, config *options.Batik) (*cli.App, error) {
 		dbCommand(config),
 		exitCommand(),
 		logspecCommand(),
+		orderCommand(),
+		resumeCommand(),
 		startCommand(config, true),
 	}
 
@@ -21,7 +21,10 @@ import (
 	"github.com/sykesm/batik/pkg/grpcapi"
 	"github.com/sykesm/batik/pkg/grpccomm"
 	"github.com/sykesm/batik/pkg/grpclogging"
+	"github.com/sykesm/batik/pkg/namespace"
 	"github.com/sykesm/batik/pkg/options"
+	adminv1 "github.com/sykesm/batik/pkg/pb/admin/v1"
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
 	storev1 "github.com/sykesm/batik/pkg/pb/store/v1"
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
 )
@@ -74,37 +77,105 @@ func startAction(ctx *cli.Context, config *options.Batik, interactive bool) erro
 		return cli.Exit(errors.WithMessage(err, "failed to create server"), exitServerCreateFailed)
 	}
 
-	grpcServerOptions = append(grpcServerOptions, grpc.Creds(credentials.NewTLS(tlsConf)))
+	// The admin API is served on a separate listener that is either only
+	// reachable from the local host or requires client certificates.
+	adminTLSConf, err := config.Server.Admin.TLSConfig(tlsConf)
+	if err != nil {
+		return cli.Exit(errors.WithMessage(err, "failed to create admin server"), exitServerCreateFailed)
+	}
+
+	// The raft peer API is only served when a raft total order has members
+	// hosted by other nodes. It is served on a separate listener that
+	// requires client certificates issued by the peer client CA.
+	var peerServer *grpccomm.Server
+	if hasRaftPeers(GetTotalOrders(ctx)) {
+		peerTLSConf, err := config.Server.Peer.TLSConfig(tlsConf)
+		if err != nil {
+			return cli.Exit(errors.WithMessage(err, "failed to create peer server"), exitServerCreateFailed)
+		}
+		peerServer = grpccomm.NewServer(
+			grpccomm.ServerConfig{
+				ListenAddress: config.Server.Peer.ListenAddress,
+				Logger:        grpcLogger.Named("peer"),
+			},
+			append(grpcServerOptions[:len(grpcServerOptions):len(grpcServerOptions)], grpc.Creds(credentials.NewTLS(peerTLSConf)))...,
+		)
+		raftService := grpcapi.NewRaftService(grpcapi.TotalOrderMapAdapter(GetTotalOrders(ctx)))
+		orderv1.RegisterRaftAPIServer(peerServer.Server, raftService)
+	}
 
 	grpcServer := grpccomm.NewServer(
 		grpccomm.ServerConfig{
 			ListenAddress: config.Server.GRPC.ListenAddress,
 			Logger:        grpcLogger,
 		},
-		grpcServerOptions...,
+		append(grpcServerOptions[:len(grpcServerOptions):len(grpcServerOptions)], grpc.Creds(credentials.NewTLS(tlsConf)))...,
 	)
 
 	encodeService := &grpcapi.EncodeService{}
 	txv1.RegisterEncodeAPIServer(grpcServer.Server, encodeService)
 
 	namespaces := GetNamespaces(ctx)
-	if len(namespaces) == 0 {
+	if namespaces == nil {
+		namespaces = namespace.NewRegistry(nil)
+	}
+	if len(namespaces.Names()) == 0 {
 		logger.Warn("no namespaces defined")
 	}
 
-	grpcapiAdapter := grpcapi.NamespaceMapAdapter(namespaces)
+	// Total orders and namespaces are only started when serving so that
+	// other commands can operate on the databases without committing.
+	for _, to := range GetTotalOrders(ctx) {
+		if starter, ok := to.(interface{ Start() }); ok {
+			starter.Start()
+		}
+	}
+	for _, name := range namespaces.Names() {
+		if ns, ok := namespaces.Lookup(name); ok {
+			ns.Start()
+		}
+	}
 
-	submitService := grpcapi.NewSubmitService(grpcapiAdapter)
+	grpcapiAdapter := grpcapi.NamespaceAdapter{Namespaces: namespaces}
+
+	submitService := grpcapi.NewSubmitService(grpcapiAdapter, grpcapiAdapter)
 	txv1.RegisterSubmitAPIServer(grpcServer.Server, submitService)
 
 	storeService := grpcapi.NewStoreService(grpcapiAdapter)
 	storev1.RegisterStoreAPIServer(grpcServer.Server, storeService)
 
+	statusService := grpcapi.NewStatusService(grpcapiAdapter)
+	txv1.RegisterStatusAPIServer(grpcServer.Server, statusService)
+
+	commitService := grpcapi.NewCommitService(grpcapiAdapter)
+	txv1.RegisterCommitAPIServer(grpcServer.Server, commitService)
+
+	var adminServer *grpccomm.Server
+	if manager := GetNamespaceManager(ctx); manager != nil {
+		adminServer = grpccomm.NewServer(
+			grpccomm.ServerConfig{
+				ListenAddress: config.Server.Admin.ListenAddress,
+				Logger:        grpcLogger.Named("admin"),
+			},
+			append(grpcServerOptions[:len(grpcServerOptions):len(grpcServerOptions)], grpc.Creds(credentials.NewTLS(adminTLSConf)))...,
+		)
+		adminv1.RegisterAdminAPIServer(adminServer.Server, grpcapi.NewAdminService(manager))
+	}
+
+	orderService := grpcapi.NewOrderService(grpcapi.TotalOrderMapAdapter(GetTotalOrders(ctx)))
+	orderv1.RegisterOrderingAPIServer(grpcServer.Server, orderService)
+
 	mux := gwruntime.NewServeMux()
 	storev1.RegisterStoreAPIHandlerServer(context.Background(), mux, storeService)
+	txv1.RegisterStatusAPIHandlerServer(context.Background(), mux, statusService)
+
+	// The commit feed is served as Server-Sent Events alongside the gateway.
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/v1/events/", grpcapi.NewCommitEventHandler(grpcapiAdapter))
+	httpMux.Handle("/", mux)
 
 	httpServer := config.Server.HTTP.BuildServer(tlsConf)
-	httpServer.Handler = mux
+	httpServer.Handler = httpMux
 
 	httpRunner := func(signals <-chan os.Signal, ready chan<- struct{}) error {
 		lis, err := net.Listen("tcp", config.Server.HTTP.ListenAddress)
@@ -131,13 +202,25 @@ func startAction(ctx *cli.Context, config *options.Batik, interactive bool) erro
 		}
 	}
 
-	logger.Info(
-		"Starting server",
+	serverFields := []zap.Field{
 		zap.String("grpc-address", config.Server.GRPC.ListenAddress),
 		zap.String("http-address", config.Server.HTTP.ListenAddress),
-	)
+		zap.String("admin-address", config.Server.Admin.ListenAddress),
+	}
+	if peerServer != nil {
+		serverFields = append(serverFields, zap.String("peer-address", config.Server.Peer.ListenAddress))
+	}
+	logger.Info("Starting server", serverFields...)
 	grpcProcess := ifrit.Invoke(sigmon.New(grpcServer))
 	httpProcess := ifrit.Invoke(sigmon.New(ifrit.RunFunc(httpRunner)))
+	var adminWaitC <-chan error
+	if adminServer != nil {
+		adminWaitC = ifrit.Invoke(sigmon.New(adminServer)).Wait()
+	}
+	var peerWaitC <-chan error
+	if peerServer != nil {
+		peerWaitC = ifrit.Invoke(sigmon.New(peerServer)).Wait()
+	}
 	logger.Info("Server started")
 	if !interactive {
 		select {
@@ -145,8 +228,23 @@ func startAction(ctx *cli.Context, config *options.Batik, interactive bool) erro
 			return err
 		case err := <-httpProcess.Wait():
 			return err
+		case err := <-adminWaitC:
+			return err
+		case err := <-peerWaitC:
+			return err
 		}
 	}
 
 	return nil
 }
+
+// hasRaftPeers reports whether any of the total orders is a raft total order
+// with members hosted by other nodes.
+func hasRaftPeers(orders map[string]namespace.TotalOrder) bool {
+	for _, to := range orders {
+		if r, ok := to.(interface{ HasPeers() bool }); ok && r.HasPeers() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/sykesm/batik/pkg/store"
+)
+
+// storageEngines maps the names of the supported storage engines to functions
+// that open a database in a data directory. The data directory is not used by
+// the memory engine.
+var storageEngines = map[string]func(dataDir string) (store.KV, error){
+	"leveldb": func(dataDir string) (store.KV, error) {
+		db, err := store.NewLevelDB(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	},
+	"bbolt": func(dataDir string) (store.KV, error) {
+		db, err := store.NewBoltDB(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	},
+	"memory": func(string) (store.KV, error) {
+		return store.NewMemoryKV(), nil
+	},
+}
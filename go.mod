@@ -28,6 +28,7 @@ require (
 	github.com/syndtr/goleveldb v1.0.0
 	github.com/tedsuo/ifrit v0.0.0-20191009134036-9a97d0632f00
 	github.com/urfave/cli/v2 v2.2.1-0.20200712133201-d2d2098085ce
+	go.etcd.io/bbolt v1.3.5
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.16.0
 	golang.org/x/crypto v0.0.0-20201012173705-84dcc777aaee
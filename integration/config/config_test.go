@@ -57,6 +57,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			expected := options.BatikDefaults()
 			expected.DataDir = filepath.Join(tempdir, "data")
+			expected.TotalOrders[0].DataDir = filepath.Join(tempdir, "data", "totalorders", "default")
 			expected.Server.TLS.CertsDir = filepath.Join(tempdir, "tls-certs")
 
 			config := decodeShowConfigOutput(cmd)
@@ -74,6 +75,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			expected := options.BatikDefaults()
 			expected.DataDir = filepath.Join(tempdir, "data")
+			expected.TotalOrders[0].DataDir = filepath.Join(tempdir, "data", "totalorders", "default")
 			expected.Server.TLS.CertsDir = filepath.Join(tempdir, "tls-certs")
 			expected.Logging.Color = "yes"
 			expected.Logging.Format = "json"
@@ -92,6 +94,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			expected := options.BatikDefaults()
 			expected.DataDir = filepath.Join(tempdir, "custom-data-dir")
+			expected.TotalOrders[0].DataDir = filepath.Join(tempdir, "custom-data-dir", "totalorders", "default")
 			expected.Server.TLS.CertsDir = filepath.Join(tempdir, "tls-certs")
 
 			config := decodeShowConfigOutput(cmd)
@@ -108,6 +111,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			expected := options.BatikDefaults()
 			expected.DataDir = filepath.Join(tempdir, "data")
+			expected.TotalOrders[0].DataDir = filepath.Join(tempdir, "data", "totalorders", "default")
 			expected.Server.TLS.CertsDir = filepath.Join(tempdir, "some/custom-tls-dir")
 
 			config := decodeShowConfigOutput(cmd)
@@ -134,6 +138,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			expected := options.BatikDefaults()
 			expected.DataDir = filepath.Join(tempdir, "data")
+			expected.TotalOrders[0].DataDir = filepath.Join(tempdir, "data", "totalorders", "default")
 			expected.Server.TLS = options.ServerTLS{
 				ServerCert: options.CertKeyPair{
 					CertFile: "my-tls.pem",
@@ -220,6 +225,7 @@ var _ = Describe("Command Line Configuration", func() {
 
 			config.ApplyDefaults()
 			config.DataDir = filepath.Join(tempdir, "data")
+			config.TotalOrders[0].DataDir = filepath.Join(tempdir, "data", "totalorders", "default")
 			config.Server.HTTP.ListenAddress = "https:https"
 			config.Server.TLS.CertsDir = filepath.Join(tempdir, "tls-certs")
 
@@ -32,6 +32,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/sykesm/batik/pkg/options"
+	adminv1 "github.com/sykesm/batik/pkg/pb/admin/v1"
 	storev1 "github.com/sykesm/batik/pkg/pb/store/v1"
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
 	"github.com/sykesm/batik/pkg/tested"
@@ -44,6 +45,7 @@ var _ = Describe("gRPC", func() {
 		session        *gexec.Session
 		grpcAddress    string
 		httpAddress    string
+		adminAddress   string
 		storagePath    string
 		storageCleanup func()
 
@@ -53,6 +55,7 @@ var _ = Describe("gRPC", func() {
 	BeforeEach(func() {
 		grpcAddress = fmt.Sprintf("127.0.0.1:%d", StartPort())
 		httpAddress = fmt.Sprintf("127.0.0.1:%d", StartPort()+1)
+		adminAddress = fmt.Sprintf("127.0.0.1:%d", StartPort()+2)
 
 		storagePath, storageCleanup = tested.TempDir(GinkgoT(), "", "grpc-integration")
 
@@ -67,6 +70,7 @@ var _ = Describe("gRPC", func() {
 			"start",
 			"--grpc-listen-address", grpcAddress,
 			"--http-listen-address", httpAddress,
+			"--admin-listen-address", adminAddress,
 		)
 
 		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
@@ -110,6 +114,27 @@ var _ = Describe("gRPC", func() {
 		})
 	})
 
+	Describe("Admin API", func() {
+		It("is only served on the admin listener", func() {
+			_, err := adminv1.NewAdminAPIClient(clientConn).ListNamespaces(context.Background(), &adminv1.ListNamespacesRequest{})
+			Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+
+			creds, err := credentials.NewClientTLSFromFile(filepath.Join(storagePath, "tls-certs", "server-cert.pem"), "")
+			Expect(err).NotTo(HaveOccurred())
+			adminConn, err := grpc.Dial(adminAddress, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+			Expect(err).NotTo(HaveOccurred())
+			defer adminConn.Close()
+
+			resp, err := adminv1.NewAdminAPIClient(adminConn).ListNamespaces(context.Background(), &adminv1.ListNamespacesRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, ns := range resp.Namespaces {
+				names = append(names, ns.Name)
+			}
+			Expect(names).To(ConsistOf("ns1", "ns2"))
+		})
+	})
+
 	Describe("Submit Transaction API", func() {
 		var submitClient txv1.SubmitAPIClient
 		var storeClient storev1.StoreAPIClient
@@ -451,12 +476,14 @@ func writeNewConfig(path string) error {
 	config := options.Batik{
 		Namespaces: []options.Namespace{
 			{
-				Name:      "ns1",
-				Validator: "signature-builtin",
+				Name:       "ns1",
+				Validator:  "signature-builtin",
+				HMACSecret: "ns1-secret",
 			},
 			{
-				Name:      "ns2",
-				Validator: "signature-wasm",
+				Name:       "ns2",
+				Validator:  "signature-wasm",
+				HMACSecret: "ns2-secret",
 			},
 		},
 		Validators: []options.Validator{
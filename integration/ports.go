@@ -21,6 +21,7 @@ const (
 
 const (
 	GRPCBasePort TestPortRange = basePort + portsPerSuite*iota
+	RecoveryBasePort
 )
 
 // On linux, the default ephemeral port range is 32768-60999 and can be
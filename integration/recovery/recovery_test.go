@@ -0,0 +1,214 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package recovery
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sykesm/batik/pkg/options"
+	storev1 "github.com/sykesm/batik/pkg/pb/store/v1"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+var _ = Describe("Crash recovery", func() {
+	var (
+		session        *gexec.Session
+		grpcAddress    string
+		httpAddress    string
+		adminAddress   string
+		confFilePath   string
+		storagePath    string
+		storageCleanup func()
+	)
+
+	BeforeEach(func() {
+		grpcAddress = fmt.Sprintf("127.0.0.1:%d", StartPort())
+		httpAddress = fmt.Sprintf("127.0.0.1:%d", StartPort()+1)
+		adminAddress = fmt.Sprintf("127.0.0.1:%d", StartPort()+2)
+
+		storagePath, storageCleanup = tested.TempDir(GinkgoT(), "", "recovery-integration")
+
+		confFilePath = filepath.Join(storagePath, "batik.yaml")
+		err := writeNewConfig(confFilePath)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if session != nil {
+			session.Kill().Wait(testTimeout)
+		}
+		if storageCleanup != nil {
+			storageCleanup()
+		}
+	})
+
+	start := func() *grpc.ClientConn {
+		cmd := exec.Command(
+			batikPath,
+			"--config", confFilePath,
+			"start",
+			"--grpc-listen-address", grpcAddress,
+			"--http-listen-address", httpAddress,
+			"--admin-listen-address", adminAddress,
+		)
+
+		var err error
+		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(session.Err, testTimeout).Should(gbytes.Say("Server started"))
+
+		creds, err := credentials.NewClientTLSFromFile(filepath.Join(storagePath, "tls-certs", "server-cert.pem"), "")
+		Expect(err).NotTo(HaveOccurred())
+		conn, err := grpc.Dial(grpcAddress, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+		Expect(err).NotTo(HaveOccurred())
+		return conn
+	}
+
+	It("commits every ordered receipt after the node is killed mid-stream", func() {
+		const count = 100
+
+		var txs []*txv1.Transaction
+		for i := 0; i < count; i++ {
+			txs = append(txs, newTransaction())
+		}
+
+		conn := start()
+		submitClient := txv1.NewSubmitAPIClient(conn)
+
+		By("killing the node while transactions are being submitted")
+		var (
+			wg        sync.WaitGroup
+			mutex     sync.Mutex
+			submitted [][]byte
+		)
+		successC := make(chan struct{}, count)
+		for _, tx := range txs {
+			wg.Add(1)
+			go func(tx *txv1.Transaction) {
+				defer wg.Done()
+				resp, err := submitClient.Submit(context.Background(), &txv1.SubmitRequest{
+					Namespace:         "ns1",
+					SignedTransaction: &txv1.SignedTransaction{Transaction: tx},
+				})
+				if err == nil {
+					mutex.Lock()
+					submitted = append(submitted, resp.Txid)
+					mutex.Unlock()
+					successC <- struct{}{}
+				}
+			}(tx)
+		}
+		for i := 0; i < 10; i++ {
+			Eventually(successC, testTimeout).Should(Receive())
+		}
+		session.Kill().Wait(testTimeout)
+		wg.Wait()
+		conn.Close()
+
+		By("determining the receipts that were ordered before the crash")
+		verify, err := gexec.Start(exec.Command(batikPath, "--config", confFilePath, "order", "verify"), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(verify, testTimeout).Should(gexec.Exit(0))
+		match := regexp.MustCompile(`verified through seq (\d+)`).FindSubmatch(verify.Err.Contents())
+		Expect(match).NotTo(BeNil())
+		lastSeq, err := strconv.Atoi(string(match[1]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastSeq + 1).To(BeNumerically(">=", len(submitted)))
+
+		By("restarting the node")
+		conn = start()
+		defer conn.Close()
+		storeClient := storev1.NewStoreAPIClient(conn)
+
+		isCommitted := func(txid []byte) bool {
+			_, err := storeClient.GetState(context.Background(), &storev1.GetStateRequest{
+				Namespace: "ns1",
+				StateRef:  &txv1.StateReference{Txid: txid, OutputIndex: 0},
+			})
+			return err == nil
+		}
+
+		By("verifying every ordered receipt was committed")
+		for _, txid := range submitted {
+			Expect(isCommitted(txid)).To(BeTrue())
+		}
+		Eventually(func() int {
+			committed := 0
+			for _, tx := range txs {
+				itx, err := transaction.New(crypto.SHA256, tx)
+				Expect(err).NotTo(HaveOccurred())
+				if isCommitted(itx.ID) {
+					committed++
+				}
+			}
+			return committed
+		}, testTimeout).Should(Equal(lastSeq + 1))
+	})
+})
+
+func newTransaction() *txv1.Transaction {
+	salt := make([]byte, 32)
+	_, err := io.ReadFull(rand.Reader, salt)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &txv1.Transaction{
+		Salt: salt,
+		Outputs: []*txv1.State{{
+			Info:  &txv1.StateInfo{Kind: "test-state"},
+			State: salt,
+		}},
+	}
+}
+
+func writeNewConfig(path string) error {
+	config := options.Batik{
+		Namespaces: []options.Namespace{
+			{
+				Name:       "ns1",
+				Validator:  "signature-builtin",
+				HMACSecret: "ns1-secret",
+			},
+		},
+		Validators: []options.Validator{
+			{
+				Name: "signature-builtin",
+				Type: "builtin",
+			},
+		},
+	}
+
+	confFile, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer confFile.Close()
+
+	encoder := yaml.NewEncoder(confFile)
+	err = encoder.Encode(config)
+	if err != nil {
+		return err
+	}
+	encoder.Close()
+	return nil
+}
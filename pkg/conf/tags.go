@@ -41,6 +41,9 @@ func (tr *TagResolver) Resolve(input interface{}) error {
 
 func (tr *TagResolver) resolve(v reflect.Value) error {
 	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
 	t := v.Type()
 
 	var err error
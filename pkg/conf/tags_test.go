@@ -202,6 +202,14 @@ func TestBasicTypesNoPanic(t *testing.T) {
 		Vuint   *uint
 	}
 
+	type BasicSlices struct {
+		Vint    []int
+		Vstring []string
+		Vuint64 []uint64
+	}
+
+	vint, vstring := 1, "string"
+
 	tests := map[string]struct {
 		input interface{}
 	}{
@@ -209,6 +217,8 @@ func TestBasicTypesNoPanic(t *testing.T) {
 		"basic ref":    {input: &BasicTypes{}},
 		"pointers":     {input: BasicPointers{}},
 		"pointers ref": {input: &BasicPointers{}},
+		"pointers set": {input: &BasicPointers{Vint: &vint, Vstring: &vstring}},
+		"slices":       {input: &BasicSlices{Vint: []int{1}, Vstring: []string{"a"}, Vuint64: []uint64{1, 2}}},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/x509"
+	"encoding/pem"
 	"io"
 
 	"github.com/pkg/errors"
@@ -39,6 +40,44 @@ func UnmarshalPublicKey(pub []byte) (*ecdsa.PublicKey, error) {
 	return pk, nil
 }
 
+// MarshalPrivateKey returns the PKCS #8, ASN.1 DER form of the ECDSA private
+// key.
+func MarshalPrivateKey(k *ecdsa.PrivateKey) ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(k)
+}
+
+// UnmarshalPrivateKey parses a PKCS #8 or SEC 1, ASN.1 DER form of an ECDSA
+// private key. If any other key type is provided, an error is returned.
+func UnmarshalPrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type: %T", key)
+	}
+	return pk, nil
+}
+
+// ParsePrivateKeyPEM parses the first private key PEM block in data. Both
+// "PRIVATE KEY" and "EC PRIVATE KEY" blocks are supported.
+func ParsePrivateKeyPEM(data []byte) (*ecdsa.PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no private key PEM block found")
+		}
+		if block.Type == "PRIVATE KEY" || block.Type == "EC PRIVATE KEY" {
+			return UnmarshalPrivateKey(block.Bytes)
+		}
+	}
+}
+
 // Verify decodes the provided ASN.1 DER encoded signature and verifies the
 // signature of the digest using the public key. An error is returned if the
 // signature cannot be parsed or if the s component of the signature is not
@@ -12,6 +12,7 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/pem"
 	"math/big"
 	"testing"
 
@@ -43,6 +44,28 @@ func TestECDSA(t *testing.T) {
 				upk, err := UnmarshalPublicKey(mpk)
 				gt.Expect(err).NotTo(HaveOccurred())
 				gt.Expect(upk).To(Equal(&pk.PublicKey))
+
+				mk, err := MarshalPrivateKey(pk)
+				gt.Expect(err).NotTo(HaveOccurred())
+				uk, err := UnmarshalPrivateKey(mk)
+				gt.Expect(err).NotTo(HaveOccurred())
+				gt.Expect(uk).To(Equal(pk))
+			})
+
+			t.Run("PEM", func(t *testing.T) {
+				gt := NewGomegaWithT(t)
+				pkcs8, err := MarshalPrivateKey(pk)
+				gt.Expect(err).NotTo(HaveOccurred())
+				sec1, err := x509.MarshalECPrivateKey(pk)
+				gt.Expect(err).NotTo(HaveOccurred())
+
+				for _, block := range []*pem.Block{{Type: "PRIVATE KEY", Bytes: pkcs8}, {Type: "EC PRIVATE KEY", Bytes: sec1}} {
+					data := append([]byte("leading text\n"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert")})...)
+					data = append(data, pem.EncodeToMemory(block)...)
+					key, err := ParsePrivateKeyPEM(data)
+					gt.Expect(err).NotTo(HaveOccurred())
+					gt.Expect(key).To(Equal(pk))
+				}
 			})
 
 			t.Run("Signing", func(t *testing.T) {
@@ -132,6 +155,23 @@ func TestUnmarshalPublicKeyErrors(t *testing.T) {
 	}
 }
 
+func TestUnmarshalPrivateKeyErrors(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	_, err := UnmarshalPrivateKey([]byte("garbage"))
+	gt.Expect(err).To(HaveOccurred())
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	gt.Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = UnmarshalPrivateKey(der)
+	gt.Expect(err).To(MatchError("unsupported private key type: *rsa.PrivateKey"))
+
+	_, err = ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("key")}))
+	gt.Expect(err).To(MatchError("no private key PEM block found"))
+}
+
 func TestUnmarshalECDSASignatureBadSign(t *testing.T) {
 	t.Run("NegativeR", func(t *testing.T) {
 		gt := NewGomegaWithT(t)
@@ -9,13 +9,24 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
-type NamespaceMapAdapter map[string]*namespace.Namespace
+// A NamespaceLookup resolves active namespaces by name.
+type NamespaceLookup interface {
+	Lookup(name string) (*namespace.Namespace, bool)
+}
 
-func (nma NamespaceMapAdapter) Submitter(namespace string) Submitter {
-	ns, ok := nma[namespace]
+// NamespaceAdapter routes requests to the namespaces resolved by a
+// NamespaceLookup. Requests for namespaces that can not be resolved fail with
+// a namespace not found error.
+type NamespaceAdapter struct {
+	Namespaces NamespaceLookup
+}
+
+func (na NamespaceAdapter) Submitter(namespace string) Submitter {
+	ns, ok := na.Namespaces.Lookup(namespace)
 	if !ok {
 		return notFoundSubmitter(namespace)
 	}
@@ -23,19 +34,154 @@ func (nma NamespaceMapAdapter) Submitter(namespace string) Submitter {
 	return ns
 }
 
-func (nma NamespaceMapAdapter) Repository(namespace string) Repository {
-	ns, ok := nma[namespace]
+func (na NamespaceAdapter) Simulator(namespace string) Simulator {
+	ns, ok := na.Namespaces.Lookup(namespace)
+	if !ok {
+		return notFoundSimulator(namespace)
+	}
+
+	return ns
+}
+
+func (na NamespaceAdapter) Repository(namespace string) Repository {
+	ns, ok := na.Namespaces.Lookup(namespace)
 	if !ok {
 		return notFoundRepository(namespace)
 	}
 
-	return ns.Repo
+	return namespaceRepository{ns}
+}
+
+func (na NamespaceAdapter) StatusReporter(namespace string) StatusReporter {
+	ns, ok := na.Namespaces.Lookup(namespace)
+	if !ok {
+		return notFoundStatusReporter(namespace)
+	}
+
+	return ns
+}
+
+func (na NamespaceAdapter) Subscriber(namespace string) Subscriber {
+	ns, ok := na.Namespaces.Lookup(namespace)
+	if !ok {
+		return notFoundSubscriber(namespace)
+	}
+
+	return namespaceSubscriber{ns}
+}
+
+// NamespaceMapAdapter routes requests to a fixed set of namespaces.
+type NamespaceMapAdapter map[string]*namespace.Namespace
+
+func (nma NamespaceMapAdapter) Lookup(name string) (*namespace.Namespace, bool) {
+	ns, ok := nma[name]
+	return ns, ok
+}
+
+func (nma NamespaceMapAdapter) Submitter(namespace string) Submitter {
+	return NamespaceAdapter{nma}.Submitter(namespace)
+}
+
+func (nma NamespaceMapAdapter) Simulator(namespace string) Simulator {
+	return NamespaceAdapter{nma}.Simulator(namespace)
+}
+
+func (nma NamespaceMapAdapter) Repository(namespace string) Repository {
+	return NamespaceAdapter{nma}.Repository(namespace)
+}
+
+func (nma NamespaceMapAdapter) StatusReporter(namespace string) StatusReporter {
+	return NamespaceAdapter{nma}.StatusReporter(namespace)
+}
+
+func (nma NamespaceMapAdapter) Subscriber(namespace string) Subscriber {
+	return NamespaceAdapter{nma}.Subscriber(namespace)
+}
+
+// namespaceSubscriber adapts Namespace.Subscribe to the Subscriber interface.
+type namespaceSubscriber struct{ ns *namespace.Namespace }
+
+func (n namespaceSubscriber) Subscribe(fromSeq uint64) (CommitSubscription, error) {
+	return n.ns.Subscribe(fromSeq), nil
+}
+
+// namespaceRepository registers the requests made to the repository of a
+// namespace so the namespace database is not closed while they are in
+// flight.
+type namespaceRepository struct{ ns *namespace.Namespace }
+
+func (n namespaceRepository) PutTransaction(tx *transaction.Transaction) error {
+	if err := n.ns.Acquire(); err != nil {
+		return err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.PutTransaction(tx)
+}
+
+func (n namespaceRepository) GetTransaction(id transaction.ID) (*transaction.Transaction, error) {
+	if err := n.ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.GetTransaction(id)
+}
+
+func (n namespaceRepository) PutState(state *transaction.State) error {
+	if err := n.ns.Acquire(); err != nil {
+		return err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.PutState(state)
+}
+
+func (n namespaceRepository) GetState(id transaction.StateID, consumed bool) (*transaction.State, error) {
+	if err := n.ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.GetState(id, consumed)
+}
+
+func (n namespaceRepository) GetRejected(id transaction.ID) (*transaction.Rejected, error) {
+	if err := n.ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.GetRejected(id)
+}
+
+func (n namespaceRepository) GetCommitted(id transaction.ID) (*transaction.Committed, error) {
+	if err := n.ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer n.ns.Release()
+	return n.ns.Repo.GetCommitted(id)
+}
+
+type TotalOrderMapAdapter map[string]namespace.TotalOrder
+
+func (toma TotalOrderMapAdapter) TotalOrder(name string) TotalOrder {
+	to, ok := toma[name]
+	if !ok {
+		return notFoundTotalOrder(name)
+	}
+
+	return to
 }
 
 var errNamespaceNotFound = errors.Errorf("namespace not found")
 
+// isNamespaceNotFound returns true when a request was made for a namespace
+// that is not active, including a namespace that was removed while the
+// request was processed.
 func isNamespaceNotFound(err error) bool {
-	return errors.Is(err, errNamespaceNotFound)
+	return errors.Is(err, errNamespaceNotFound) || errors.Is(err, namespace.ErrNamespaceClosed)
+}
+
+var errTotalOrderNotFound = errors.Errorf("total order not found")
+
+func isTotalOrderNotFound(err error) bool {
+	return errors.Is(err, errTotalOrderNotFound)
 }
 
 type notFoundSubmitter string
@@ -44,6 +190,12 @@ func (nfs notFoundSubmitter) Submit(context.Context, *transaction.Signed) error
 	return errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfs)
 }
 
+type notFoundSimulator string
+
+func (nfs notFoundSimulator) Simulate(*transaction.Signed) (*namespace.Simulation, error) {
+	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfs)
+}
+
 type notFoundRepository string
 
 func (nfr notFoundRepository) PutTransaction(*transaction.Transaction) error {
@@ -61,3 +213,41 @@ func (nfr notFoundRepository) PutState(*transaction.State) error {
 func (nfr notFoundRepository) GetState(transaction.StateID, bool) (*transaction.State, error) {
 	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfr)
 }
+
+func (nfr notFoundRepository) GetRejected(transaction.ID) (*transaction.Rejected, error) {
+	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfr)
+}
+
+func (nfr notFoundRepository) GetCommitted(transaction.ID) (*transaction.Committed, error) {
+	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfr)
+}
+
+type notFoundStatusReporter string
+
+func (nfs notFoundStatusReporter) Status(transaction.ID) (*namespace.TxStatus, error) {
+	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfs)
+}
+
+type notFoundSubscriber string
+
+func (nfs notFoundSubscriber) Subscribe(uint64) (CommitSubscription, error) {
+	return nil, errors.WithMessagef(errNamespaceNotFound, "bad namespace %q", nfs)
+}
+
+type notFoundTotalOrder string
+
+func (nfo notFoundTotalOrder) Broadcast(context.Context, totalorder.TXIDAndHMAC) error {
+	return errors.WithMessagef(errTotalOrderNotFound, "bad total order %q", nfo)
+}
+
+func (nfo notFoundTotalOrder) Deliver(context.Context, uint64) (totalorder.TXIDAndHMAC, error) {
+	return totalorder.TXIDAndHMAC{}, errors.WithMessagef(errTotalOrderNotFound, "bad total order %q", nfo)
+}
+
+func (nfo notFoundTotalOrder) Checkpoint(context.Context, uint64) (totalorder.Checkpoint, error) {
+	return totalorder.Checkpoint{}, errors.WithMessagef(errTotalOrderNotFound, "bad total order %q", nfo)
+}
+
+func (nfo notFoundTotalOrder) LatestCheckpoint(context.Context) (totalorder.Checkpoint, error) {
+	return totalorder.Checkpoint{}, errors.WithMessagef(errTotalOrderNotFound, "bad total order %q", nfo)
+}
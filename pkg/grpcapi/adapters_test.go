@@ -4,12 +4,14 @@
 package grpcapi
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
 	"github.com/sykesm/batik/pkg/namespace"
 	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
@@ -26,7 +28,7 @@ func TestAdapters_MapAdapter(t *testing.T) {
 	})
 
 	store := adapter.Repository("present")
-	gt.Expect(store).To(Equal(repoPtr))
+	gt.Expect(store).To(Equal(namespaceRepository{namespacePtr}))
 
 	submit := adapter.Submitter("present")
 	gt.Expect(submit).To(Equal(namespacePtr))
@@ -36,6 +38,46 @@ func TestAdapters_MapAdapter(t *testing.T) {
 
 	missingSubmit := adapter.Submitter("missing")
 	gt.Expect(missingSubmit).To(Equal(notFoundSubmitter("missing")))
+
+	simulator := adapter.Simulator("present")
+	gt.Expect(simulator).To(Equal(namespacePtr))
+
+	missingSimulator := adapter.Simulator("missing")
+	gt.Expect(missingSimulator).To(Equal(notFoundSimulator("missing")))
+
+	reporter := adapter.StatusReporter("present")
+	gt.Expect(reporter).To(Equal(namespacePtr))
+
+	missingReporter := adapter.StatusReporter("missing")
+	gt.Expect(missingReporter).To(Equal(notFoundStatusReporter("missing")))
+
+	subscriber := adapter.Subscriber("present")
+	gt.Expect(subscriber).To(Equal(namespaceSubscriber{namespacePtr}))
+
+	missingSubscriber := adapter.Subscriber("missing")
+	gt.Expect(missingSubscriber).To(Equal(notFoundSubscriber("missing")))
+}
+
+func TestAdapters_NamespaceAdapter(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	namespacePtr := &namespace.Namespace{
+		Name: "present",
+		Repo: &store.TransactionRepository{},
+	}
+	registry := namespace.NewRegistry(nil)
+	adapter := NamespaceAdapter{Namespaces: registry}
+
+	gt.Expect(adapter.Submitter("present")).To(Equal(notFoundSubmitter("present")))
+
+	gt.Expect(registry.Add(namespacePtr)).To(Succeed())
+	gt.Expect(adapter.Submitter("present")).To(Equal(namespacePtr))
+	gt.Expect(adapter.Repository("present")).To(Equal(namespaceRepository{namespacePtr}))
+
+	_, err := registry.Remove("present")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(adapter.Submitter("present")).To(Equal(notFoundSubmitter("present")))
+	gt.Expect(adapter.Repository("present")).To(Equal(notFoundRepository("present")))
 }
 
 func TestAdapters_NotFoundSubmitter(t *testing.T) {
@@ -46,6 +88,30 @@ func TestAdapters_NotFoundSubmitter(t *testing.T) {
 	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
 }
 
+func TestAdapters_NotFoundSimulator(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	nfs := notFoundSimulator("missing")
+	_, err := nfs.Simulate(nil)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
+func TestAdapters_NotFoundStatusReporter(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	nfs := notFoundStatusReporter("missing")
+	_, err := nfs.Status(nil)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
+func TestAdapters_NotFoundSubscriber(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	nfs := notFoundSubscriber("missing")
+	_, err := nfs.Subscribe(0)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
 func TestAdapters_NotFoundRepository(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
@@ -62,4 +128,37 @@ func TestAdapters_NotFoundRepository(t *testing.T) {
 
 	_, err = nfr.GetState(transaction.StateID{}, false)
 	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+
+	_, err = nfr.GetRejected(nil)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
+func TestAdapters_TotalOrderMapAdapter(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	orderPtr := &totalorder.InProcess{}
+	adapter := TotalOrderMapAdapter(map[string]namespace.TotalOrder{
+		"present": orderPtr,
+	})
+
+	gt.Expect(adapter.TotalOrder("present")).To(Equal(orderPtr))
+	gt.Expect(adapter.TotalOrder("missing")).To(Equal(notFoundTotalOrder("missing")))
+}
+
+func TestAdapters_NotFoundTotalOrder(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	nfo := notFoundTotalOrder("missing")
+
+	err := nfo.Broadcast(context.Background(), totalorder.TXIDAndHMAC{})
+	gt.Expect(err).To(MatchError("bad total order \"missing\": total order not found"))
+
+	_, err = nfo.Deliver(context.Background(), 0)
+	gt.Expect(err).To(MatchError("bad total order \"missing\": total order not found"))
+
+	_, err = nfo.Checkpoint(context.Background(), 0)
+	gt.Expect(err).To(MatchError("bad total order \"missing\": total order not found"))
+
+	_, err = nfo.LatestCheckpoint(context.Background())
+	gt.Expect(err).To(MatchError("bad total order \"missing\": total order not found"))
 }
@@ -0,0 +1,190 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/options"
+	adminv1 "github.com/sykesm/batik/pkg/pb/admin/v1"
+)
+
+type NamespaceAdmin interface {
+	CreateNamespace(config options.Namespace, persist bool) (options.Namespace, error)
+	ListNamespaces() []options.Namespace
+	DescribeNamespace(name string) (options.Namespace, uint64, error)
+	RemoveNamespace(name string, persist bool) error
+	NamespaceHealth(name string) (namespace.Health, error)
+	ResumeNamespace(name string) error
+}
+
+// AdminService implements the AdminAPIServer gRPC interface.
+type AdminService struct {
+	// Unnsafe has been chosed to ensure there's a compilation failure when the
+	// implementation diverges from the gRPC service.
+	adminv1.UnsafeAdminAPIServer
+
+	admin NamespaceAdmin
+}
+
+var _ adminv1.AdminAPIServer = (*AdminService)(nil)
+
+// NewAdminService creates a new instance of the AdminService.
+func NewAdminService(admin NamespaceAdmin) *AdminService {
+	return &AdminService{
+		admin: admin,
+	}
+}
+
+// CreateNamespace creates a namespace and begins routing requests to it.
+func (a *AdminService) CreateNamespace(ctx context.Context, req *adminv1.CreateNamespaceRequest) (*adminv1.CreateNamespaceResponse, error) {
+	if req.Namespace == nil {
+		return nil, status.Error(codes.InvalidArgument, "namespace was not provided")
+	}
+
+	config, err := a.admin.CreateNamespace(toNamespaceConfig(req.Namespace), req.Persist)
+	if err != nil {
+		return nil, adminStatus(err)
+	}
+
+	return &adminv1.CreateNamespaceResponse{Namespace: fromNamespaceConfig(config)}, nil
+}
+
+// ListNamespaces lists the active namespaces.
+func (a *AdminService) ListNamespaces(ctx context.Context, req *adminv1.ListNamespacesRequest) (*adminv1.ListNamespacesResponse, error) {
+	var namespaces []*adminv1.Namespace
+	for _, config := range a.admin.ListNamespaces() {
+		namespaces = append(namespaces, fromNamespaceConfig(config))
+	}
+
+	return &adminv1.ListNamespacesResponse{Namespaces: namespaces}, nil
+}
+
+// DescribeNamespace retrieves the configuration and commit progress of a
+// namespace.
+func (a *AdminService) DescribeNamespace(ctx context.Context, req *adminv1.DescribeNamespaceRequest) (*adminv1.DescribeNamespaceResponse, error) {
+	config, nextSeq, err := a.admin.DescribeNamespace(req.Name)
+	if err != nil {
+		return nil, adminStatus(err)
+	}
+	health, err := a.admin.NamespaceHealth(req.Name)
+	if err != nil {
+		return nil, adminStatus(err)
+	}
+
+	return &adminv1.DescribeNamespaceResponse{
+		Namespace: fromNamespaceConfig(config),
+		NextSeq:   nextSeq,
+		Health:    fromHealth(req.Name, health),
+	}, nil
+}
+
+// RemoveNamespace stops routing requests to a namespace and closes it.
+func (a *AdminService) RemoveNamespace(ctx context.Context, req *adminv1.RemoveNamespaceRequest) (*adminv1.RemoveNamespaceResponse, error) {
+	if err := a.admin.RemoveNamespace(req.Name, req.Persist); err != nil {
+		return nil, adminStatus(err)
+	}
+
+	return &adminv1.RemoveNamespaceResponse{}, nil
+}
+
+// GetHealth reports whether commit processing is running for each active
+// namespace. Namespaces removed while the health is collected are omitted.
+func (a *AdminService) GetHealth(ctx context.Context, req *adminv1.GetHealthRequest) (*adminv1.GetHealthResponse, error) {
+	var namespaces []*adminv1.NamespaceHealth
+	for _, config := range a.admin.ListNamespaces() {
+		health, err := a.admin.NamespaceHealth(config.Name)
+		if errors.Is(err, namespace.ErrNamespaceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, adminStatus(err)
+		}
+		namespaces = append(namespaces, fromHealth(config.Name, health))
+	}
+
+	return &adminv1.GetHealthResponse{Namespaces: namespaces}, nil
+}
+
+// ResumeNamespace restarts commit processing for a halted namespace.
+func (a *AdminService) ResumeNamespace(ctx context.Context, req *adminv1.ResumeNamespaceRequest) (*adminv1.ResumeNamespaceResponse, error) {
+	if err := a.admin.ResumeNamespace(req.Name); err != nil {
+		return nil, adminStatus(err)
+	}
+
+	return &adminv1.ResumeNamespaceResponse{}, nil
+}
+
+func adminStatus(err error) error {
+	switch {
+	case errors.Is(err, namespace.ErrNamespaceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, namespace.ErrNamespaceExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, namespace.ErrInvalidNamespace):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, namespace.ErrNamespaceNotHalted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toNamespaceConfig(ns *adminv1.Namespace) options.Namespace {
+	var kinds []options.KindValidators
+	for _, kv := range ns.KindValidators {
+		kinds = append(kinds, options.KindValidators{Kind: kv.Kind, Validators: kv.Validators})
+	}
+
+	return options.Namespace{
+		Name:              ns.Name,
+		DataDir:           ns.DataDir,
+		Storage:           ns.Storage,
+		Validator:         ns.Validator,
+		Validators:        ns.Validators,
+		KindValidators:    kinds,
+		TotalOrder:        ns.TotalOrder,
+		HMACSecret:        ns.HmacSecret,
+		ValidationWorkers: int(ns.ValidationWorkers),
+	}
+}
+
+// fromNamespaceConfig converts a namespace configuration to its protobuf
+// message. The hmac secret is omitted.
+func fromNamespaceConfig(config options.Namespace) *adminv1.Namespace {
+	var kinds []*adminv1.KindValidators
+	for _, kv := range config.KindValidators {
+		kinds = append(kinds, &adminv1.KindValidators{Kind: kv.Kind, Validators: kv.Validators})
+	}
+
+	return &adminv1.Namespace{
+		Name:              config.Name,
+		DataDir:           config.DataDir,
+		Storage:           config.Storage,
+		Validator:         config.Validator,
+		Validators:        config.Validators,
+		KindValidators:    kinds,
+		TotalOrder:        config.TotalOrder,
+		ValidationWorkers: uint32(config.ValidationWorkers),
+	}
+}
+
+func fromHealth(name string, health namespace.Health) *adminv1.NamespaceHealth {
+	h := &adminv1.NamespaceHealth{
+		Name:   name,
+		Halted: health.Halted,
+		Seq:    health.Seq,
+		Cause:  health.Cause,
+	}
+	if !health.HaltedAt.IsZero() {
+		h.HaltedAt = timestamppb.New(health.HaltedAt)
+	}
+	return h
+}
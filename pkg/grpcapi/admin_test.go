@@ -0,0 +1,319 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	"github.com/sykesm/batik/pkg/options"
+	adminv1 "github.com/sykesm/batik/pkg/pb/admin/v1"
+	. "github.com/sykesm/batik/pkg/tested/matcher"
+)
+
+type namespaceAdmin struct {
+	namespaces []options.Namespace
+	nextSeq    uint64
+	health     map[string]namespace.Health
+	err        error
+
+	created options.Namespace
+	removed string
+	resumed string
+	persist bool
+}
+
+func (n *namespaceAdmin) CreateNamespace(config options.Namespace, persist bool) (options.Namespace, error) {
+	n.created, n.persist = config, persist
+	if n.err != nil {
+		return options.Namespace{}, n.err
+	}
+	config.ApplyDefaults("base")
+	return config, nil
+}
+
+func (n *namespaceAdmin) ListNamespaces() []options.Namespace {
+	return n.namespaces
+}
+
+func (n *namespaceAdmin) DescribeNamespace(name string) (options.Namespace, uint64, error) {
+	if n.err != nil {
+		return options.Namespace{}, 0, n.err
+	}
+	for _, config := range n.namespaces {
+		if config.Name == name {
+			return config, n.nextSeq, nil
+		}
+	}
+	return options.Namespace{}, 0, errors.WithMessagef(namespace.ErrNamespaceNotFound, "namespace %q", name)
+}
+
+func (n *namespaceAdmin) RemoveNamespace(name string, persist bool) error {
+	n.removed, n.persist = name, persist
+	return n.err
+}
+
+func (n *namespaceAdmin) NamespaceHealth(name string) (namespace.Health, error) {
+	if n.err != nil {
+		return namespace.Health{}, n.err
+	}
+	health, ok := n.health[name]
+	if !ok {
+		return namespace.Health{}, errors.WithMessagef(namespace.ErrNamespaceNotFound, "namespace %q", name)
+	}
+	return health, nil
+}
+
+func (n *namespaceAdmin) ResumeNamespace(name string) error {
+	n.resumed = name
+	return n.err
+}
+
+func testNamespaceHealth() map[string]namespace.Health {
+	return map[string]namespace.Health{
+		"ns1": {},
+		"ns2": {Halted: true, Seq: 9, Cause: "boom", HaltedAt: time.Date(2021, time.January, 2, 3, 4, 5, 6, time.UTC)},
+	}
+}
+
+func testNamespaceConfigs() []options.Namespace {
+	return []options.Namespace{
+		{Name: "ns1", DataDir: "data/ns1", Validator: "v1", TotalOrder: "to1", HMACSecret: "secret", ValidationWorkers: 2},
+		{Name: "ns2", DataDir: "data/ns2", Validator: "v2", TotalOrder: "to2", ValidationWorkers: 1},
+	}
+}
+
+func TestAdminService_CreateNamespace(t *testing.T) {
+	tests := map[string]struct {
+		req      *adminv1.CreateNamespaceRequest
+		err      error
+		expected *adminv1.CreateNamespaceResponse
+		errCode  codes.Code
+	}{
+		"defaults": {
+			req: &adminv1.CreateNamespaceRequest{
+				Namespace: &adminv1.Namespace{Name: "ns", HmacSecret: "secret", ValidationWorkers: 3},
+				Persist:   true,
+			},
+			expected: &adminv1.CreateNamespaceResponse{
+				Namespace: &adminv1.Namespace{
+					Name:              "ns",
+					DataDir:           "base/namespaces/ns",
+					Storage:           "leveldb",
+					Validator:         "signature-builtin",
+					TotalOrder:        "default",
+					ValidationWorkers: 3,
+				},
+			},
+		},
+		"validators": {
+			req: &adminv1.CreateNamespaceRequest{
+				Namespace: &adminv1.Namespace{
+					Name:           "ns",
+					Storage:        "memory",
+					Validators:     []string{"v1", "v2"},
+					KindValidators: []*adminv1.KindValidators{{Kind: "token", Validators: []string{"token-rules"}}},
+				},
+			},
+			expected: &adminv1.CreateNamespaceResponse{
+				Namespace: &adminv1.Namespace{
+					Name:              "ns",
+					DataDir:           "base/namespaces/ns",
+					Storage:           "memory",
+					Validators:        []string{"v1", "v2"},
+					KindValidators:    []*adminv1.KindValidators{{Kind: "token", Validators: []string{"token-rules"}}},
+					TotalOrder:        "default",
+					ValidationWorkers: uint32(runtime.NumCPU()),
+				},
+			},
+		},
+		"missing namespace": {
+			req:     &adminv1.CreateNamespaceRequest{},
+			errCode: codes.InvalidArgument,
+		},
+		"exists": {
+			req:     &adminv1.CreateNamespaceRequest{Namespace: &adminv1.Namespace{Name: "ns"}},
+			err:     errors.WithMessage(namespace.ErrNamespaceExists, "namespace \"ns\""),
+			errCode: codes.AlreadyExists,
+		},
+		"invalid": {
+			req:     &adminv1.CreateNamespaceRequest{Namespace: &adminv1.Namespace{Name: "ns"}},
+			err:     errors.WithMessage(namespace.ErrInvalidNamespace, "bad validator"),
+			errCode: codes.InvalidArgument,
+		},
+		"failure": {
+			req:     &adminv1.CreateNamespaceRequest{Namespace: &adminv1.Namespace{Name: "ns"}},
+			err:     errors.New("boom"),
+			errCode: codes.Internal,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			admin := &namespaceAdmin{err: tt.err}
+			adminSvc := NewAdminService(admin)
+
+			resp, err := adminSvc.CreateNamespace(context.Background(), tt.req)
+			gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+			if tt.expected == nil {
+				gt.Expect(resp).To(BeNil())
+				return
+			}
+			gt.Expect(resp).To(ProtoEqual(tt.expected))
+			gt.Expect(admin.persist).To(Equal(tt.req.Persist))
+			gt.Expect(admin.created.HMACSecret).To(Equal(tt.req.Namespace.HmacSecret))
+		})
+	}
+}
+
+func TestAdminService_ListNamespaces(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	adminSvc := NewAdminService(&namespaceAdmin{namespaces: testNamespaceConfigs()})
+	resp, err := adminSvc.ListNamespaces(context.Background(), &adminv1.ListNamespacesRequest{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp).To(ProtoEqual(&adminv1.ListNamespacesResponse{
+		Namespaces: []*adminv1.Namespace{
+			{Name: "ns1", DataDir: "data/ns1", Validator: "v1", TotalOrder: "to1", ValidationWorkers: 2},
+			{Name: "ns2", DataDir: "data/ns2", Validator: "v2", TotalOrder: "to2", ValidationWorkers: 1},
+		},
+	}))
+}
+
+func TestAdminService_DescribeNamespace(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		err      error
+		expected *adminv1.DescribeNamespaceResponse
+		errCode  codes.Code
+	}{
+		"present": {
+			name: "ns1",
+			expected: &adminv1.DescribeNamespaceResponse{
+				Namespace: &adminv1.Namespace{Name: "ns1", DataDir: "data/ns1", Validator: "v1", TotalOrder: "to1", ValidationWorkers: 2},
+				NextSeq:   7,
+				Health:    &adminv1.NamespaceHealth{Name: "ns1"},
+			},
+		},
+		"halted": {
+			name: "ns2",
+			expected: &adminv1.DescribeNamespaceResponse{
+				Namespace: &adminv1.Namespace{Name: "ns2", DataDir: "data/ns2", Validator: "v2", TotalOrder: "to2", ValidationWorkers: 1},
+				NextSeq:   7,
+				Health: &adminv1.NamespaceHealth{
+					Name:     "ns2",
+					Halted:   true,
+					Seq:      9,
+					Cause:    "boom",
+					HaltedAt: &timestamppb.Timestamp{Seconds: 1609556645, Nanos: 6},
+				},
+			},
+		},
+		"missing": {
+			name:    "missing",
+			errCode: codes.NotFound,
+		},
+		"failure": {
+			name:    "ns1",
+			err:     errors.New("boom"),
+			errCode: codes.Internal,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			adminSvc := NewAdminService(&namespaceAdmin{namespaces: testNamespaceConfigs(), nextSeq: 7, health: testNamespaceHealth(), err: tt.err})
+			resp, err := adminSvc.DescribeNamespace(context.Background(), &adminv1.DescribeNamespaceRequest{Name: tt.name})
+			gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+			if tt.expected == nil {
+				gt.Expect(resp).To(BeNil())
+				return
+			}
+			gt.Expect(resp).To(ProtoEqual(tt.expected))
+		})
+	}
+}
+
+func TestAdminService_RemoveNamespace(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		errCode codes.Code
+	}{
+		"success":   {},
+		"not found": {err: errors.WithMessage(namespace.ErrNamespaceNotFound, "namespace \"ns\""), errCode: codes.NotFound},
+		"failure":   {err: errors.New("boom"), errCode: codes.Internal},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			admin := &namespaceAdmin{err: tt.err}
+			adminSvc := NewAdminService(admin)
+
+			_, err := adminSvc.RemoveNamespace(context.Background(), &adminv1.RemoveNamespaceRequest{Name: "ns", Persist: true})
+			gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+			gt.Expect(admin.removed).To(Equal("ns"))
+			gt.Expect(admin.persist).To(BeTrue())
+		})
+	}
+}
+
+func TestAdminService_GetHealth(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	// ns3 was removed after the namespaces were listed.
+	namespaces := append(testNamespaceConfigs(), options.Namespace{Name: "ns3"})
+	adminSvc := NewAdminService(&namespaceAdmin{namespaces: namespaces, health: testNamespaceHealth()})
+	resp, err := adminSvc.GetHealth(context.Background(), &adminv1.GetHealthRequest{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp).To(ProtoEqual(&adminv1.GetHealthResponse{
+		Namespaces: []*adminv1.NamespaceHealth{
+			{Name: "ns1"},
+			{Name: "ns2", Halted: true, Seq: 9, Cause: "boom", HaltedAt: &timestamppb.Timestamp{Seconds: 1609556645, Nanos: 6}},
+		},
+	}))
+
+	adminSvc = NewAdminService(&namespaceAdmin{namespaces: namespaces, err: errors.New("boom")})
+	_, err = adminSvc.GetHealth(context.Background(), &adminv1.GetHealthRequest{})
+	gt.Expect(status.Code(err)).To(Equal(codes.Internal))
+}
+
+func TestAdminService_ResumeNamespace(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		errCode codes.Code
+	}{
+		"success":    {},
+		"not found":  {err: errors.WithMessage(namespace.ErrNamespaceNotFound, "namespace \"ns\""), errCode: codes.NotFound},
+		"not halted": {err: errors.WithMessage(namespace.ErrNamespaceNotHalted, "namespace \"ns\""), errCode: codes.FailedPrecondition},
+		"failure":    {err: errors.New("boom"), errCode: codes.Internal},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			admin := &namespaceAdmin{err: tt.err}
+			adminSvc := NewAdminService(admin)
+
+			_, err := adminSvc.ResumeNamespace(context.Background(), &adminv1.ResumeNamespaceRequest{Name: "ns"})
+			gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+			gt.Expect(admin.resumed).To(Equal("ns"))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+type SubscriberMap interface {
+	Subscriber(namespace string) Subscriber
+}
+
+type Subscriber interface {
+	Subscribe(fromSeq uint64) (CommitSubscription, error)
+}
+
+type CommitSubscription interface {
+	Next(context.Context) (*namespace.CommitEvent, error)
+}
+
+// CommitService implements the CommitAPIServer gRPC interface.
+type CommitService struct {
+	// Unnsafe has been chosed to ensure there's a compilation failure when the
+	// implementation diverges from the gRPC service.
+	txv1.UnsafeCommitAPIServer
+
+	subscribers SubscriberMap
+}
+
+var _ txv1.CommitAPIServer = (*CommitService)(nil)
+
+// NewCommitService creates a new instance of the CommitService.
+func NewCommitService(subscribers SubscriberMap) *CommitService {
+	return &CommitService{
+		subscribers: subscribers,
+	}
+}
+
+// WatchCommits streams the transactions committed by a namespace starting at
+// the requested sequence number. The stream ends when the client goes away.
+func (c *CommitService) WatchCommits(req *txv1.WatchCommitsRequest, stream txv1.CommitAPI_WatchCommitsServer) error {
+	sub, err := c.subscribers.Subscriber(req.Namespace).Subscribe(req.StartSeq)
+	if isNamespaceNotFound(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	ctx := stream.Context()
+	for {
+		event, err := sub.Next(ctx)
+		if ctx.Err() != nil {
+			return status.Error(codes.Canceled, ctx.Err().Error())
+		}
+		if isNamespaceNotFound(err) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		err = stream.Send(&txv1.WatchCommitsResponse{Event: fromCommitEvent(event)})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// CommitEventHandler serves the commit feed of a namespace to HTTP clients as
+// Server-Sent Events. The feed is served at /v1/events/{namespace}/commits
+// and starts at the sequence number provided by the start_seq query
+// parameter. Clients that reconnect with a Last-Event-ID header resume after
+// the sequence number of the last event they received.
+type CommitEventHandler struct {
+	subscribers SubscriberMap
+}
+
+// NewCommitEventHandler creates a new instance of the CommitEventHandler.
+func NewCommitEventHandler(subscribers SubscriberMap) *CommitEventHandler {
+	return &CommitEventHandler{
+		subscribers: subscribers,
+	}
+}
+
+func (h *CommitEventHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ns, ok := commitEventNamespace(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	startSeq, err := commitEventStartSeq(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, err := h.subscribers.Subscriber(ns).Subscribe(startSeq)
+	if isNamespaceNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		event, err := sub.Next(req.Context())
+		if err != nil {
+			return
+		}
+		data, err := protojson.Marshal(fromCommitEvent(event))
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, "id: %d\nevent: commit\ndata: %s\n\n", event.SeqNo, data)
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// commitEventNamespace extracts the namespace from a path of the form
+// /v1/events/{namespace}/commits.
+func commitEventNamespace(path string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "v1" || parts[1] != "events" || parts[2] == "" || parts[3] != "commits" {
+		return "", false
+	}
+	return parts[2], true
+}
+
+func commitEventStartSeq(req *http.Request) (uint64, error) {
+	if lastID := req.Header.Get("Last-Event-ID"); lastID != "" {
+		seq, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid Last-Event-ID %q", lastID)
+		}
+		return seq + 1, nil
+	}
+	if start := req.URL.Query().Get("start_seq"); start != "" {
+		seq, err := strconv.ParseUint(start, 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid start_seq %q", start)
+		}
+		return seq, nil
+	}
+	return 0, nil
+}
+
+func fromCommitEvent(event *namespace.CommitEvent) *txv1.CommitEvent {
+	return &txv1.CommitEvent{
+		Txid:      event.TxID,
+		Seq:       event.SeqNo,
+		ReceiptId: event.ReceiptID,
+		Consumed:  transaction.FromStateIDs(event.Consumed...),
+		Outputs:   transaction.FromStates(event.Outputs...),
+	}
+}
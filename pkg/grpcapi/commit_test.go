@@ -0,0 +1,277 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	. "github.com/sykesm/batik/pkg/tested/matcher"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+type subscriberMap map[string]*eventSubscriber
+
+func (sm subscriberMap) Subscriber(namespace string) Subscriber {
+	s, ok := sm[namespace]
+	if !ok {
+		return notFoundSubscriber(namespace)
+	}
+	return s
+}
+
+// eventSubscriber replays a fixed list of events and then returns err or
+// blocks until the context is done.
+type eventSubscriber struct {
+	events []*namespace.CommitEvent
+	err    error
+	start  uint64
+}
+
+func (e *eventSubscriber) Subscribe(fromSeq uint64) (CommitSubscription, error) {
+	e.start = fromSeq
+	var events []*namespace.CommitEvent
+	for _, event := range e.events {
+		if event.SeqNo >= fromSeq {
+			events = append(events, event)
+		}
+	}
+	return &eventSubscription{events: events, err: e.err}, nil
+}
+
+type eventSubscription struct {
+	events []*namespace.CommitEvent
+	err    error
+}
+
+func (e *eventSubscription) Next(ctx context.Context) (*namespace.CommitEvent, error) {
+	if len(e.events) > 0 {
+		event := e.events[0]
+		e.events = e.events[1:]
+		return event, nil
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type watchCommitsServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*txv1.WatchCommitsResponse
+	onSend    func()
+}
+
+func (w *watchCommitsServer) Context() context.Context { return w.ctx }
+
+func (w *watchCommitsServer) Send(resp *txv1.WatchCommitsResponse) error {
+	w.responses = append(w.responses, resp)
+	if w.onSend != nil {
+		w.onSend()
+	}
+	return nil
+}
+
+func testCommitEvents() []*namespace.CommitEvent {
+	return []*namespace.CommitEvent{
+		{
+			TxID:      transaction.ID("tx-0"),
+			SeqNo:     0,
+			ReceiptID: []byte("receipt-0"),
+			Outputs: []*transaction.State{{
+				ID:        transaction.StateID{TxID: transaction.ID("tx-0"), OutputIndex: 0},
+				StateInfo: &transaction.StateInfo{Kind: "kind"},
+				Data:      []byte("state-0"),
+			}},
+		},
+		{
+			TxID:      transaction.ID("tx-1"),
+			SeqNo:     2,
+			ReceiptID: []byte("receipt-1"),
+			Consumed:  []*transaction.StateID{{TxID: transaction.ID("tx-0"), OutputIndex: 0}},
+		},
+	}
+}
+
+func TestCommitService_WatchCommits(t *testing.T) {
+	tests := map[string]struct {
+		namespace string
+		startSeq  uint64
+		err       error
+		expected  []*txv1.WatchCommitsResponse
+		errCode   codes.Code
+	}{
+		"all events": {
+			expected: []*txv1.WatchCommitsResponse{
+				{Event: &txv1.CommitEvent{
+					Txid:      []byte("tx-0"),
+					ReceiptId: []byte("receipt-0"),
+					Outputs:   []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("state-0")}},
+				}},
+				{Event: &txv1.CommitEvent{
+					Txid:      []byte("tx-1"),
+					Seq:       2,
+					ReceiptId: []byte("receipt-1"),
+					Consumed:  []*txv1.StateReference{{Txid: []byte("tx-0"), OutputIndex: 0}},
+				}},
+			},
+			errCode: codes.Canceled,
+		},
+		"start sequence": {
+			startSeq: 1,
+			expected: []*txv1.WatchCommitsResponse{
+				{Event: &txv1.CommitEvent{
+					Txid:      []byte("tx-1"),
+					Seq:       2,
+					ReceiptId: []byte("receipt-1"),
+					Consumed:  []*txv1.StateReference{{Txid: []byte("tx-0"), OutputIndex: 0}},
+				}},
+			},
+			errCode: codes.Canceled,
+		},
+		"subscription failure": {
+			startSeq: 3,
+			err:      errors.New("boom"),
+			errCode:  codes.Internal,
+		},
+		"unknown namespace": {
+			namespace: "missing",
+			errCode:   codes.InvalidArgument,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			subscriber := &eventSubscriber{events: testCommitEvents(), err: tt.err}
+			commitSvc := NewCommitService(subscriberMap{"ns": subscriber})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream := &watchCommitsServer{ctx: ctx}
+			stream.onSend = func() {
+				if len(stream.responses) == len(tt.expected) {
+					cancel()
+				}
+			}
+
+			ns := tt.namespace
+			if ns == "" {
+				ns = "ns"
+			}
+			err := commitSvc.WatchCommits(&txv1.WatchCommitsRequest{Namespace: ns, StartSeq: tt.startSeq}, stream)
+			gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+			gt.Expect(stream.responses).To(HaveLen(len(tt.expected)))
+			for i := range tt.expected {
+				gt.Expect(stream.responses[i]).To(ProtoEqual(tt.expected[i]))
+			}
+		})
+	}
+}
+
+func TestCommitEventHandler(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	subscriber := &eventSubscriber{events: testCommitEvents(), err: errors.New("end of feed")}
+	server := httptest.NewServer(NewCommitEventHandler(subscriberMap{"ns": subscriber}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/events/ns/commits")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	gt.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	gt.Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body)
+	var lines []string
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			events = append(events, strings.Join(lines, "\n"))
+			lines = nil
+			continue
+		}
+		lines = append(lines, scanner.Text())
+	}
+	gt.Expect(events).To(HaveLen(2))
+
+	for i, event := range testCommitEvents() {
+		data, err := protojson.Marshal(fromCommitEvent(event))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(events[i]).To(Equal(strings.Join([]string{
+			"id: " + []string{"0", "2"}[i],
+			"event: commit",
+			"data: " + string(data),
+		}, "\n")))
+	}
+
+	t.Run("StartSeq", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		resp, err := http.Get(server.URL + "/v1/events/ns/commits?start_seq=1")
+		gt.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		gt.Expect(subscriber.start).To(Equal(uint64(1)))
+	})
+
+	t.Run("LastEventID", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/events/ns/commits?start_seq=1", nil)
+		gt.Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Last-Event-ID", "2")
+		resp, err := http.DefaultClient.Do(req)
+		gt.Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		gt.Expect(subscriber.start).To(Equal(uint64(3)))
+	})
+
+	errTests := map[string]struct {
+		method string
+		path   string
+		header string
+		code   int
+	}{
+		"unknown namespace":  {path: "/v1/events/missing/commits", code: http.StatusNotFound},
+		"bad path":           {path: "/v1/events/ns", code: http.StatusNotFound},
+		"bad start_seq":      {path: "/v1/events/ns/commits?start_seq=x", code: http.StatusBadRequest},
+		"bad last event id":  {path: "/v1/events/ns/commits", header: "x", code: http.StatusBadRequest},
+		"method not allowed": {method: http.MethodPost, path: "/v1/events/ns/commits", code: http.StatusMethodNotAllowed},
+	}
+	for name, tt := range errTests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, server.URL+tt.path, nil)
+			gt.Expect(err).NotTo(HaveOccurred())
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			gt.Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			gt.Expect(resp.StatusCode).To(Equal(tt.code))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+	"github.com/sykesm/batik/pkg/totalorder"
+)
+
+type TotalOrderMap interface {
+	TotalOrder(name string) TotalOrder
+}
+
+type TotalOrder interface {
+	Broadcast(ctx context.Context, t totalorder.TXIDAndHMAC) error
+	Deliver(ctx context.Context, seq uint64) (totalorder.TXIDAndHMAC, error)
+}
+
+// OrderService implements the OrderingAPIServer gRPC interface.
+type OrderService struct {
+	// Unnsafe has been chosed to ensure there's a compilation failure when the
+	// implementation diverges from the gRPC service.
+	orderv1.UnsafeOrderingAPIServer
+
+	orders TotalOrderMap
+}
+
+var _ orderv1.OrderingAPIServer = (*OrderService)(nil)
+
+// NewOrderService creates a new instance of the OrderService.
+func NewOrderService(orders TotalOrderMap) *OrderService {
+	return &OrderService{
+		orders: orders,
+	}
+}
+
+// Broadcast submits an entry to a total order for sequencing.
+func (o *OrderService) Broadcast(ctx context.Context, req *orderv1.BroadcastRequest) (*orderv1.BroadcastResponse, error) {
+	entry := req.GetEntry()
+	if entry == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "entry was not provided")
+	}
+	if len(entry.Txid) != 32 || len(entry.Hmac) != 32 {
+		return nil, status.Errorf(codes.InvalidArgument, "entry txid and hmac must be 32 bytes")
+	}
+
+	err := o.orders.TotalOrder(req.TotalOrder).Broadcast(ctx, totalorder.TXIDAndHMAC{
+		ID:   entry.Txid,
+		HMAC: entry.Hmac,
+	})
+	if err != nil {
+		return nil, orderStatus(err)
+	}
+
+	return &orderv1.BroadcastResponse{}, nil
+}
+
+// Deliver streams the entries of a total order, in sequence, starting from
+// the requested sequence number. The stream remains open and waits for new
+// entries until the client goes away.
+func (o *OrderService) Deliver(req *orderv1.DeliverRequest, stream orderv1.OrderingAPI_DeliverServer) error {
+	ctx := stream.Context()
+	order := o.orders.TotalOrder(req.TotalOrder)
+
+	for seq := req.FromSeq; ; seq++ {
+		tah, err := order.Deliver(ctx, seq)
+		if err != nil {
+			return orderStatus(err)
+		}
+
+		err = stream.Send(&orderv1.DeliverResponse{
+			Seq: seq,
+			Entry: &orderv1.Entry{
+				Txid: tah.ID,
+				Hmac: tah.HMAC,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Checkpoint returns the hash chain accumulator of a total order through a
+// sequence number.
+func (o *OrderService) Checkpoint(ctx context.Context, req *orderv1.CheckpointRequest) (*orderv1.CheckpointResponse, error) {
+	order := o.orders.TotalOrder(req.TotalOrder)
+	checkpointer, ok := order.(totalorder.Checkpointer)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "total order %q does not provide checkpoints", req.TotalOrder)
+	}
+
+	var checkpoint totalorder.Checkpoint
+	var err error
+	if req.Latest {
+		checkpoint, err = checkpointer.LatestCheckpoint(ctx)
+	} else {
+		checkpoint, err = checkpointer.Checkpoint(ctx, req.Seq)
+	}
+	if err != nil {
+		return nil, orderStatus(err)
+	}
+
+	return &orderv1.CheckpointResponse{
+		Seq:         checkpoint.Seq,
+		Accumulator: checkpoint.Accumulator,
+	}, nil
+}
+
+func orderStatus(err error) error {
+	code := codes.Unknown
+	switch {
+	case isTotalOrderNotFound(err):
+		code = codes.NotFound
+	case errors.Is(err, totalorder.ErrUncommitted):
+		code = codes.OutOfRange
+	case err == context.Canceled:
+		code = codes.Canceled
+	case err == context.DeadlineExceeded:
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,344 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
+)
+
+func TestOrderService_Broadcast(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	orderSvc, order, cleanup := newOrderService(t)
+	defer cleanup()
+
+	entry := testEntry("tx1")
+	_, err := orderSvc.Broadcast(context.Background(), &orderv1.BroadcastRequest{
+		TotalOrder: "order1",
+		Entry:      &orderv1.Entry{Txid: entry.ID, Hmac: entry.HMAC},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tah, err := order.Deliver(context.Background(), 0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(entry))
+}
+
+func TestOrderService_BroadcastErrors(t *testing.T) {
+	tests := map[string]struct {
+		req     *orderv1.BroadcastRequest
+		code    codes.Code
+		message string
+	}{
+		"missing entry": {
+			req:     &orderv1.BroadcastRequest{TotalOrder: "order1"},
+			code:    codes.InvalidArgument,
+			message: "entry was not provided",
+		},
+		"short txid": {
+			req:     &orderv1.BroadcastRequest{TotalOrder: "order1", Entry: &orderv1.Entry{Txid: []byte("short"), Hmac: testEntry("tx1").HMAC}},
+			code:    codes.InvalidArgument,
+			message: "entry txid and hmac must be 32 bytes",
+		},
+		"unknown total order": {
+			req:     &orderv1.BroadcastRequest{TotalOrder: "missing", Entry: &orderv1.Entry{Txid: testEntry("tx1").ID, Hmac: testEntry("tx1").HMAC}},
+			code:    codes.NotFound,
+			message: "bad total order \"missing\": total order not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			orderSvc, _, cleanup := newOrderService(t)
+			defer cleanup()
+
+			_, err := orderSvc.Broadcast(context.Background(), tt.req)
+			gt.Expect(err).To(HaveOccurred())
+			gt.Expect(status.Code(err)).To(Equal(tt.code))
+			gt.Expect(status.Convert(err).Message()).To(Equal(tt.message))
+		})
+	}
+}
+
+func TestOrderService_Deliver(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	orderSvc, order, cleanup := newOrderService(t)
+	defer cleanup()
+
+	client, closeConn := serveOrderService(t, orderSvc)
+	defer closeConn()
+
+	for _, tx := range []string{"tx0", "tx1"} {
+		err := order.Broadcast(context.Background(), testEntry(tx))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Deliver(ctx, &orderv1.DeliverRequest{TotalOrder: "order1", FromSeq: 1})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := stream.Recv()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp.Seq).To(Equal(uint64(1)))
+	gt.Expect(resp.Entry.Txid).To(Equal(testEntry("tx1").ID))
+	gt.Expect(resp.Entry.Hmac).To(Equal(testEntry("tx1").HMAC))
+
+	// The stream waits for entries that have not been ordered yet.
+	respC := make(chan *orderv1.DeliverResponse, 1)
+	go func() {
+		resp, err := stream.Recv()
+		if err == nil {
+			respC <- resp
+		}
+	}()
+	gt.Consistently(respC).ShouldNot(Receive())
+
+	err = order.Broadcast(context.Background(), testEntry("tx2"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Eventually(respC).Should(Receive(&resp))
+	gt.Expect(resp.Seq).To(Equal(uint64(2)))
+	gt.Expect(resp.Entry.Txid).To(Equal(testEntry("tx2").ID))
+
+	stream, err = client.Deliver(ctx, &orderv1.DeliverRequest{TotalOrder: "missing"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = stream.Recv()
+	gt.Expect(status.Code(err)).To(Equal(codes.NotFound))
+}
+
+func TestOrderService_GRPCTotalOrder(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	orderSvc, _, cleanup := newOrderService(t)
+	defer cleanup()
+
+	conn := dialOrderService(t, orderSvc)
+	defer conn.Close()
+
+	remote := totalorder.NewGRPC(conn, "order1")
+	for _, tx := range []string{"tx0", "tx1", "tx2"} {
+		err := remote.Broadcast(context.Background(), testEntry(tx))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for seq, tx := range []string{"tx0", "tx1", "tx2"} {
+		tah, err := remote.Deliver(ctx, uint64(seq))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(tah).To(Equal(testEntry(tx)))
+	}
+
+	// Out of sequence requests open a new stream.
+	tah, err := remote.Deliver(ctx, 1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(testEntry("tx1")))
+
+	// A stream bound to a canceled context is replaced.
+	expired, cancelExpired := context.WithCancel(context.Background())
+	_, err = remote.Deliver(expired, 2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	cancelExpired()
+
+	err = remote.Broadcast(context.Background(), testEntry("tx3"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	tah, err = remote.Deliver(ctx, 3)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(testEntry("tx3")))
+}
+
+func TestOrderService_GRPCTotalOrderConcurrentDeliver(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	orderSvc, _, cleanup := newOrderService(t)
+	defer cleanup()
+
+	conn := dialOrderService(t, orderSvc)
+	defer conn.Close()
+
+	remote := totalorder.NewGRPC(conn, "order1")
+	err := remote.Broadcast(context.Background(), testEntry("tx0"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// A caller waiting for an entry that has not been ordered does not
+	// block other callers.
+	waiting, cancelWaiting := context.WithCancel(context.Background())
+	defer cancelWaiting()
+	errC := make(chan error, 1)
+	go func() {
+		_, err := remote.Deliver(waiting, 5)
+		errC <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tah, err := remote.Deliver(ctx, 0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(testEntry("tx0")))
+
+	// The waiting caller returns when its context is canceled.
+	gt.Consistently(errC).ShouldNot(Receive())
+	cancelWaiting()
+	gt.Eventually(errC).Should(Receive(MatchError(context.Canceled)))
+
+	// A stream opened by another caller is abandoned when the context of
+	// the caller using it ends.
+	expired, cancelExpired := context.WithCancel(context.Background())
+	errC = make(chan error, 1)
+	go func() {
+		_, err := remote.Deliver(expired, 1)
+		errC <- err
+	}()
+	gt.Consistently(errC).ShouldNot(Receive())
+	cancelExpired()
+	gt.Eventually(errC).Should(Receive(MatchError(context.Canceled)))
+
+	err = remote.Broadcast(context.Background(), testEntry("tx1"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	tah, err = remote.Deliver(ctx, 1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(testEntry("tx1")))
+}
+
+func TestOrderService_GRPCTotalOrderUnavailable(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	address := lis.Addr().String()
+	lis.Close()
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = totalorder.NewGRPC(conn, "order1").Deliver(ctx, 0)
+	gt.Expect(errors.Is(err, totalorder.ErrUnavailable)).To(BeTrue(), "%v", err)
+}
+
+func TestOrderService_Checkpoint(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	orderSvc, order, cleanup := newOrderService(t)
+	defer cleanup()
+
+	_, err := orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "order1", Latest: true})
+	gt.Expect(status.Code(err)).To(Equal(codes.OutOfRange))
+
+	for _, tx := range []string{"tx0", "tx1"} {
+		err := order.Broadcast(context.Background(), testEntry(tx))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	_, err = order.Deliver(context.Background(), 1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	latest, err := order.LatestCheckpoint(context.Background())
+	gt.Expect(err).NotTo(HaveOccurred())
+	first, err := order.Checkpoint(context.Background(), 0)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "order1", Latest: true})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp.Seq).To(Equal(uint64(1)))
+	gt.Expect(resp.Accumulator).To(Equal(latest.Accumulator))
+
+	resp, err = orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "order1", Seq: 0})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp.Seq).To(Equal(uint64(0)))
+	gt.Expect(resp.Accumulator).To(Equal(first.Accumulator))
+
+	_, err = orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "order1", Seq: 2})
+	gt.Expect(status.Code(err)).To(Equal(codes.OutOfRange))
+
+	_, err = orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "missing"})
+	gt.Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+	// Remote total orders report the checkpoints of the hosting node.
+	conn := dialOrderService(t, orderSvc)
+	defer conn.Close()
+	remote := totalorder.NewGRPC(conn, "order1")
+
+	common, err := totalorder.Compare(context.Background(), order, remote)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(common).To(Equal(latest))
+
+	_, err = remote.Checkpoint(context.Background(), 5)
+	gt.Expect(errors.Is(err, totalorder.ErrUncommitted)).To(BeTrue())
+}
+
+func TestOrderService_CheckpointUnimplemented(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	orderSvc := NewOrderService(TotalOrderMapAdapter(map[string]namespace.TotalOrder{"order1": &deliverOnly{}}))
+	_, err := orderSvc.Checkpoint(context.Background(), &orderv1.CheckpointRequest{TotalOrder: "order1"})
+	gt.Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+}
+
+type deliverOnly struct{}
+
+func (deliverOnly) Broadcast(context.Context, totalorder.TXIDAndHMAC) error { return nil }
+func (deliverOnly) Deliver(context.Context, uint64) (totalorder.TXIDAndHMAC, error) {
+	return totalorder.TXIDAndHMAC{}, nil
+}
+
+func newOrderService(t *testing.T) (*OrderService, *totalorder.InProcess, func()) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	orderStore, err := totalorder.NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	order := totalorder.NewInProcess(orderStore)
+
+	orderSvc := NewOrderService(TotalOrderMapAdapter(map[string]namespace.TotalOrder{"order1": order}))
+
+	return orderSvc, order, func() {
+		order.Stop()
+		tested.Close(t, db)
+	}
+}
+
+func dialOrderService(t *testing.T, orderSvc *OrderService) *grpc.ClientConn {
+	gt := NewGomegaWithT(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	server := grpc.NewServer()
+	orderv1.RegisterOrderingAPIServer(server, orderSvc)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	gt.Expect(err).NotTo(HaveOccurred())
+	return conn
+}
+
+func serveOrderService(t *testing.T, orderSvc *OrderService) (orderv1.OrderingAPIClient, func()) {
+	conn := dialOrderService(t, orderSvc)
+	return orderv1.NewOrderingAPIClient(conn), func() { conn.Close() }
+}
+
+func testEntry(value string) totalorder.TXIDAndHMAC {
+	id := sha256.Sum256([]byte(value))
+	hmac := sha256.Sum256([]byte(value + "secret"))
+	return totalorder.TXIDAndHMAC{ID: id[:], HMAC: hmac[:]}
+}
@@ -0,0 +1,148 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+	"github.com/sykesm/batik/pkg/totalorder"
+)
+
+// A RaftNode is a member of a raft total order that receives messages from
+// the other members of the cluster.
+type RaftNode interface {
+	Step(totalorder.RaftMessage)
+	// PeerAddress returns the address of the node hosting the member of the
+	// cluster with the provided identifier.
+	PeerAddress(id uint64) (string, bool)
+}
+
+// RaftService implements the RaftAPIServer gRPC interface.
+type RaftService struct {
+	// Unnsafe has been chosed to ensure there's a compilation failure when the
+	// implementation diverges from the gRPC service.
+	orderv1.UnsafeRaftAPIServer
+
+	orders TotalOrderMap
+}
+
+var _ orderv1.RaftAPIServer = (*RaftService)(nil)
+
+// NewRaftService creates a new instance of the RaftService.
+func NewRaftService(orders TotalOrderMap) *RaftService {
+	return &RaftService{
+		orders: orders,
+	}
+}
+
+// Step delivers a raft message to the member of a raft total order hosted by
+// this node. Messages are only delivered when the verified client certificate
+// of the caller is valid for the host of the member that sent the message.
+func (r *RaftService) Step(ctx context.Context, req *orderv1.StepRequest) (*orderv1.StepResponse, error) {
+	cert, err := peerCertificate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	order := r.orders.TotalOrder(req.TotalOrder)
+	if nfo, ok := order.(notFoundTotalOrder); ok {
+		return nil, orderStatus(errors.WithMessagef(errTotalOrderNotFound, "bad total order %q", nfo))
+	}
+	node, ok := order.(RaftNode)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "total order %q is not a raft total order", req.TotalOrder)
+	}
+
+	msg, err := raftMessageFromProto(req.GetMessage())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := authorizeSender(node, msg.From, cert); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	node.Step(msg)
+
+	return &orderv1.StepResponse{}, nil
+}
+
+// peerCertificate returns the verified client certificate of the caller.
+func peerCertificate(ctx context.Context) (*x509.Certificate, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("peer information was not provided")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, errors.New("a verified client certificate is required")
+	}
+	return tlsInfo.State.VerifiedChains[0][0], nil
+}
+
+// authorizeSender ensures that the client certificate was issued for the
+// host of the member of the cluster that sent a message.
+func authorizeSender(node RaftNode, from uint64, cert *x509.Certificate) error {
+	address, ok := node.PeerAddress(from)
+	if !ok {
+		return errors.Errorf("sender %d is not a raft peer", from)
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if err := cert.VerifyHostname(host); err != nil {
+		return errors.WithMessagef(err, "client certificate does not identify raft peer %d", from)
+	}
+	return nil
+}
+
+var raftMessageTypes = map[orderv1.RaftMessageType]totalorder.RaftMessageType{
+	orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE:      totalorder.MsgVote,
+	orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE_RESP: totalorder.MsgVoteResp,
+	orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP:       totalorder.MsgApp,
+	orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP_RESP:  totalorder.MsgAppResp,
+	orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_PROP:      totalorder.MsgProp,
+}
+
+func raftMessageFromProto(msg *orderv1.RaftMessage) (totalorder.RaftMessage, error) {
+	if msg == nil {
+		return totalorder.RaftMessage{}, errors.New("message was not provided")
+	}
+	msgType, ok := raftMessageTypes[msg.Type]
+	if !ok {
+		return totalorder.RaftMessage{}, errors.Errorf("message type %s is not valid", msg.Type)
+	}
+
+	var entries []totalorder.RaftEntry
+	for i, e := range msg.Entries {
+		entry := totalorder.RaftEntry{Term: e.Term}
+		if e.Entry != nil {
+			if len(e.Entry.Txid) != 32 || len(e.Entry.Hmac) != 32 {
+				return totalorder.RaftMessage{}, errors.Errorf("entry %d txid and hmac must be 32 bytes", i)
+			}
+			entry.Data = totalorder.TXIDAndHMAC{ID: e.Entry.Txid, HMAC: e.Entry.Hmac}
+		}
+		entries = append(entries, entry)
+	}
+
+	return totalorder.RaftMessage{
+		Type:    msgType,
+		From:    msg.From,
+		To:      msg.To,
+		Term:    msg.Term,
+		Index:   msg.Index,
+		LogTerm: msg.LogTerm,
+		Entries: entries,
+		Commit:  msg.Commit,
+		Reject:  msg.Reject,
+	}, nil
+}
@@ -0,0 +1,341 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
+)
+
+type recordingRaftNode struct {
+	namespace.TotalOrder
+	messages []totalorder.RaftMessage
+}
+
+func (r *recordingRaftNode) Step(msg totalorder.RaftMessage) {
+	r.messages = append(r.messages, msg)
+}
+
+func (r *recordingRaftNode) PeerAddress(id uint64) (string, bool) {
+	if id != 1 {
+		return "", false
+	}
+	return "127.0.0.1:9445", true
+}
+
+// peerContext returns a context carrying the verified client certificate of
+// a caller.
+func peerContext(t *testing.T, ckp tested.CertKeyPair) context.Context {
+	cert, err := x509.ParseCertificate(ckp.Certificate.Certificate[0])
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestRaftService_Step(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	node := &recordingRaftNode{}
+	raftSvc := NewRaftService(TotalOrderMapAdapter(map[string]namespace.TotalOrder{"raft": node}))
+
+	ckp := tested.NewCA(t, "peer-ca").IssueClientCertificate(t, "raft-1", "127.0.0.1")
+	entry := testEntry("tx1")
+	_, err := raftSvc.Step(peerContext(t, ckp), &orderv1.StepRequest{
+		TotalOrder: "raft",
+		Message: &orderv1.RaftMessage{
+			Type:    orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP,
+			From:    1,
+			To:      2,
+			Term:    3,
+			Index:   4,
+			LogTerm: 2,
+			Entries: []*orderv1.RaftEntry{
+				{Term: 3},
+				{Term: 3, Entry: &orderv1.Entry{Txid: entry.ID, Hmac: entry.HMAC}},
+			},
+			Commit: 4,
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(node.messages).To(Equal([]totalorder.RaftMessage{{
+		Type:    totalorder.MsgApp,
+		From:    1,
+		To:      2,
+		Term:    3,
+		Index:   4,
+		LogTerm: 2,
+		Entries: []totalorder.RaftEntry{{Term: 3}, {Term: 3, Data: entry}},
+		Commit:  4,
+	}}))
+}
+
+func TestRaftService_StepErrors(t *testing.T) {
+	ca := tested.NewCA(t, "peer-ca")
+	peerCtx := peerContext(t, ca.IssueClientCertificate(t, "raft-1", "127.0.0.1"))
+	voteFrom := func(from uint64) *orderv1.StepRequest {
+		return &orderv1.StepRequest{TotalOrder: "raft", Message: &orderv1.RaftMessage{Type: orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE, From: from, To: 2}}
+	}
+
+	tests := map[string]struct {
+		ctx     context.Context
+		req     *orderv1.StepRequest
+		code    codes.Code
+		message string
+	}{
+		"no peer": {
+			ctx:     context.Background(),
+			req:     voteFrom(1),
+			code:    codes.Unauthenticated,
+			message: "peer information was not provided",
+		},
+		"no client certificate": {
+			ctx:     peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}}),
+			req:     voteFrom(1),
+			code:    codes.Unauthenticated,
+			message: "a verified client certificate is required",
+		},
+		"unknown sender": {
+			req:     voteFrom(3),
+			code:    codes.PermissionDenied,
+			message: "sender 3 is not a raft peer",
+		},
+		"impersonated sender": {
+			ctx:     peerContext(t, ca.IssueClientCertificate(t, "raft-3", "10.0.0.3")),
+			req:     voteFrom(1),
+			code:    codes.PermissionDenied,
+			message: "client certificate does not identify raft peer 1: x509: certificate is valid for 10.0.0.3, not 127.0.0.1",
+		},
+		"unknown total order": {
+			req:     &orderv1.StepRequest{TotalOrder: "missing", Message: &orderv1.RaftMessage{Type: orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE}},
+			code:    codes.NotFound,
+			message: "bad total order \"missing\": total order not found",
+		},
+		"not raft": {
+			req:     &orderv1.StepRequest{TotalOrder: "in-process", Message: &orderv1.RaftMessage{Type: orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE}},
+			code:    codes.FailedPrecondition,
+			message: "total order \"in-process\" is not a raft total order",
+		},
+		"missing message": {
+			req:     &orderv1.StepRequest{TotalOrder: "raft"},
+			code:    codes.InvalidArgument,
+			message: "message was not provided",
+		},
+		"unspecified type": {
+			req:     &orderv1.StepRequest{TotalOrder: "raft", Message: &orderv1.RaftMessage{}},
+			code:    codes.InvalidArgument,
+			message: "message type RAFT_MESSAGE_TYPE_UNSPECIFIED is not valid",
+		},
+		"short entry": {
+			req: &orderv1.StepRequest{TotalOrder: "raft", Message: &orderv1.RaftMessage{
+				Type:    orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP,
+				Entries: []*orderv1.RaftEntry{{Term: 1}, {Term: 1, Entry: &orderv1.Entry{Txid: []byte("short"), Hmac: testEntry("tx1").HMAC}}},
+			}},
+			code:    codes.InvalidArgument,
+			message: "entry 1 txid and hmac must be 32 bytes",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			node := &recordingRaftNode{}
+			raftSvc := NewRaftService(TotalOrderMapAdapter(map[string]namespace.TotalOrder{
+				"raft":       node,
+				"in-process": &totalorder.InProcess{},
+			}))
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = peerCtx
+			}
+			_, err := raftSvc.Step(ctx, tt.req)
+			gt.Expect(err).To(HaveOccurred())
+			gt.Expect(status.Code(err)).To(Equal(tt.code))
+			gt.Expect(status.Convert(err).Message()).To(Equal(tt.message))
+			gt.Expect(node.messages).To(BeEmpty())
+		})
+	}
+}
+
+// A peerRaft is a raft node that knows the addresses of its peers.
+type peerRaft struct {
+	*totalorder.Raft
+	addresses map[uint64]string
+}
+
+func (p *peerRaft) PeerAddress(id uint64) (string, bool) {
+	address, ok := p.addresses[id]
+	return address, ok
+}
+
+// A raftNode is a member of a raft cluster that is served by its own gRPC
+// server and sends messages to its peers with a GRPCTransport.
+type raftNode struct {
+	db        *store.LevelDBKV
+	raft      *totalorder.Raft
+	transport *totalorder.GRPCTransport
+	server    *grpc.Server
+	stopped   bool
+}
+
+func (n *raftNode) stop() {
+	if !n.stopped {
+		n.server.Stop()
+		n.raft.Stop()
+		n.transport.Close()
+		n.stopped = true
+	}
+}
+
+func newGRPCRaftCluster(t *testing.T, ids ...uint64) map[uint64]*raftNode {
+	gt := NewGomegaWithT(t)
+
+	ca := tested.NewCA(t, "peer-ca")
+	serverCreds := ca.IssueServerCertificate(t, "server", "127.0.0.1").ServerTLSConfig(t, &ca.Certificate)
+	serverCreds.ClientAuth = tls.RequireAndVerifyClientCert
+	clientCreds := ca.IssueClientCertificate(t, "client", "127.0.0.1").ClientTLSConfig(t, &ca.Certificate)
+
+	listeners := map[uint64]net.Listener{}
+	addresses := map[uint64]string{}
+	conns := map[uint64]*grpc.ClientConn{}
+	for _, id := range ids {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		gt.Expect(err).NotTo(HaveOccurred())
+		listeners[id] = lis
+		addresses[id] = lis.Addr().String()
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(clientCreds)))
+		gt.Expect(err).NotTo(HaveOccurred())
+		conns[id] = conn
+		t.Cleanup(func() { conn.Close() })
+	}
+
+	nodes := map[uint64]*raftNode{}
+	for _, id := range ids {
+		db, err := store.NewLevelDB("")
+		gt.Expect(err).NotTo(HaveOccurred())
+		orderStore, err := totalorder.NewStore(crypto.SHA256, db)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		peers := map[uint64]grpc.ClientConnInterface{}
+		for _, peer := range ids {
+			if peer != id {
+				peers[peer] = conns[peer]
+			}
+		}
+		transport := totalorder.NewGRPCTransport(zap.NewNop(), "raft", peers)
+
+		r, err := totalorder.NewRaft(totalorder.RaftConfig{
+			ID:             id,
+			Peers:          ids,
+			TickInterval:   10 * time.Millisecond,
+			ElectionTicks:  10,
+			HeartbeatTicks: 1,
+		}, zap.NewNop(), orderStore, db, transport)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverCreds)))
+		node := &peerRaft{Raft: r, addresses: addresses}
+		orderv1.RegisterRaftAPIServer(server, NewRaftService(TotalOrderMapAdapter(map[string]namespace.TotalOrder{"raft": node})))
+		go server.Serve(listeners[id])
+
+		n := &raftNode{db: db, raft: r, transport: transport, server: server}
+		nodes[id] = n
+		t.Cleanup(func() {
+			n.stop()
+			tested.Close(t, n.db)
+		})
+		r.Start()
+	}
+	return nodes
+}
+
+// agreedLeader waits for the running nodes to agree on a leader.
+func agreedLeader(gt *GomegaWithT, nodes map[uint64]*raftNode) uint64 {
+	var leader uint64
+	gt.Eventually(func() bool {
+		leader = 0
+		for _, n := range nodes {
+			if n.stopped {
+				continue
+			}
+			l := n.raft.Leader()
+			if l == 0 || nodes[l].stopped || (leader != 0 && l != leader) {
+				return false
+			}
+			leader = l
+		}
+		return true
+	}, 10*time.Second).Should(BeTrue())
+	return leader
+}
+
+func deliverEntries(gt *GomegaWithT, r *totalorder.Raft, count int) []totalorder.TXIDAndHMAC {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var delivered []totalorder.TXIDAndHMAC
+	for seq := 0; seq < count; seq++ {
+		tah, err := r.Deliver(ctx, uint64(seq))
+		gt.Expect(err).NotTo(HaveOccurred())
+		delivered = append(delivered, tah)
+	}
+	return delivered
+}
+
+func TestRaftService_ClusterSurvivesLeaderLoss(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	nodes := newGRPCRaftCluster(t, 1, 2, 3)
+
+	leader := agreedLeader(gt, nodes)
+	for i, name := range []string{"tx0", "tx1", "tx2", "tx3"} {
+		// Alternate between the nodes so that followers forward proposals.
+		id := uint64(i%len(nodes)) + 1
+		err := nodes[id].raft.Broadcast(context.Background(), testEntry(name))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	expected := deliverEntries(gt, nodes[leader].raft, 4)
+	gt.Expect(expected).To(ConsistOf(testEntry("tx0"), testEntry("tx1"), testEntry("tx2"), testEntry("tx3")))
+	for _, n := range nodes {
+		gt.Expect(deliverEntries(gt, n.raft, 4)).To(Equal(expected))
+	}
+
+	// The remaining nodes elect a new leader and continue ordering.
+	nodes[leader].stop()
+	newLeader := agreedLeader(gt, nodes)
+	gt.Expect(newLeader).NotTo(Equal(leader))
+
+	for _, name := range []string{"tx4", "tx5"} {
+		err := nodes[newLeader].raft.Broadcast(context.Background(), testEntry(name))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	expected = append(expected, testEntry("tx4"), testEntry("tx5"))
+	for _, n := range nodes {
+		if !n.stopped {
+			gt.Expect(deliverEntries(gt, n.raft, 6)).To(Equal(expected))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+type StatusReporterMap interface {
+	StatusReporter(namespace string) StatusReporter
+}
+
+type StatusReporter interface {
+	Status(transaction.ID) (*namespace.TxStatus, error)
+}
+
+// StatusService implements the StatusAPIServer gRPC interface.
+type StatusService struct {
+	// Unnsafe has been chosed to ensure there's a compilation failure when the
+	// implementation diverges from the gRPC service.
+	txv1.UnsafeStatusAPIServer
+
+	reporters StatusReporterMap
+}
+
+var _ txv1.StatusAPIServer = (*StatusService)(nil)
+
+// NewStatusService creates a new instance of the StatusService.
+func NewStatusService(reporters StatusReporterMap) *StatusService {
+	return &StatusService{
+		reporters: reporters,
+	}
+}
+
+// GetStatus retrieves the commit processing status of a transaction.
+func (s *StatusService) GetStatus(ctx context.Context, req *txv1.GetStatusRequest) (*txv1.GetStatusResponse, error) {
+	txStatus, err := s.reporters.StatusReporter(req.Namespace).Status(req.Txid)
+	if isNamespaceNotFound(err) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	switch txStatus.State {
+	case namespace.TxPending:
+		return &txv1.GetStatusResponse{Status: txv1.TransactionStatus_TRANSACTION_STATUS_PENDING}, nil
+	case namespace.TxCommitted:
+		return &txv1.GetStatusResponse{
+			Status:     txv1.TransactionStatus_TRANSACTION_STATUS_COMMITTED,
+			Seq:        txStatus.Committed.SeqNo,
+			ReceiptId:  txStatus.Committed.ReceiptID,
+			Validators: txStatus.Committed.Validators,
+		}, nil
+	case namespace.TxRejected:
+		return &txv1.GetStatusResponse{
+			Status:       txv1.TransactionStatus_TRANSACTION_STATUS_REJECTED,
+			Seq:          txStatus.Rejected.SeqNo,
+			ReceiptId:    txStatus.Rejected.ReceiptID,
+			ErrorMessage: txStatus.Rejected.Reason,
+			Validators:   txStatus.Rejected.Validators,
+		}, nil
+	default:
+		return &txv1.GetStatusResponse{Status: txv1.TransactionStatus_TRANSACTION_STATUS_UNKNOWN}, nil
+	}
+}
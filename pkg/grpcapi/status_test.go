@@ -0,0 +1,123 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sykesm/batik/pkg/namespace"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	. "github.com/sykesm/batik/pkg/tested/matcher"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+type statusReporterMap map[string]statusReporterFunc
+
+func (srm statusReporterMap) StatusReporter(namespace string) StatusReporter {
+	sr, ok := srm[namespace]
+	if !ok {
+		return notFoundStatusReporter(namespace)
+	}
+	return sr
+}
+
+type statusReporterFunc func(transaction.ID) (*namespace.TxStatus, error)
+
+func (s statusReporterFunc) Status(id transaction.ID) (*namespace.TxStatus, error) {
+	return s(id)
+}
+
+func TestStatusService_GetStatus(t *testing.T) {
+	tests := map[string]struct {
+		namespace string
+		txStatus  *namespace.TxStatus
+		statusErr error
+		expected  *txv1.GetStatusResponse
+		errCode   codes.Code
+	}{
+		"unknown": {
+			txStatus: &namespace.TxStatus{State: namespace.TxUnknown},
+			expected: &txv1.GetStatusResponse{Status: txv1.TransactionStatus_TRANSACTION_STATUS_UNKNOWN},
+		},
+		"pending": {
+			txStatus: &namespace.TxStatus{State: namespace.TxPending},
+			expected: &txv1.GetStatusResponse{Status: txv1.TransactionStatus_TRANSACTION_STATUS_PENDING},
+		},
+		"committed": {
+			txStatus: &namespace.TxStatus{
+				State:     namespace.TxCommitted,
+				Committed: &transaction.Committed{SeqNo: 5, ReceiptID: []byte("receipt-id"), Validators: []string{"v1", "v2"}},
+			},
+			expected: &txv1.GetStatusResponse{
+				Status:     txv1.TransactionStatus_TRANSACTION_STATUS_COMMITTED,
+				Seq:        5,
+				ReceiptId:  []byte("receipt-id"),
+				Validators: []string{"v1", "v2"},
+			},
+		},
+		"rejected": {
+			txStatus: &namespace.TxStatus{
+				State:    namespace.TxRejected,
+				Rejected: &transaction.Rejected{SeqNo: 6, ReceiptID: []byte("receipt-id"), Reason: "validation failed"},
+			},
+			expected: &txv1.GetStatusResponse{
+				Status:       txv1.TransactionStatus_TRANSACTION_STATUS_REJECTED,
+				Seq:          6,
+				ReceiptId:    []byte("receipt-id"),
+				ErrorMessage: "validation failed",
+			},
+		},
+		"rejected by validators": {
+			txStatus: &namespace.TxStatus{
+				State:    namespace.TxRejected,
+				Rejected: &transaction.Rejected{SeqNo: 6, ReceiptID: []byte("receipt-id"), Reason: "validation failed: v1: bad; v2,beta: worse", Validators: []string{"v1", "v2,beta"}},
+			},
+			expected: &txv1.GetStatusResponse{
+				Status:       txv1.TransactionStatus_TRANSACTION_STATUS_REJECTED,
+				Seq:          6,
+				ReceiptId:    []byte("receipt-id"),
+				ErrorMessage: "validation failed: v1: bad; v2,beta: worse",
+				Validators:   []string{"v1", "v2,beta"},
+			},
+		},
+		"unknown namespace": {
+			namespace: "missing",
+			errCode:   codes.InvalidArgument,
+		},
+		"status failure": {
+			statusErr: errors.New("boom"),
+			errCode:   codes.Internal,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			var reporter statusReporterFunc = func(id transaction.ID) (*namespace.TxStatus, error) {
+				gt.Expect(id).To(Equal(transaction.ID("txid")))
+				return tt.txStatus, tt.statusErr
+			}
+			ss := NewStatusService(statusReporterMap{"namespace": reporter})
+
+			ns := "namespace"
+			if tt.namespace != "" {
+				ns = tt.namespace
+			}
+			resp, err := ss.GetStatus(context.Background(), &txv1.GetStatusRequest{Namespace: ns, Txid: []byte("txid")})
+			if tt.errCode != codes.OK {
+				gt.Expect(status.Code(err)).To(Equal(tt.errCode))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(resp).To(ProtoEqual(tt.expected))
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/sykesm/batik/pkg/merkle"
 	storev1 "github.com/sykesm/batik/pkg/pb/store/v1"
@@ -24,6 +25,8 @@ type Repository interface {
 	GetTransaction(transaction.ID) (*transaction.Transaction, error)
 	PutState(*transaction.State) error
 	GetState(transaction.StateID, bool) (*transaction.State, error)
+	GetRejected(transaction.ID) (*transaction.Rejected, error)
+	GetCommitted(transaction.ID) (*transaction.Committed, error)
 }
 
 // StoreService implements the StoreAPIServer gRPC interface.
@@ -97,3 +100,49 @@ func (s *StoreService) PutState(ctx context.Context, req *storev1.PutStateReques
 
 	return &storev1.PutStateResponse{}, nil
 }
+
+// GetRejection retrieves the record of a transaction that failed commit
+// processing.
+func (s *StoreService) GetRejection(ctx context.Context, req *storev1.GetRejectionRequest) (*storev1.GetRejectionResponse, error) {
+	rejected, err := s.repos.Repository(req.Namespace).GetRejected(req.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storev1.GetRejectionResponse{
+		Rejection: &storev1.Rejection{
+			ReceiptId:    rejected.ReceiptID,
+			Seq:          rejected.SeqNo,
+			Reason:       rejected.Reason,
+			Validators:   rejected.Validators,
+			ErrorMessage: rejected.ErrorMessage,
+			Timestamp:    timestamppb.New(rejected.Timestamp),
+		},
+	}, nil
+}
+
+// GetAttestation retrieves the node's signed attestation for the commit of a
+// transaction. Commits are attested after they are committed so a recent
+// commit may not have been attested yet.
+func (s *StoreService) GetAttestation(ctx context.Context, req *storev1.GetAttestationRequest) (*storev1.GetAttestationResponse, error) {
+	committed, err := s.repos.Repository(req.Namespace).GetCommitted(req.Txid)
+	if err != nil {
+		return nil, err
+	}
+	a := committed.Attestation
+	if a == nil {
+		return nil, status.Errorf(codes.NotFound, "transaction %x has not been attested", req.Txid)
+	}
+
+	return &storev1.GetAttestationResponse{
+		Attestation: &storev1.Attestation{
+			Namespace:   a.Namespace,
+			Seq:         a.SeqNo,
+			Txid:        a.TxID,
+			ReceiptId:   a.ReceiptID,
+			Accumulator: a.Accumulator,
+			PublicKey:   a.PublicKey,
+			Signature:   a.Signature,
+		},
+	}, nil
+}
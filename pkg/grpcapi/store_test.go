@@ -6,16 +6,25 @@ package grpcapi
 import (
 	"context"
 	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/sykesm/batik/pkg/ecdsautil"
 	"github.com/sykesm/batik/pkg/namespace"
 	storev1 "github.com/sykesm/batik/pkg/pb/store/v1"
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
 	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/tested"
 	. "github.com/sykesm/batik/pkg/tested/matcher"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
 	"github.com/sykesm/batik/pkg/validator"
 )
@@ -133,17 +142,109 @@ func TestStoreService_PutState(t *testing.T) {
 	gt.Expect(state.Data).To(Equal(testState.State))
 }
 
+func TestStoreService_GetRejection(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	storeSvc, cleanup := newStoreService(t)
+	defer cleanup()
+
+	req := &storev1.GetRejectionRequest{
+		Namespace: "ns1",
+		Txid:      []byte("rejected-txid"),
+	}
+	_, err := storeSvc.GetRejection(context.Background(), req)
+	gt.Expect(err).To(MatchError(ContainSubstring("leveldb: not found")))
+
+	rejected := &transaction.Rejected{
+		ReceiptID:    []byte("receipt-id"),
+		SeqNo:        9,
+		Reason:       "validation failed: no-signature",
+		Validators:   []string{"signature-builtin", "rules,v2"},
+		ErrorMessage: "no-signature",
+		Timestamp:    time.Date(2021, time.January, 2, 3, 4, 5, 6, time.UTC),
+	}
+	repo := storeSvc.repos.Repository("ns1").(namespaceRepository).ns.Repo
+	err = repo.PutRejected(transaction.ID("rejected-txid"), rejected)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := storeSvc.GetRejection(context.Background(), req)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp.Rejection).To(ProtoEqual(&storev1.Rejection{
+		ReceiptId:    []byte("receipt-id"),
+		Seq:          9,
+		Reason:       "validation failed: no-signature",
+		Validators:   []string{"signature-builtin", "rules,v2"},
+		ErrorMessage: "no-signature",
+		Timestamp:    &timestamppb.Timestamp{Seconds: 1609556645, Nanos: 6},
+	}))
+
+	req.Namespace = "missing"
+	_, err = storeSvc.GetRejection(context.Background(), req)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
+func TestStoreService_GetAttestation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	storeSvc, cleanup := newStoreService(t)
+	defer cleanup()
+
+	req := &storev1.GetAttestationRequest{
+		Namespace: "ns1",
+		Txid:      []byte("committed-txid"),
+	}
+	_, err := storeSvc.GetAttestation(context.Background(), req)
+	gt.Expect(err).To(MatchError(ContainSubstring("leveldb: not found")))
+
+	repo := storeSvc.repos.Repository("ns1").(namespaceRepository).ns.Repo
+	err = repo.PutCommitted(transaction.ID("committed-txid"), &transaction.Committed{ReceiptID: []byte("receipt-id"), SeqNo: 9})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = storeSvc.GetAttestation(context.Background(), req)
+	gt.Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+	key, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	attestation, err := transaction.NewAttestation(key, "ns1", 9, transaction.ID("committed-txid"), []byte("receipt-id"), []byte("accumulator"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = repo.PutCommitted(transaction.ID("committed-txid"), &transaction.Committed{ReceiptID: []byte("receipt-id"), SeqNo: 9, Attestation: attestation})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := storeSvc.GetAttestation(context.Background(), req)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resp.Attestation).To(ProtoEqual(&storev1.Attestation{
+		Namespace:   "ns1",
+		Seq:         9,
+		Txid:        []byte("committed-txid"),
+		ReceiptId:   []byte("receipt-id"),
+		Accumulator: []byte("accumulator"),
+		PublicKey:   attestation.PublicKey,
+		Signature:   attestation.Signature,
+	}))
+
+	req.Namespace = "missing"
+	_, err = storeSvc.GetAttestation(context.Background(), req)
+	gt.Expect(err).To(MatchError("bad namespace \"missing\": namespace not found"))
+}
+
 func newStoreService(t *testing.T) (*StoreService, func()) {
 	path, cleanup := tested.TempDir(t, "", "level")
 
 	db, err := store.NewLevelDB(path)
 	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
 
-	ns := namespace.New(nil, crypto.SHA256, db, validator.NewSignature())
+	orderDB, err := store.NewLevelDB("")
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	orderStore, err := totalorder.NewStore(crypto.SHA256, orderDB)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	order := totalorder.NewInProcess(orderStore)
+
+	ns := namespace.New("ns1", zap.NewNop(), crypto.SHA256, db, namespace.ValidatorChain{{Name: "signature-builtin", Validator: validator.NewSignature()}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
 
 	storeSvc := NewStoreService(NamespaceMapAdapter(map[string]*namespace.Namespace{"ns1": ns}))
 
 	return storeSvc, func() {
+		ns.Stop()
+		order.Stop()
+		tested.Close(t, orderDB)
 		tested.Close(t, db)
 		cleanup()
 	}
@@ -6,11 +6,15 @@ package grpcapi
 import (
 	"context"
 	"crypto"
+	"strconv"
 
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/sykesm/batik/pkg/merkle"
+	"github.com/sykesm/batik/pkg/namespace"
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
 	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/transaction"
@@ -24,6 +28,14 @@ type Submitter interface {
 	Submit(ctx context.Context, signedTx *transaction.Signed) error
 }
 
+type SimulatorMap interface {
+	Simulator(namespace string) Simulator
+}
+
+type Simulator interface {
+	Simulate(signedTx *transaction.Signed) (*namespace.Simulation, error)
+}
+
 // SubmitService implements the EncodeAPIServer gRPC interface.
 type SubmitService struct {
 	// Unnsafe has been chosed to ensure there's a compilation failure when the
@@ -35,15 +47,19 @@ type SubmitService struct {
 	// submitters are the set of domain specific transaction processors asociated
 	// with each namespace
 	submitters SubmitterMap
+	// simulators resolve and validate transactions for each namespace without
+	// committing them
+	simulators SimulatorMap
 }
 
 var _ txv1.SubmitAPIServer = (*SubmitService)(nil)
 
 // NewSubmitService creates a new instance of the SubmitService.
-func NewSubmitService(submitters SubmitterMap) *SubmitService {
+func NewSubmitService(submitters SubmitterMap, simulators SimulatorMap) *SubmitService {
 	return &SubmitService{
 		hasher:     crypto.SHA256,
 		submitters: submitters,
+		simulators: simulators,
 	}
 }
 
@@ -69,6 +85,10 @@ func (s *SubmitService) Submit(ctx context.Context, req *txv1.SubmitRequest) (*t
 		Signatures:  transaction.ToSignatures(signedTx.Signatures...),
 	}
 	err = s.submitters.Submitter(req.Namespace).Submit(ctx, signed)
+	var stateErr *namespace.StateError
+	if errors.As(err, &stateErr) {
+		return nil, stateErrorStatus(itx.ID, stateErr, err)
+	}
 	if err != nil {
 		code := codes.Unknown
 		switch {
@@ -78,9 +98,110 @@ func (s *SubmitService) Submit(ctx context.Context, req *txv1.SubmitRequest) (*t
 			code = codes.FailedPrecondition
 		case isNamespaceNotFound(err):
 			code = codes.InvalidArgument
+		case errors.Is(err, namespace.ErrNamespaceHalted), errors.Is(err, namespace.ErrHalt):
+			code = codes.Unavailable
 		}
 		return nil, status.Errorf(code, "storing transaction %s failed: %s", itx.ID, err)
 	}
 
 	return &txv1.SubmitResponse{Txid: itx.ID}, nil
 }
+
+// Simulate resolves and validates a transaction against the current state of
+// the namespace without storing, ordering, or committing it.
+func (s *SubmitService) Simulate(ctx context.Context, req *txv1.SimulateRequest) (*txv1.SimulateResponse, error) {
+	signedTx := req.GetSignedTransaction()
+	if signedTx == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "signed transaction was not provided")
+	}
+	tx := signedTx.GetTransaction()
+	if tx == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "transaction was not provided")
+	}
+	itx, err := transaction.New(s.hasher, tx)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	sim, err := s.simulators.Simulator(req.Namespace).Simulate(&transaction.Signed{
+		Transaction: itx,
+		Signatures:  transaction.ToSignatures(signedTx.Signatures...),
+	})
+	if isNamespaceNotFound(err) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "simulating transaction %s failed: %s", itx.ID, err)
+	}
+
+	resp := &txv1.SimulateResponse{
+		Txid:       itx.ID,
+		Inputs:     simulatedStates(sim.Resolved.Inputs),
+		References: simulatedStates(sim.Resolved.References),
+	}
+	if sim.Response != nil {
+		resp.Validation = &txv1.SimulatedValidation{
+			Valid:        sim.Response.Valid,
+			ErrorMessage: sim.Response.ErrorMessage,
+		}
+	}
+	for _, missing := range sim.Missing {
+		ms := &txv1.MissingState{
+			Reference: transaction.FromStateID(&missing.StateID),
+			Reason:    ReasonStateUnknown,
+		}
+		if errors.Is(missing, namespace.ErrStateConsumed) {
+			ms.Reason = ReasonStateConsumed
+			ms.ConsumedBy = missing.ConsumedBy
+		}
+		resp.Missing = append(resp.Missing, ms)
+	}
+
+	return resp, nil
+}
+
+func simulatedStates(states []*transaction.State) []*txv1.SimulatedState {
+	var simulated []*txv1.SimulatedState
+	for _, state := range states {
+		simulated = append(simulated, &txv1.SimulatedState{
+			Reference: transaction.FromStateID(&state.ID),
+			State:     transaction.FromState(state),
+		})
+	}
+	return simulated
+}
+
+// Reasons reported in the ErrorInfo details of the status returned when a
+// transaction input or reference can not be resolved and in the missing
+// states of a simulation.
+const (
+	ReasonStateConsumed = "STATE_CONSUMED"
+	ReasonStateUnknown  = "STATE_UNKNOWN"
+)
+
+// stateErrorStatus returns a FailedPrecondition status error for a
+// transaction that references a consumed or unknown state. The ErrorInfo
+// details identify the state and, for consumed states, the consuming
+// transaction.
+func stateErrorStatus(txid transaction.ID, se *namespace.StateError, err error) error {
+	info := &errdetails.ErrorInfo{
+		Reason: ReasonStateUnknown,
+		Domain: "batik",
+		Metadata: map[string]string{
+			"txid":         se.StateID.TxID.String(),
+			"output_index": strconv.FormatUint(se.StateID.OutputIndex, 10),
+		},
+	}
+	if errors.Is(se, namespace.ErrStateConsumed) {
+		info.Reason = ReasonStateConsumed
+		if se.ConsumedBy != nil {
+			info.Metadata["consumed_by"] = se.ConsumedBy.String()
+		}
+	}
+
+	st, derr := status.Newf(codes.FailedPrecondition, "storing transaction %s failed: %s", txid, err).WithDetails(info)
+	if derr != nil {
+		return status.Errorf(codes.FailedPrecondition, "storing transaction %s failed: %s", txid, err)
+	}
+	return st.Err()
+}
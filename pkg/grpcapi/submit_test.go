@@ -7,18 +7,26 @@ import (
 	"context"
 	"crypto"
 	"errors"
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/sykesm/batik/pkg/namespace"
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
 	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
 	. "github.com/sykesm/batik/pkg/tested/matcher"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
+	"github.com/sykesm/batik/pkg/validator"
 )
 
 type submitMapAdapter map[string]submitterFunc
@@ -38,6 +46,23 @@ func (s submitterFunc) Submit(ctx context.Context, tx *transaction.Signed) error
 	return s(ctx, tx)
 }
 
+type simulatorMap map[string]simulatorFunc
+
+func (sm simulatorMap) Simulator(namespace string) Simulator {
+	s, ok := sm[namespace]
+	if !ok {
+		return notFoundSimulator(namespace)
+	}
+
+	return s
+}
+
+type simulatorFunc func(*transaction.Signed) (*namespace.Simulation, error)
+
+func (s simulatorFunc) Simulate(tx *transaction.Signed) (*namespace.Simulation, error) {
+	return s(tx)
+}
+
 func TestSubmit(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
@@ -58,11 +83,18 @@ func TestSubmit(t *testing.T) {
 		},
 	}
 
+	stateID := transaction.StateID{TxID: transaction.ID("input"), OutputIndex: 1}
+	notFound := &store.NotFoundError{Err: errors.New("not-found")}
+	consumedErr := namespace.NewStateError(&store.StateConsumedError{StateID: stateID, ConsumedBy: transaction.ID("consumer"), Err: notFound})
+	unknownErr := namespace.NewStateError(&store.StateUnknownError{StateID: stateID, Err: notFound})
+
 	tests := map[string]struct {
 		setup      func(sr *txv1.SubmitRequest)
 		submitErr  error
 		resp       *txv1.SubmitResponse
 		errMatcher types.GomegaMatcher
+		code       codes.Code
+		details    []proto.Message
 	}{
 		"nil signed transaction": {
 			setup:      func(sr *txv1.SubmitRequest) { sr.SignedTransaction = nil },
@@ -91,6 +123,41 @@ func TestSubmit(t *testing.T) {
 			submitErr:  &store.NotFoundError{Err: errors.New("not-found")},
 			errMatcher: MatchError(status.Errorf(codes.FailedPrecondition, "storing transaction %s failed: not-found", tx.ID)),
 		},
+		"consumed state error": {
+			submitErr:  consumedErr,
+			errMatcher: MatchError(ContainSubstring("storing transaction %s failed: state consumed: ", tx.ID)),
+			code:       codes.FailedPrecondition,
+			details: []proto.Message{&errdetails.ErrorInfo{
+				Reason: ReasonStateConsumed,
+				Domain: "batik",
+				Metadata: map[string]string{
+					"txid":         "696e707574",
+					"output_index": "1",
+					"consumed_by":  "636f6e73756d6572",
+				},
+			}},
+		},
+		"unknown state error": {
+			submitErr:  unknownErr,
+			errMatcher: MatchError(ContainSubstring("storing transaction %s failed: state unknown: ", tx.ID)),
+			code:       codes.FailedPrecondition,
+			details: []proto.Message{&errdetails.ErrorInfo{
+				Reason: ReasonStateUnknown,
+				Domain: "batik",
+				Metadata: map[string]string{
+					"txid":         "696e707574",
+					"output_index": "1",
+				},
+			}},
+		},
+		"halted": {
+			submitErr:  fmt.Errorf("commit processing halted at seq 3: boom: %w", namespace.ErrNamespaceHalted),
+			errMatcher: MatchError(status.Errorf(codes.Unavailable, "storing transaction %s failed: commit processing halted at seq 3: boom: namespace halted", tx.ID)),
+		},
+		"halting": {
+			submitErr:  fmt.Errorf("validation failed: %w", namespace.ErrHalt),
+			errMatcher: MatchError(status.Errorf(codes.Unavailable, "storing transaction %s failed: validation failed: halt processing", tx.ID)),
+		},
 		"unknown error": {
 			submitErr:  errors.New("woops"),
 			errMatcher: MatchError(status.Errorf(codes.Unknown, "storing transaction %s failed: woops", tx.ID)),
@@ -109,9 +176,215 @@ func TestSubmit(t *testing.T) {
 			}
 			ss := NewSubmitService(submitMapAdapter(map[string]submitterFunc{
 				"namespace": submitter,
-			}))
+			}), simulatorMap{})
 
 			resp, err := ss.Submit(context.Background(), req)
+			if tt.errMatcher != nil {
+				gt.Expect(err).To(tt.errMatcher)
+				if tt.details != nil {
+					st, ok := status.FromError(err)
+					gt.Expect(ok).To(BeTrue())
+					gt.Expect(st.Code()).To(Equal(tt.code))
+					gt.Expect(st.Details()).To(HaveLen(len(tt.details)))
+					for i, detail := range st.Details() {
+						gt.Expect(detail).To(ProtoEqual(tt.details[i]))
+					}
+				}
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(resp).To(ProtoEqual(tt.resp))
+		})
+	}
+}
+
+func TestSubmit_Resubmit(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db := store.NewMemoryKV()
+	defer tested.Close(t, db)
+	orderDB := store.NewMemoryKV()
+	defer tested.Close(t, orderDB)
+	orderStore, err := totalorder.NewStore(crypto.SHA256, orderDB)
+	gt.Expect(err).NotTo(HaveOccurred())
+	order := totalorder.NewInProcess(orderStore)
+	defer order.Stop()
+
+	ns := namespace.New("ns1", zap.NewNop(), crypto.SHA256, db, namespace.ValidatorChain{{Name: "signature-builtin", Validator: validator.NewSignature()}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	ss := NewSubmitService(NamespaceMapAdapter(map[string]*namespace.Namespace{"ns1": ns}), nil)
+
+	// The inputs of the transaction do not exist.
+	req := &txv1.SubmitRequest{
+		Namespace:         "ns1",
+		SignedTransaction: &txv1.SignedTransaction{Transaction: newTestTransaction()},
+	}
+	expected := &errdetails.ErrorInfo{
+		Reason: ReasonStateUnknown,
+		Domain: "batik",
+		Metadata: map[string]string{
+			"txid":         transaction.ID("input-transaction-id-0").String(),
+			"output_index": "1",
+		},
+	}
+
+	// The rejection is reported the same way when the transaction is
+	// submitted again.
+	for i := 0; i < 2; i++ {
+		_, err := ss.Submit(context.Background(), req)
+		st, ok := status.FromError(err)
+		gt.Expect(ok).To(BeTrue())
+		gt.Expect(st.Code()).To(Equal(codes.FailedPrecondition))
+		gt.Expect(st.Message()).To(ContainSubstring("state unknown"))
+		gt.Expect(st.Details()).To(HaveLen(1))
+		gt.Expect(st.Details()[0]).To(ProtoEqual(expected))
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+		Salt: []byte("potassium permanganate (KMnO4) is a salt"),
+		Inputs: []*txv1.StateReference{
+			{Txid: []byte("input"), OutputIndex: 0},
+			{Txid: []byte("input"), OutputIndex: 1},
+			{Txid: []byte("input"), OutputIndex: 2},
+		},
+		References: []*txv1.StateReference{{Txid: []byte("reference"), OutputIndex: 0}},
+		Outputs: []*txv1.State{{
+			Info:  &txv1.StateInfo{Kind: "test-kind"},
+			State: []byte("test-state-1"),
+		}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	simulateRequest := &txv1.SimulateRequest{
+		Namespace: "namespace",
+		SignedTransaction: &txv1.SignedTransaction{
+			Transaction: tx.Tx,
+			Signatures:  []*txv1.Signature{{PublicKey: []byte("public-key"), Signature: []byte("signature")}},
+		},
+	}
+
+	input := &transaction.State{
+		ID:        transaction.StateID{TxID: transaction.ID("input"), OutputIndex: 0},
+		StateInfo: &transaction.StateInfo{Kind: "input-kind"},
+		Data:      []byte("input-state"),
+	}
+	reference := &transaction.State{
+		ID:        transaction.StateID{TxID: transaction.ID("reference"), OutputIndex: 0},
+		StateInfo: &transaction.StateInfo{Kind: "reference-kind"},
+		Data:      []byte("reference-state"),
+	}
+	notFound := &store.NotFoundError{Err: errors.New("not-found")}
+	consumedErr := namespace.NewStateError(&store.StateConsumedError{
+		StateID:    transaction.StateID{TxID: transaction.ID("input"), OutputIndex: 1},
+		ConsumedBy: transaction.ID("consumer"),
+		Err:        notFound,
+	}).(*namespace.StateError)
+	unknownErr := namespace.NewStateError(&store.StateUnknownError{
+		StateID: transaction.StateID{TxID: transaction.ID("input"), OutputIndex: 2},
+		Err:     notFound,
+	}).(*namespace.StateError)
+
+	resolvedInputs := []*txv1.SimulatedState{{
+		Reference: &txv1.StateReference{Txid: []byte("input"), OutputIndex: 0},
+		State:     &txv1.State{Info: &txv1.StateInfo{Kind: "input-kind"}, State: []byte("input-state")},
+	}}
+	resolvedRefs := []*txv1.SimulatedState{{
+		Reference: &txv1.StateReference{Txid: []byte("reference"), OutputIndex: 0},
+		State:     &txv1.State{Info: &txv1.StateInfo{Kind: "reference-kind"}, State: []byte("reference-state")},
+	}}
+
+	tests := map[string]struct {
+		setup       func(sr *txv1.SimulateRequest)
+		simulation  *namespace.Simulation
+		simulateErr error
+		resp        *txv1.SimulateResponse
+		errMatcher  types.GomegaMatcher
+	}{
+		"nil signed transaction": {
+			setup:      func(sr *txv1.SimulateRequest) { sr.SignedTransaction = nil },
+			errMatcher: MatchError(status.Errorf(codes.InvalidArgument, "signed transaction was not provided")),
+		},
+		"nil transaction": {
+			setup:      func(sr *txv1.SimulateRequest) { sr.SignedTransaction.Transaction = nil },
+			errMatcher: MatchError(status.Errorf(codes.InvalidArgument, "transaction was not provided")),
+		},
+		"unknown namespace": {
+			setup:      func(sr *txv1.SimulateRequest) { sr.Namespace = "missing" },
+			errMatcher: MatchError(status.Errorf(codes.InvalidArgument, "bad namespace \"missing\": namespace not found")),
+		},
+		"simulation failure": {
+			simulateErr: errors.New("woops"),
+			errMatcher:  MatchError(status.Errorf(codes.Internal, "simulating transaction %s failed: woops", tx.ID)),
+		},
+		"valid": {
+			simulation: &namespace.Simulation{
+				Resolved: &transaction.Resolved{Inputs: []*transaction.State{input}, References: []*transaction.State{reference}},
+				Response: &validationv1.ValidateResponse{Valid: true},
+			},
+			resp: &txv1.SimulateResponse{
+				Txid:       tx.ID,
+				Validation: &txv1.SimulatedValidation{Valid: true},
+				Inputs:     resolvedInputs,
+				References: resolvedRefs,
+			},
+		},
+		"invalid": {
+			simulation: &namespace.Simulation{
+				Resolved: &transaction.Resolved{Inputs: []*transaction.State{input}, References: []*transaction.State{reference}},
+				Response: &validationv1.ValidateResponse{Valid: false, ErrorMessage: "bad-signature"},
+			},
+			resp: &txv1.SimulateResponse{
+				Txid:       tx.ID,
+				Validation: &txv1.SimulatedValidation{Valid: false, ErrorMessage: "bad-signature"},
+				Inputs:     resolvedInputs,
+				References: resolvedRefs,
+			},
+		},
+		"missing states": {
+			simulation: &namespace.Simulation{
+				Resolved: &transaction.Resolved{Inputs: []*transaction.State{input}, References: []*transaction.State{reference}},
+				Missing:  []*namespace.StateError{consumedErr, unknownErr},
+			},
+			resp: &txv1.SimulateResponse{
+				Txid:       tx.ID,
+				Inputs:     resolvedInputs,
+				References: resolvedRefs,
+				Missing: []*txv1.MissingState{
+					{
+						Reference:  &txv1.StateReference{Txid: []byte("input"), OutputIndex: 1},
+						Reason:     ReasonStateConsumed,
+						ConsumedBy: []byte("consumer"),
+					},
+					{
+						Reference: &txv1.StateReference{Txid: []byte("input"), OutputIndex: 2},
+						Reason:    ReasonStateUnknown,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			req := proto.Clone(simulateRequest).(*txv1.SimulateRequest)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			var simulator simulatorFunc = func(signed *transaction.Signed) (*namespace.Simulation, error) {
+				gt.Expect(signed.Transaction.ID).To(Equal(tx.ID))
+				gt.Expect(signed.Signatures).To(Equal([]*transaction.Signature{{PublicKey: []byte("public-key"), Signature: []byte("signature")}}))
+				return tt.simulation, tt.simulateErr
+			}
+			ss := NewSubmitService(submitMapAdapter{}, simulatorMap{"namespace": simulator})
+
+			resp, err := ss.Simulate(context.Background(), req)
 			if tt.errMatcher != nil {
 				gt.Expect(err).To(tt.errMatcher)
 				return
@@ -0,0 +1,169 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/totalorder"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+const (
+	// DefaultCheckpointTimeout bounds the time spent retrieving the
+	// checkpoint for a single attestation.
+	DefaultCheckpointTimeout = 10 * time.Second
+	// DefaultAttestRetryInterval is the time to wait before attesting again
+	// after an attestation fails.
+	DefaultAttestRetryInterval = time.Second
+)
+
+// An attestor signs attestations for the transactions a namespace commits
+// with the identity key of the node.
+type attestor struct {
+	namespace   string
+	key         *ecdsa.PrivateKey
+	checkpoints totalorder.Checkpointer // checkpoints provide the accumulator of the total order; may be nil
+	timeout     time.Duration           // timeout bounds each checkpoint request
+	retry       time.Duration           // retry is the delay before attesting again after a failure
+}
+
+// newAttestor creates an attestor for a namespace. When the key is nil,
+// commits are not attested and nil is returned. When the total order does not
+// provide checkpoints, attestations do not include an accumulator.
+func newAttestor(namespace string, key *ecdsa.PrivateKey, order TotalOrder) *attestor {
+	if key == nil {
+		return nil
+	}
+	checkpoints, _ := order.(totalorder.Checkpointer)
+	return &attestor{
+		namespace:   namespace,
+		key:         key,
+		checkpoints: checkpoints,
+		timeout:     DefaultCheckpointTimeout,
+		retry:       DefaultAttestRetryInterval,
+	}
+}
+
+// attest signs an attestation for the commit of a transaction at a sequence.
+// The checkpoint request is bounded by the attestor timeout and canceled with
+// the provided context. A nil attestor produces a nil attestation.
+func (a *attestor) attest(ctx context.Context, seqNo uint64, txID transaction.ID, receiptID []byte) (*transaction.Attestation, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	var accumulator []byte
+	if a.checkpoints != nil {
+		ctx, cancel := context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+
+		checkpoint, err := a.checkpoints.Checkpoint(ctx, seqNo)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to retrieve checkpoint for seq %d", seqNo)
+		}
+		accumulator = checkpoint.Accumulator
+	}
+
+	return transaction.NewAttestation(a.key, a.namespace, seqNo, txID, receiptID, accumulator)
+}
+
+// attestCommits attests the committed transactions that have not been
+// attested, in sequence order, until the context is done. Attestation trails
+// commit processing so a slow or unavailable total order delays attestations
+// instead of commits. Failed attestations are retried; they do not halt the
+// namespace.
+func (ns *Namespace) attestCommits(ctx context.Context) {
+	var next uint64
+	var resumed bool
+	for {
+		// The notification channel is acquired before the repository is
+		// read so a commit can not be missed between the two.
+		commitC := ns.commits()
+
+		var err error
+		if !resumed {
+			next, err = ns.nextAttestSeq()
+			resumed = err == nil
+		}
+		if err == nil {
+			next, err = ns.attestPending(ctx, next)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			ns.Logger.Warn("attestation failed", zap.Uint64("seq", next), zap.Error(err))
+			select {
+			case <-time.After(ns.attestor.retry):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-commitC:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// nextAttestSeq returns the sequence number that follows the last attested
+// commit.
+func (ns *Namespace) nextAttestSeq() (uint64, error) {
+	last, err := ns.Repo.GetLastAttestedSeq()
+	if store.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get last attested sequence")
+	}
+	return last + 1, nil
+}
+
+// attestPending attests the transactions committed from the next sequence
+// number through the last committed sequence number. The sequence number of
+// the first commit that was not attested is returned.
+func (ns *Namespace) attestPending(ctx context.Context, next uint64) (uint64, error) {
+	last, err := ns.Repo.GetLastCommittedSeq()
+	if store.IsNotFound(err) {
+		return next, nil
+	}
+	if err != nil {
+		return next, errors.WithMessage(err, "failed to get last committed sequence")
+	}
+
+	for ; next <= last; next++ {
+		txID, err := ns.Repo.GetCommittedTxID(next)
+		if store.IsNotFound(err) {
+			// Rejected and unauthenticated entries are not committed.
+			continue
+		}
+		if err != nil {
+			return next, err
+		}
+		committed, err := ns.Repo.GetCommitted(txID)
+		if err != nil {
+			return next, errors.WithMessagef(err, "failed to get commit record for transaction %s", txID)
+		}
+
+		attestation, err := ns.attestor.attest(ctx, next, txID, committed.ReceiptID)
+		if err != nil {
+			return next, err
+		}
+		err = ns.Repo.PutAttestation(txID, attestation)
+		if err != nil {
+			return next, errors.WithMessagef(err, "failed to store attestation for transaction %s", txID)
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,102 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/ecdsautil"
+	"github.com/sykesm/batik/pkg/totalorder"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// checkpointOrder is a total order that provides checkpoints.
+type checkpointOrder struct {
+	TotalOrder
+	accumulator []byte
+	err         error
+	block       bool
+	seq         uint64
+}
+
+func (c *checkpointOrder) Checkpoint(ctx context.Context, seq uint64) (totalorder.Checkpoint, error) {
+	c.seq = seq
+	if c.block {
+		<-ctx.Done()
+		return totalorder.Checkpoint{}, ctx.Err()
+	}
+	return totalorder.Checkpoint{Seq: seq, Accumulator: c.accumulator}, c.err
+}
+
+func (c *checkpointOrder) LatestCheckpoint(ctx context.Context) (totalorder.Checkpoint, error) {
+	return totalorder.Checkpoint{}, errors.New("not implemented")
+}
+
+func newTestIdentity(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	return key
+}
+
+func TestAttestor(t *testing.T) {
+	key := newTestIdentity(t)
+
+	tests := map[string]struct {
+		key         *ecdsa.PrivateKey
+		order       TotalOrder
+		accumulator []byte
+		errMatch    string
+	}{
+		"no identity":    {order: &checkpointOrder{accumulator: []byte("accumulator")}},
+		"no checkpoints": {key: key, order: struct{ TotalOrder }{}},
+		"checkpoints":    {key: key, order: &checkpointOrder{accumulator: []byte("accumulator")}, accumulator: []byte("accumulator")},
+		"checkpoint failure": {
+			key:      key,
+			order:    &checkpointOrder{err: errors.New("boom")},
+			errMatch: "failed to retrieve checkpoint for seq 7: boom",
+		},
+		"checkpoint timeout": {
+			key:      key,
+			order:    &checkpointOrder{block: true},
+			errMatch: "failed to retrieve checkpoint for seq 7: context deadline exceeded",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			a := newAttestor("ns", tt.key, tt.order)
+			if a != nil {
+				a.timeout = 10 * time.Millisecond
+			}
+			attestation, err := a.attest(context.Background(), 7, transaction.ID("txid"), []byte("receipt-id"))
+			if tt.errMatch != "" {
+				gt.Expect(err).To(MatchError(tt.errMatch))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			if tt.key == nil {
+				gt.Expect(a).To(BeNil())
+				gt.Expect(attestation).To(BeNil())
+				return
+			}
+
+			gt.Expect(attestation.Namespace).To(Equal("ns"))
+			gt.Expect(attestation.SeqNo).To(Equal(uint64(7)))
+			gt.Expect(attestation.TxID).To(Equal(transaction.ID("txid")))
+			gt.Expect(attestation.ReceiptID).To(Equal([]byte("receipt-id")))
+			gt.Expect(attestation.Accumulator).To(Equal(tt.accumulator))
+			gt.Expect(attestation.Verify(&key.PublicKey)).To(Succeed())
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package namespace
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
@@ -16,109 +18,274 @@ import (
 type Repository interface {
 	PutCommitted(transaction.ID, *transaction.Committed) error
 	GetCommitted(transaction.ID) (*transaction.Committed, error)
+	CommitTransaction(transaction.ID, *transaction.Committed, []*transaction.State, []transaction.StateID) error
+	GetLastCommittedSeq() (uint64, error)
+	GetCommittedTxID(uint64) (transaction.ID, error)
+	PutAttestation(transaction.ID, *transaction.Attestation) error
+	GetLastAttestedSeq() (uint64, error)
+	PutRejected(transaction.ID, *transaction.Rejected) error
+	RejectTransaction(transaction.ID, *transaction.Rejected) error
+	GetRejected(transaction.ID) (*transaction.Rejected, error)
 	PutReceipt(*transaction.Receipt) error
 	GetReceipt([]byte) (*transaction.Receipt, error)
 	PutTransaction(*transaction.Transaction) error
 	GetTransaction(transaction.ID) (*transaction.Transaction, error)
 	PutState(*transaction.State) error
 	GetState(transaction.StateID, bool) (*transaction.State, error)
+	GetStatesByOwner([]byte) ([]*transaction.State, error)
+	GetStatesByKind(string) ([]*transaction.State, error)
 	ConsumeState(transaction.StateID) error
+	NewView() (store.View, error)
+}
+
+// A stateGetter retrieves states from a repository or a view of one.
+type stateGetter interface {
+	GetState(transaction.StateID, bool) (*transaction.State, error)
 }
 
 // TODO: proper error values with semantics
 
 type committer struct {
-	repo      Repository // repo is a reference to the transaction state repository.
-	validator Validator  // validator the transaction Validator
+	repo       Repository     // repo is a reference to the transaction state repository.
+	validators ValidatorChain // validators are the fallback validators that must all pass
+	kinds      KindValidators // kinds are the validators for specific state kinds
 }
 
-func newCommitter(repo Repository, validator Validator) *committer {
+func newCommitter(repo Repository, validators ValidatorChain, kinds KindValidators) *committer {
 	return &committer{
-		repo:      repo,
-		validator: validator,
+		repo:       repo,
+		validators: validators,
+		kinds:      kinds,
+	}
+}
+
+// A validation is the outcome of resolving and validating the transaction
+// associated with an ordered receipt.
+type validation struct {
+	receipt    *transaction.Receipt
+	tx         *transaction.Transaction
+	processed  bool                  // processed is set when the transaction was already committed or rejected
+	rejected   *transaction.Rejected // rejected is the earlier rejection of a processed transaction
+	stateErr   error
+	resolved   *transaction.Resolved
+	resp       *validationv1.ValidateResponse
+	validators []string // validators are the names of the validators that ran
+	rejectedBy []string // rejectedBy are the names of the validators that rejected the transaction
+}
+
+// inputs returns the IDs of the states consumed by the transaction.
+func (v *validation) inputs() []transaction.StateID {
+	var inputs []transaction.StateID
+	for _, input := range v.resolved.Inputs {
+		inputs = append(inputs, input.ID)
+	}
+	return inputs
+}
+
+func (c *committer) commit(receiptID []byte, seqNo uint64) error {
+	v, err := c.validate(receiptID)
+	if err != nil {
+		return err
 	}
+	return c.apply(v, seqNo)
 }
 
-func (c *committer) commit(receiptID []byte) error {
+// validate resolves and validates the transaction associated with a receipt
+// against the current state. Nothing is written to the repository so
+// validate may be called concurrently. An error is only returned when commit
+// processing must halt.
+func (c *committer) validate(receiptID []byte) (*validation, error) {
 	receipt, err := c.repo.GetReceipt(receiptID)
 	if store.IsNotFound(err) {
-		return newHaltError(err, "receipt should have been disseminated but was not found")
+		return nil, newHaltError(err, "receipt should have been disseminated but was not found")
 	}
 	if err != nil {
-		return newHaltError(err, "transaction store failure")
+		return nil, newHaltError(err, "transaction store failure")
 	}
 
 	tx, err := c.repo.GetTransaction(receipt.TxID)
 	if store.IsNotFound(err) {
-		return newHaltError(err, "transaction should have been disseminated but was not found")
+		return nil, newHaltError(err, "transaction should have been disseminated but was not found")
 	}
 	if err != nil {
-		return newHaltError(err, "transaction store failure")
+		return nil, newHaltError(err, "transaction store failure")
 	}
 
-	// resolve all inputs and references
-	resolved, err := resolve(c.repo, tx, receipt.Signatures)
+	processed, rejected, resolved, err := c.resolveUnprocessed(tx, receipt.Signatures)
+	if processed {
+		return &validation{receipt: receipt, tx: tx, processed: true, rejected: rejected}, nil
+	}
 	if err != nil && store.IsNotFound(err) {
-		return errors.WithMessagef(err, "missing state for transaction %s", tx.ID)
+		return &validation{receipt: receipt, tx: tx, stateErr: err}, nil
 	}
 	if err != nil {
-		return newHaltError(err, "state resolution for transaction %s failed", tx.ID)
+		return nil, err
 	}
 
-	resp, err := c.validator.Validate(&validationv1.ValidateRequest{
+	chain := c.kinds.chain(resolved, c.validators)
+	resp, rejectedBy, err := chain.validate(&validationv1.ValidateRequest{
 		ResolvedTransaction: transaction.FromResolved(resolved),
 	})
 	if err != nil {
-		return newHaltError(err, "validator failed")
+		return nil, newHaltError(err, "validation failed")
 	}
-	if !resp.Valid && resp.ErrorMessage != "" {
-		return errors.Errorf("validation failed: %s", resp.ErrorMessage)
+
+	return &validation{
+		receipt:    receipt,
+		tx:         tx,
+		resolved:   resolved,
+		resp:       resp,
+		validators: chain.Names(),
+		rejectedBy: rejectedBy,
+	}, nil
+}
+
+// resolveUnprocessed resolves the inputs and references of a transaction
+// that has not been committed or rejected. The processing checks and
+// resolution read from a single view of the repository so a concurrent
+// commit can not be observed part way through. The view is released before
+// validation begins.
+//
+// When the transaction was already processed, true is returned along with
+// the rejection of a rejected transaction. A NotFoundError is returned when
+// a state could not be resolved; any other error halts commit processing.
+func (c *committer) resolveUnprocessed(tx *transaction.Transaction, sigs []*transaction.Signature) (bool, *transaction.Rejected, *transaction.Resolved, error) {
+	view, err := c.repo.NewView()
+	if err != nil {
+		return false, nil, nil, newHaltError(err, "transaction store failure")
 	}
-	if !resp.Valid {
-		return errors.New("validation failed")
+	defer view.Release()
+
+	// Receipts are delivered again when processing resumes after a failure;
+	// transactions that have already been committed or rejected are skipped.
+	_, err = view.GetCommitted(tx.ID)
+	if err == nil {
+		return true, nil, nil, nil
+	}
+	if !store.IsNotFound(err) {
+		return false, nil, nil, newHaltError(err, "transaction store failure")
+	}
+	rejected, err := view.GetRejected(tx.ID)
+	if err == nil {
+		return true, rejected, nil, nil
+	}
+	if !store.IsNotFound(err) {
+		return false, nil, nil, newHaltError(err, "transaction store failure")
 	}
 
-	err = c.repo.PutCommitted(tx.ID, &transaction.Committed{
-		// TODO, once ordered, include SeqNo
-		ReceiptID: receipt.ID,
-	})
-	if err != nil {
-		return newHaltError(err, "marking %s as committed failed", tx.ID)
+	// resolve all inputs and references
+	resolved, err := resolve(view, tx, sigs)
+	if err != nil && !store.IsNotFound(err) {
+		return false, nil, nil, newHaltError(err, "state resolution for transaction %s failed", tx.ID)
+	}
+	return false, nil, resolved, err
+}
+
+// apply commits a validated transaction at a sequence number or records the
+// reason it was rejected.
+func (c *committer) apply(v *validation, seqNo uint64) error {
+	tx, receipt := v.tx, v.receipt
+	if v.rejected != nil {
+		return newRejectedError(v.rejected)
+	}
+	if v.processed {
+		return nil
 	}
 
-	for _, output := range resolved.Outputs {
-		err = c.repo.PutState(output)
-		if err != nil {
-			return newHaltError(err, "storing transaction output %s failed", output.ID)
+	if v.stateErr != nil {
+		rejected := &transaction.Rejected{ReceiptID: receipt.ID, SeqNo: seqNo}
+		err := NewStateError(v.stateErr)
+		var stateErr *StateError
+		if errors.As(err, &stateErr) {
+			rejected.MissingState = &stateErr.StateID
+			rejected.Consumed = errors.Is(stateErr, ErrStateConsumed)
+			rejected.ConsumedBy = stateErr.ConsumedBy
 		}
+		return c.reject(tx.ID, rejected, errors.WithMessagef(err, "missing state for transaction %s", tx.ID))
 	}
 
-	for _, input := range resolved.Inputs {
-		err = c.repo.ConsumeState(input.ID)
-		if err != nil {
-			return newHaltError(err, "consuming transaction state %s failed", input.ID)
+	if !v.resp.Valid {
+		rejected := &transaction.Rejected{
+			ReceiptID:    receipt.ID,
+			SeqNo:        seqNo,
+			Validators:   v.rejectedBy,
+			ErrorMessage: v.resp.ErrorMessage,
 		}
+		if v.resp.ErrorMessage != "" {
+			return c.reject(tx.ID, rejected, errors.Errorf("validation failed: %s", v.resp.ErrorMessage))
+		}
+		return c.reject(tx.ID, rejected, errors.New("validation failed"))
+	}
+
+	// The commit record, outputs, and consumed inputs are persisted together
+	// so a failure never leaves a partially committed transaction.
+	err := c.repo.CommitTransaction(tx.ID, &transaction.Committed{
+		SeqNo:      seqNo,
+		ReceiptID:  receipt.ID,
+		Validators: v.validators,
+	}, v.resolved.Outputs, v.inputs())
+	if err != nil {
+		return newHaltError(err, "committing transaction %s failed", tx.ID)
 	}
 
 	return nil
 }
 
-func resolve(repo Repository, tx *transaction.Transaction, sigs []*transaction.Signature) (*transaction.Resolved, error) {
-	var inputs []*transaction.State
-	for _, input := range tx.Inputs {
-		state, err := repo.GetState(*input, false)
-		if err != nil {
-			return nil, err
-		}
-		inputs = append(inputs, state)
+// reject records the reason a transaction failed commit processing along
+// with the sequence number of the rejected entry and returns the reason.
+func (c *committer) reject(txID transaction.ID, rejected *transaction.Rejected, reason error) error {
+	rejected.Reason = reason.Error()
+	rejected.Timestamp = time.Now().UTC()
+
+	err := c.repo.RejectTransaction(txID, rejected)
+	if err != nil {
+		return newHaltError(err, "recording rejection of transaction %s failed", txID)
 	}
-	var refs []*transaction.State
-	for _, ref := range tx.References {
-		state, err := repo.GetState(*ref, false)
-		if err != nil {
-			return nil, err
+	return reason
+}
+
+// resolve resolves the inputs and references of a transaction. The error
+// for the first state that could not be found is returned when resolution is
+// incomplete.
+func resolve(repo stateGetter, tx *transaction.Transaction, sigs []*transaction.Signature) (*transaction.Resolved, error) {
+	resolved, missing, err := resolveAll(repo, tx, sigs)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) != 0 {
+		return nil, missing[0]
+	}
+	return resolved, nil
+}
+
+// resolveAll resolves the inputs and references of a transaction. States
+// that can not be found do not stop resolution; their errors are returned
+// as missing and they are left out of the resolved transaction.
+func resolveAll(repo stateGetter, tx *transaction.Transaction, sigs []*transaction.Signature) (*transaction.Resolved, []error, error) {
+	var missing []error
+	lookup := func(ids []*transaction.StateID) ([]*transaction.State, error) {
+		var states []*transaction.State
+		for _, id := range ids {
+			state, err := repo.GetState(*id, false)
+			if store.IsNotFound(err) {
+				missing = append(missing, err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			states = append(states, state)
 		}
-		refs = append(refs, state)
+		return states, nil
+	}
+
+	inputs, err := lookup(tx.Inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := lookup(tx.References)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	resolved := &transaction.Resolved{
@@ -131,5 +298,5 @@ func resolve(repo Repository, tx *transaction.Transaction, sigs []*transaction.S
 		Signatures:      sigs,
 	}
 
-	return resolved, nil
+	return resolved, missing, nil
 }
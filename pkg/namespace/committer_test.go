@@ -5,8 +5,10 @@ package namespace
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/sha256"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -15,6 +17,7 @@ import (
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
 	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
 	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
 	. "github.com/sykesm/batik/pkg/tested/matcher"
 	"github.com/sykesm/batik/pkg/transaction"
 )
@@ -24,6 +27,18 @@ type fakeRepository Repository // private to prevent an import cycle in generate
 
 var _ fakeRepository = (*fake.Repository)(nil)
 
+// fakeView reads from a fake repository in place of a snapshot.
+type fakeView struct{ *fake.Repository }
+
+func (fakeView) Release() {}
+
+// newFakeRepository returns a fake repository with views that read from it.
+func newFakeRepository() *fake.Repository {
+	fakeRepo := &fake.Repository{}
+	fakeRepo.NewViewReturns(fakeView{fakeRepo}, nil)
+	return fakeRepo
+}
+
 type validatorFunc func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error)
 
 func (v validatorFunc) Validate(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
@@ -44,7 +59,7 @@ func TestCommitGetTransaction(t *testing.T) {
 		gt := NewGomegaWithT(t)
 
 		fakeRepo := &fake.Repository{}
-		committer := newCommitter(fakeRepo, validator.NewSignature())
+		committer := newCommitter(fakeRepo, ValidatorChain{{Name: "signature-builtin", Validator: validator.NewSignature()}}, nil)
 
 		err := committer.Submit(context.Background(), signed)
 		gt.Expect(err).To(HaveOccurred())
@@ -56,7 +71,7 @@ func TestCommitGetTransaction(t *testing.T) {
 
 		fakeRepo := &fake.Repository{}
 		fakeRepo.GetTransactionReturns(nil, errors.New("unexpected-error"))
-		committer := newCommitter(fakeRepo, validator.NewSignature())
+		committer := newCommitter(fakeRepo, ValidatorChain{{Name: "signature-builtin", Validator: validator.NewSignature()}}, nil)
 
 		err := committer.Submit(context.Background(), signed)
 		gt.Expect(err).To(MatchError(ErrHalt))
@@ -109,7 +124,9 @@ func TestCommitTxResolve(t *testing.T) {
 			}},
 		}
 
-		fakeRepo = &fake.Repository{}
+		fakeRepo = newFakeRepository()
+		fakeRepo.GetCommittedReturns(nil, &store.NotFoundError{Err: errors.New("not-committed")})
+		fakeRepo.GetRejectedReturns(nil, &store.NotFoundError{Err: errors.New("not-rejected")})
 		fakeRepo.GetReceiptStub = func(id []byte) (*transaction.Receipt, error) {
 			if !bytes.Equal(id, receipt.ID) {
 				return nil, &store.NotFoundError{Err: errors.Errorf("missing-receipt %x", receipt.ID)}
@@ -154,11 +171,11 @@ func TestCommitTxResolve(t *testing.T) {
 			return &validationv1.ValidateResponse{Valid: true}, nil
 		}
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(validator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(validator)}},
 		}
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		gt.Expect(fakeRepo.GetStateCallCount()).To(Equal(2))
@@ -212,36 +229,116 @@ func TestCommitTxResolve(t *testing.T) {
 		}))
 	})
 
+	t.Run("WhenViewFails", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+		}
+
+		fakeRepo.NewViewReturns(nil, errors.New("snapshot-error"))
+
+		err := committer.commit(receipt.ID, 0)
+		gt.Expect(err).To(MatchError(ErrHalt))
+		gt.Expect(err).To(MatchError("transaction store failure: halt processing: snapshot-error"))
+		gt.Expect(fakeRepo.GetStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(0))
+	})
+
 	t.Run("WhenInputMissing", func(t *testing.T) {
 		setup(t)
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
 		fakeRepo.GetStateReturnsOnCall(0, nil, &store.NotFoundError{Err: errors.New("missing-input-state")})
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ContainSubstring("missing-input-state")))
 		gt.Expect(store.IsNotFound(err)).To(BeTrue())
 		input, _ := fakeRepo.GetStateArgsForCall(0)
 		gt.Expect(input).To(Equal(*tx.Inputs[0]))
 	})
 
+	t.Run("WhenInputConsumed", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+		}
+
+		consumedBy := transaction.ID("consuming-txid")
+		fakeRepo.GetStateReturnsOnCall(0, nil, &store.StateConsumedError{
+			StateID:    *tx.Inputs[0],
+			ConsumedBy: consumedBy,
+			Err:        &store.NotFoundError{Err: errors.New("consumed-input-state")},
+		})
+
+		err := committer.commit(receipt.ID, 0)
+		gt.Expect(err).To(MatchError(ErrStateConsumed))
+		gt.Expect(err).NotTo(MatchError(ErrStateUnknown))
+		gt.Expect(err).NotTo(MatchError(ErrHalt))
+		gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+		var stateErr *StateError
+		gt.Expect(errors.As(err, &stateErr)).To(BeTrue())
+		gt.Expect(stateErr.StateID).To(Equal(*tx.Inputs[0]))
+		gt.Expect(stateErr.ConsumedBy).To(Equal(consumedBy))
+
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(1))
+		_, rejected := fakeRepo.RejectTransactionArgsForCall(0)
+		gt.Expect(rejected.Reason).To(Equal(err.Error()))
+		gt.Expect(rejected.Validators).To(BeEmpty())
+		gt.Expect(rejected.ErrorMessage).To(BeEmpty())
+		gt.Expect(rejected.MissingState).To(Equal(tx.Inputs[0]))
+		gt.Expect(rejected.Consumed).To(BeTrue())
+		gt.Expect(rejected.ConsumedBy).To(Equal(consumedBy))
+	})
+
+	t.Run("WhenInputUnknown", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+		}
+
+		fakeRepo.GetStateReturnsOnCall(0, nil, &store.StateUnknownError{
+			StateID: *tx.Inputs[0],
+			Err:     &store.NotFoundError{Err: errors.New("unknown-input-state")},
+		})
+
+		err := committer.commit(receipt.ID, 0)
+		gt.Expect(err).To(MatchError(ErrStateUnknown))
+		gt.Expect(err).NotTo(MatchError(ErrStateConsumed))
+		gt.Expect(err).NotTo(MatchError(ErrHalt))
+
+		var stateErr *StateError
+		gt.Expect(errors.As(err, &stateErr)).To(BeTrue())
+		gt.Expect(stateErr.StateID).To(Equal(*tx.Inputs[0]))
+		gt.Expect(stateErr.ConsumedBy).To(BeNil())
+	})
+
 	t.Run("WhenGetInputFailure", func(t *testing.T) {
 		setup(t)
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
 		fakeRepo.GetStateReturnsOnCall(0, nil, errors.New("get-input-state-failed"))
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ErrHalt))
 		gt.Expect(err).To(MatchError(MatchRegexp("state resolution for transaction [[:xdigit:]]+ failed: halt processing: get-input-state-failed")), err.Error())
 		input, _ := fakeRepo.GetStateArgsForCall(0)
@@ -253,13 +350,13 @@ func TestCommitTxResolve(t *testing.T) {
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
 		fakeRepo.GetStateReturnsOnCall(1, nil, &store.NotFoundError{Err: errors.New("missing-reference-state")})
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ContainSubstring("missing-reference-state")))
 		gt.Expect(store.IsNotFound(err)).To(BeTrue())
 		ref, _ := fakeRepo.GetStateArgsForCall(1)
@@ -271,13 +368,13 @@ func TestCommitTxResolve(t *testing.T) {
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
 		fakeRepo.GetStateReturnsOnCall(1, nil, errors.New("get-reference-state-failed"))
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ErrHalt))
 		gt.Expect(err).To(MatchError(MatchRegexp("state resolution for transaction [[:xdigit:]]+ failed: halt processing: get-reference-state-failed")), err.Error())
 		ref, _ := fakeRepo.GetStateArgsForCall(1)
@@ -322,7 +419,9 @@ func TestCommitPostValidation(t *testing.T) {
 			}},
 		}
 
-		fakeRepo = &fake.Repository{}
+		fakeRepo = newFakeRepository()
+		fakeRepo.GetCommittedReturns(nil, &store.NotFoundError{Err: errors.New("not-committed")})
+		fakeRepo.GetRejectedReturns(nil, &store.NotFoundError{Err: errors.New("not-rejected")})
 		fakeRepo.GetTransactionStub = func(txid transaction.ID) (*transaction.Transaction, error) {
 			if !bytes.Equal(txid, tx.ID) {
 				return nil, &store.NotFoundError{Err: errors.New("missing-transaction-error")}
@@ -354,24 +453,27 @@ func TestCommitPostValidation(t *testing.T) {
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 7)
 		gt.Expect(err).NotTo(HaveOccurred())
 
-		gt.Expect(fakeRepo.PutCommittedCallCount()).To(Equal(1))
-		txid, commit := fakeRepo.PutCommittedArgsForCall(0)
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(1))
+		txid, commit, outputs, inputs := fakeRepo.CommitTransactionArgsForCall(0)
 		gt.Expect(txid).To(Equal(tx.ID))
 		gt.Expect(commit).To(Equal(&transaction.Committed{
-			SeqNo:     0,
-			ReceiptID: []byte("tx-receipt"),
+			SeqNo:      7,
+			ReceiptID:  []byte("tx-receipt"),
+			Validators: []string{"test"},
 		}))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.PutStateArgsForCall(0)).To(Equal(tx.Outputs[0]))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.ConsumeStateArgsForCall(0)).To(Equal(*tx.Inputs[0]))
+		gt.Expect(outputs).To(Equal(tx.Outputs))
+		gt.Expect(inputs).To(Equal([]transaction.StateID{*tx.Inputs[0]}))
+
+		gt.Expect(fakeRepo.PutCommittedCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
 	})
 
 	t.Run("WhenInvalid", func(t *testing.T) {
@@ -380,18 +482,17 @@ func TestCommitPostValidation(t *testing.T) {
 
 		committer := &committer{
 			repo: fakeRepo,
-			validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
 				return &validationv1.ValidateResponse{Valid: false}, nil
-			}),
+			})}},
 		}
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ContainSubstring("validation failed")))
 		gt.Expect(err).NotTo(MatchError(ErrHalt))
 
 		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
 	})
 
 	t.Run("WhenInvalidWithMessage", func(t *testing.T) {
@@ -400,18 +501,112 @@ func TestCommitPostValidation(t *testing.T) {
 
 		committer := &committer{
 			repo: fakeRepo,
-			validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+			validators: ValidatorChain{{Name: "breakfast", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
 				return &validationv1.ValidateResponse{Valid: false, ErrorMessage: "texas-toast"}, nil
-			}),
+			})}},
 		}
 
-		err := committer.commit(receipt.ID)
+		before := time.Now()
+		err := committer.commit(receipt.ID, 3)
 		gt.Expect(err).To(MatchError(ContainSubstring("validation failed: texas-toast")))
 		gt.Expect(err).NotTo(MatchError(ErrHalt))
 
 		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
+
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(1))
+		txID, rejected := fakeRepo.RejectTransactionArgsForCall(0)
+		gt.Expect(txID).To(Equal(tx.ID))
+		gt.Expect(rejected.Timestamp).To(BeTemporally(">=", before))
+		gt.Expect(rejected.Timestamp.Location()).To(Equal(time.UTC))
+		rejected.Timestamp = time.Time{}
+		gt.Expect(rejected).To(Equal(&transaction.Rejected{
+			ReceiptID:    receipt.ID,
+			SeqNo:        3,
+			Reason:       "validation failed: texas-toast",
+			Validators:   []string{"breakfast"},
+			ErrorMessage: "texas-toast",
+		}))
+	})
+
+	t.Run("WhenRejectedByChain", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		reject := func(msg string) Validator {
+			return validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+				return &validationv1.ValidateResponse{Valid: false, ErrorMessage: msg}, nil
+			})
+		}
+		committer := &committer{
+			repo: fakeRepo,
+			validators: ValidatorChain{
+				{Name: "signature", Validator: reject("bad-signature")},
+				{Name: "noop", Validator: validatorFunc(noopValidator)},
+				{Name: "rules", Validator: reject("bad-rule")},
+			},
+		}
+
+		err := committer.commit(receipt.ID, 3)
+		gt.Expect(err).To(MatchError("validation failed: signature: bad-signature; rules: bad-rule"))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
+
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(1))
+		_, rejected := fakeRepo.RejectTransactionArgsForCall(0)
+		gt.Expect(rejected.Validators).To(Equal([]string{"signature", "rules"}))
+		gt.Expect(rejected.ErrorMessage).To(Equal("signature: bad-signature; rules: bad-rule"))
+	})
+
+	t.Run("WithKindValidators", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		tx.Outputs[0].StateInfo.Kind = "token"
+		var tokenCalls int
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+			kinds: KindValidators{
+				"token": {{Name: "token-rules", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+					tokenCalls++
+					return &validationv1.ValidateResponse{Valid: tokenCalls == 1, ErrorMessage: "token-toast"}, nil
+				})}},
+				"bond": {{Name: "bond-rules", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+					panic("bond validator should not run")
+				})}},
+			},
+		}
+
+		err := committer.commit(receipt.ID, 7)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(tokenCalls).To(Equal(1))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(1))
+		_, commit, _, _ := fakeRepo.CommitTransactionArgsForCall(0)
+		gt.Expect(commit.Validators).To(Equal([]string{"test", "token-rules"}))
+
+		fakeRepo.GetCommittedReturns(nil, &store.NotFoundError{Err: errors.New("not-committed")})
+		err = committer.commit(receipt.ID, 8)
+		gt.Expect(err).To(MatchError("validation failed: token-rules: token-toast"))
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(1))
+		_, rejected := fakeRepo.RejectTransactionArgsForCall(0)
+		gt.Expect(rejected.Validators).To(Equal([]string{"token-rules"}))
+	})
+
+	t.Run("WhenRecordingRejectionFails", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo: fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+				return &validationv1.ValidateResponse{Valid: false, ErrorMessage: "texas-toast"}, nil
+			})}},
+		}
+		fakeRepo.RejectTransactionReturns(errors.New("reject-failed"))
+
+		err := committer.commit(receipt.ID, 0)
+		gt.Expect(err).To(MatchError(ErrHalt))
+		gt.Expect(err).To(MatchError(MatchRegexp("recording rejection of transaction [[:xdigit:]]+ failed: halt processing: reject-failed")))
 	})
 
 	t.Run("WhenValidationFails", func(t *testing.T) {
@@ -420,79 +615,217 @@ func TestCommitPostValidation(t *testing.T) {
 
 		committer := &committer{
 			repo: fakeRepo,
-			validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
 				return nil, errors.New("boom!")
-			}),
+			})}},
 		}
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ErrHalt))
-		gt.Expect(err).To(MatchError("validator failed: halt processing: boom!"))
+		gt.Expect(err).To(MatchError("validation failed: halt processing: validator test failed: boom!"))
 
 		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
 	})
 
-	t.Run("WhenPutCommittedFails", func(t *testing.T) {
+	t.Run("WhenAlreadyCommitted", func(t *testing.T) {
 		setup(t)
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
-		fakeRepo.PutCommittedReturns(errors.New("put-committed-failed"))
+		fakeRepo.GetCommittedReturns(&transaction.Committed{SeqNo: 3, ReceiptID: receipt.ID}, nil)
 
-		err := committer.commit(receipt.ID)
-		gt.Expect(err).To(MatchError(ErrHalt))
-		gt.Expect(err).To(MatchError(MatchRegexp("marking [[:xdigit:]]+ as committed failed: halt processing: put-committed-failed")))
+		err := committer.commit(receipt.ID, 7)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(fakeRepo.GetCommittedArgsForCall(0)).To(Equal(tx.ID))
+		gt.Expect(fakeRepo.GetStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
+	})
 
-		gt.Expect(fakeRepo.PutCommittedCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(0))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
+	t.Run("WhenAlreadyRejected", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+		}
+
+		fakeRepo.GetRejectedReturns(&transaction.Rejected{SeqNo: 3, ReceiptID: receipt.ID, Reason: "validation failed"}, nil)
+
+		err := committer.commit(receipt.ID, 7)
+		gt.Expect(err).To(MatchError("validation failed"))
+		gt.Expect(fakeRepo.GetRejectedArgsForCall(0)).To(Equal(tx.ID))
+		gt.Expect(fakeRepo.GetStateCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.RejectTransactionCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
 	})
 
-	t.Run("WhenPutStateFails", func(t *testing.T) {
+	t.Run("WhenGetRejectedFails", func(t *testing.T) {
 		setup(t)
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
-		fakeRepo.PutStateReturns(errors.New("put-state-failed"))
+		fakeRepo.GetRejectedReturns(nil, errors.New("get-rejected-failed"))
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 7)
 		gt.Expect(err).To(MatchError(ErrHalt))
-		gt.Expect(err).To(MatchError(MatchRegexp("storing transaction output [[:xdigit:]]+:[[:xdigit:]]+ failed: halt processing: put-state-failed")))
+		gt.Expect(err).To(MatchError("transaction store failure: halt processing: get-rejected-failed"))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
+	})
 
-		gt.Expect(fakeRepo.PutCommittedCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(0))
+	t.Run("WhenGetCommittedFails", func(t *testing.T) {
+		setup(t)
+		gt := NewGomegaWithT(t)
+
+		committer := &committer{
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
+		}
+
+		fakeRepo.GetCommittedReturns(nil, errors.New("get-committed-failed"))
+
+		err := committer.commit(receipt.ID, 7)
+		gt.Expect(err).To(MatchError(ErrHalt))
+		gt.Expect(err).To(MatchError("transaction store failure: halt processing: get-committed-failed"))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(0))
 	})
 
-	t.Run("WhenConsumeStateFails", func(t *testing.T) {
+	t.Run("WhenCommitFails", func(t *testing.T) {
 		setup(t)
 		gt := NewGomegaWithT(t)
 
 		committer := &committer{
-			repo:      fakeRepo,
-			validator: validatorFunc(noopValidator),
+			repo:       fakeRepo,
+			validators: ValidatorChain{{Name: "test", Validator: validatorFunc(noopValidator)}},
 		}
 
-		fakeRepo.ConsumeStateReturns(errors.New("consume-state-failed"))
+		fakeRepo.CommitTransactionReturns(errors.New("commit-failed"))
 
-		err := committer.commit(receipt.ID)
+		err := committer.commit(receipt.ID, 0)
 		gt.Expect(err).To(MatchError(ErrHalt))
-		gt.Expect(err).To(MatchError(MatchRegexp("consuming transaction state [[:xdigit:]]+:[[:xdigit:]]+ failed: halt processing: consume-state-failed")))
+		gt.Expect(err).To(MatchError(MatchRegexp("committing transaction [[:xdigit:]]+ failed: halt processing: commit-failed")))
+		gt.Expect(fakeRepo.CommitTransactionCallCount()).To(Equal(1))
+	})
+}
+
+// TestCommitAtomic injects a crash at every write to the store during commit
+// processing and verifies that the transaction is either fully committed or
+// not committed at all.
+func TestCommitAtomic(t *testing.T) {
+	gt := NewGomegaWithT(t)
 
-		gt.Expect(fakeRepo.PutCommittedCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.PutStateCallCount()).To(Equal(1))
-		gt.Expect(fakeRepo.ConsumeStateCallCount()).To(Equal(1))
+	input := &transaction.State{
+		ID:        transaction.StateID{TxID: transaction.NewID(digest([]byte("input-tx"))), OutputIndex: 0},
+		StateInfo: &transaction.StateInfo{Kind: "kind"},
+		Data:      []byte("input-data"),
+	}
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+		Salt:   digest([]byte("salt")),
+		Inputs: []*txv1.StateReference{{Txid: input.ID.TxID, OutputIndex: input.ID.OutputIndex}},
+		Outputs: []*txv1.State{
+			{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("output-0")},
+			{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("output-1")},
+		},
 	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	for writes := 0; ; writes++ {
+		db, err := store.NewLevelDB("")
+		gt.Expect(err).NotTo(HaveOccurred())
+		repo := store.NewRepository(db)
+		gt.Expect(repo.PutState(input)).To(Succeed())
+		gt.Expect(repo.PutTransaction(tx)).To(Succeed())
+		gt.Expect(repo.PutReceipt(receipt)).To(Succeed())
+
+		kv := &crashingKV{KV: db, writes: writes}
+		committer := newCommitter(store.NewRepository(kv), ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil)
+		commitErr := committer.commit(receipt.ID, 5)
+
+		_, err = repo.GetCommitted(tx.ID)
+		committed := err == nil
+		for _, output := range tx.Outputs {
+			_, err := repo.GetState(output.ID, false)
+			gt.Expect(err == nil).To(Equal(committed), "output %s after %d writes", output.ID, writes)
+		}
+		_, err = repo.GetState(input.ID, false)
+		gt.Expect(err == nil).To(Equal(!committed), "input after %d writes", writes)
+		_, err = repo.GetState(input.ID, true)
+		gt.Expect(err == nil).To(Equal(committed), "consumed input after %d writes", writes)
+
+		tested.Close(t, db)
+
+		if !kv.crashed {
+			gt.Expect(commitErr).NotTo(HaveOccurred())
+			gt.Expect(committed).To(BeTrue())
+			return
+		}
+		gt.Expect(commitErr).To(MatchError(ErrHalt))
+		gt.Expect(committed).To(BeFalse())
+	}
+}
+
+var errCrash = errors.New("injected crash")
+
+// crashingKV fails every write after the configured number of writes has
+// been performed. Operations added to a write batch are applied when the
+// batch is committed, so the commit counts as a single write.
+type crashingKV struct {
+	store.KV
+	writes  int
+	crashed bool
+}
+
+func (c *crashingKV) write() error {
+	if c.writes == 0 {
+		c.crashed = true
+		return errCrash
+	}
+	c.writes--
+	return nil
+}
+
+func (c *crashingKV) Put(key, value []byte) error {
+	if err := c.write(); err != nil {
+		return err
+	}
+	return c.KV.Put(key, value)
+}
+
+func (c *crashingKV) Delete(key []byte) error {
+	if err := c.write(); err != nil {
+		return err
+	}
+	return c.KV.Delete(key)
+}
+
+func (c *crashingKV) NewWriteBatch() store.WriteBatch {
+	return &crashingBatch{WriteBatch: c.KV.NewWriteBatch(), kv: c}
+}
+
+type crashingBatch struct {
+	store.WriteBatch
+	kv *crashingKV
+}
+
+func (c *crashingBatch) Commit() error {
+	if err := c.kv.write(); err != nil {
+		return err
+	}
+	return c.WriteBatch.Commit()
 }
 
 func digest(preImage []byte) []byte {
@@ -5,11 +5,37 @@ package namespace
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/transaction"
 )
 
 const (
 	// ErrHalt indicates transaction submission should be halted
 	ErrHalt = errorString("halt processing")
+	// ErrStateConsumed indicates a transaction input or reference has already
+	// been consumed by another transaction.
+	ErrStateConsumed = errorString("state consumed")
+	// ErrStateUnknown indicates a transaction input or reference refers to a
+	// state that does not exist.
+	ErrStateUnknown = errorString("state unknown")
+	// ErrNamespaceExists indicates a namespace with the same name is already
+	// active.
+	ErrNamespaceExists = errorString("namespace exists")
+	// ErrNamespaceNotFound indicates a namespace is not active.
+	ErrNamespaceNotFound = errorString("namespace not found")
+	// ErrInvalidNamespace indicates the configuration of a namespace is not
+	// valid.
+	ErrInvalidNamespace = errorString("invalid namespace")
+	// ErrNamespaceHalted indicates commit processing for a namespace has
+	// stopped and new transactions are not accepted until it is resumed.
+	ErrNamespaceHalted = errorString("namespace halted")
+	// ErrNamespaceNotHalted indicates a namespace can not be resumed because
+	// commit processing has not halted.
+	ErrNamespaceNotHalted = errorString("namespace not halted")
+	// ErrNamespaceClosed indicates a namespace was closed before a request
+	// could be processed.
+	ErrNamespaceClosed = errorString("namespace closed")
 )
 
 // errorString is a converstion type for constant errors.
@@ -35,3 +61,78 @@ func newHaltError(err error, msg string, args ...interface{}) error {
 	fe := &fatalError{kind: ErrHalt, cause: err}
 	return errors.WithMessagef(fe, msg, args...)
 }
+
+// A StateError indicates that a transaction could not be committed because
+// one of its inputs or references could not be resolved. It matches either
+// ErrStateConsumed or ErrStateUnknown.
+type StateError struct {
+	// StateID identifies the state that could not be resolved.
+	StateID transaction.StateID
+	// ConsumedBy is the ID of the transaction that consumed the state. It is
+	// only set for consumed states when the consuming transaction is known.
+	ConsumedBy transaction.ID
+
+	cause error
+	kind  errorString
+}
+
+func (s *StateError) Error() string {
+	return s.kind.Error() + ": " + s.cause.Error()
+}
+
+func (s *StateError) Is(target error) bool {
+	return target == s.kind
+}
+
+func (s *StateError) Unwrap() error {
+	return s.cause
+}
+
+// newRejectedError returns the error reported to submitters of a transaction
+// that was rejected. The message is the recorded reason. When the rejection
+// was caused by a state that could not be resolved, the error also matches a
+// StateError for that state.
+func newRejectedError(rejected *transaction.Rejected) error {
+	err := &rejectedError{reason: rejected.Reason}
+	if rejected.MissingState != nil {
+		err.cause = &StateError{
+			StateID:    *rejected.MissingState,
+			ConsumedBy: rejected.ConsumedBy,
+			cause:      errors.New(rejected.Reason),
+			kind:       ErrStateUnknown,
+		}
+		if rejected.Consumed {
+			err.cause.kind = ErrStateConsumed
+		}
+	}
+	return err
+}
+
+// rejectedError reports a recorded rejection.
+type rejectedError struct {
+	reason string
+	cause  *StateError
+}
+
+func (r *rejectedError) Error() string { return r.reason }
+
+func (r *rejectedError) Unwrap() error {
+	if r.cause == nil {
+		return nil
+	}
+	return r.cause
+}
+
+// NewStateError converts a StateConsumedError or StateUnknownError returned
+// by the store into a StateError. Other errors are returned unchanged.
+func NewStateError(err error) error {
+	var sce *store.StateConsumedError
+	if errors.As(err, &sce) {
+		return &StateError{StateID: sce.StateID, ConsumedBy: sce.ConsumedBy, cause: err, kind: ErrStateConsumed}
+	}
+	var sue *store.StateUnknownError
+	if errors.As(err, &sue) {
+		return &StateError{StateID: sue.StateID, cause: err, kind: ErrStateUnknown}
+	}
+	return err
+}
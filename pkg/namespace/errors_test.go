@@ -8,6 +8,9 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/transaction"
 )
 
 func TestErrHalt(t *testing.T) {
@@ -22,3 +25,86 @@ func TestErrHalt(t *testing.T) {
 	unwrapped = he.(interface{ Unwrap() error }).Unwrap()
 	gt.Expect(unwrapped).To(MatchError(ErrHalt))
 }
+
+func TestNewStateError(t *testing.T) {
+	stateID := transaction.StateID{TxID: transaction.ID("txid"), OutputIndex: 1}
+	notFound := &store.NotFoundError{Err: errors.New("not-found")}
+
+	tests := map[string]struct {
+		err        error
+		kind       error
+		consumedBy transaction.ID
+	}{
+		"consumed": {
+			err:        &store.StateConsumedError{StateID: stateID, ConsumedBy: transaction.ID("consumer"), Err: notFound},
+			kind:       ErrStateConsumed,
+			consumedBy: transaction.ID("consumer"),
+		},
+		"unknown": {
+			err:  &store.StateUnknownError{StateID: stateID, Err: notFound},
+			kind: ErrStateUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			err := NewStateError(errors.WithMessage(tt.err, "wrapped"))
+			gt.Expect(err).To(MatchError(tt.kind))
+			gt.Expect(err).NotTo(MatchError(ErrHalt))
+			gt.Expect(err.Error()).To(Equal(tt.kind.Error() + ": wrapped: " + tt.err.Error()))
+			gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+			var stateErr *StateError
+			gt.Expect(errors.As(err, &stateErr)).To(BeTrue())
+			gt.Expect(stateErr.StateID).To(Equal(stateID))
+			gt.Expect(stateErr.ConsumedBy).To(Equal(tt.consumedBy))
+		})
+	}
+
+	gt := NewGomegaWithT(t)
+	gt.Expect(NewStateError(notFound)).To(BeIdenticalTo(notFound))
+}
+
+func TestNewRejectedError(t *testing.T) {
+	stateID := transaction.StateID{TxID: transaction.ID("txid"), OutputIndex: 1}
+
+	tests := map[string]struct {
+		rejected   *transaction.Rejected
+		kind       error
+		consumedBy transaction.ID
+	}{
+		"validation": {
+			rejected: &transaction.Rejected{Reason: "validation failed: invalid"},
+		},
+		"consumed": {
+			rejected:   &transaction.Rejected{Reason: "missing state", MissingState: &stateID, Consumed: true, ConsumedBy: transaction.ID("consumer")},
+			kind:       ErrStateConsumed,
+			consumedBy: transaction.ID("consumer"),
+		},
+		"unknown": {
+			rejected: &transaction.Rejected{Reason: "missing state", MissingState: &stateID},
+			kind:     ErrStateUnknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			err := newRejectedError(tt.rejected)
+			gt.Expect(err).To(MatchError(tt.rejected.Reason))
+
+			var stateErr *StateError
+			if tt.kind == nil {
+				gt.Expect(errors.As(err, &stateErr)).To(BeFalse())
+				return
+			}
+			gt.Expect(err).To(MatchError(tt.kind))
+			gt.Expect(errors.As(err, &stateErr)).To(BeTrue())
+			gt.Expect(stateErr.StateID).To(Equal(stateID))
+			gt.Expect(stateErr.ConsumedBy).To(Equal(tt.consumedBy))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// A CommitEvent describes a transaction that was committed by a namespace.
+type CommitEvent struct {
+	TxID      transaction.ID
+	SeqNo     uint64
+	ReceiptID []byte
+	Consumed  []*transaction.StateID // Consumed references the transaction inputs
+	Outputs   []*transaction.State   // Outputs are the states created by the transaction
+}
+
+// A Subscription delivers commit events in sequence order.
+type Subscription struct {
+	ns   *Namespace
+	next uint64
+}
+
+// Subscribe creates a subscription to the transactions committed at or
+// after the provided sequence number. Transactions that have already been
+// committed are replayed from the repository before events for new commits
+// are delivered.
+func (ns *Namespace) Subscribe(fromSeq uint64) *Subscription {
+	return &Subscription{ns: ns, next: fromSeq}
+}
+
+// Next returns the event for the next committed transaction. Next blocks
+// until a transaction is committed, the context is done, or the namespace is
+// closed.
+func (s *Subscription) Next(ctx context.Context) (*CommitEvent, error) {
+	for {
+		// The notification channel is acquired before the repository is
+		// read so a commit can not be missed between the two.
+		commitC := s.ns.commits()
+
+		if err := s.ns.Acquire(); err != nil {
+			return nil, err
+		}
+		event, err := s.replay()
+		s.ns.Release()
+		if event != nil || err != nil {
+			return event, err
+		}
+
+		select {
+		case <-commitC:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// replay returns the event for the first transaction committed at or after
+// the next sequence number. A nil event is returned when no such transaction
+// has been committed.
+func (s *Subscription) replay() (*CommitEvent, error) {
+	last, err := s.ns.Repo.GetLastCommittedSeq()
+	if store.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get last committed sequence")
+	}
+
+	for ; s.next <= last; s.next++ {
+		txID, err := s.ns.Repo.GetCommittedTxID(s.next)
+		if store.IsNotFound(err) {
+			// Rejected and unauthenticated entries are not committed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		event, err := commitEvent(s.ns.Repo, txID)
+		if err != nil {
+			return nil, err
+		}
+		s.next++
+		return event, nil
+	}
+	return nil, nil
+}
+
+func commitEvent(repo Repository, txID transaction.ID) (*CommitEvent, error) {
+	committed, err := repo.GetCommitted(txID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get commit record for transaction %s", txID)
+	}
+	tx, err := repo.GetTransaction(txID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get committed transaction %s", txID)
+	}
+
+	return &CommitEvent{
+		TxID:      txID,
+		SeqNo:     committed.SeqNo,
+		ReceiptID: committed.ReceiptID,
+		Consumed:  tx.Inputs,
+		Outputs:   tx.Outputs,
+	}, nil
+}
+
+// commits returns a channel that is closed when the next ordered receipt has
+// been processed.
+func (ns *Namespace) commits() <-chan struct{} {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.commitC == nil {
+		ns.commitC = make(chan struct{})
+	}
+	return ns.commitC
+}
@@ -0,0 +1,105 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"crypto"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+func TestNamespace_Subscribe(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	broadcast := func(tx *transaction.Transaction) {
+		receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+		err := order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	tx0 := newPipelineTx(t, repo, "tx0")
+	tx1 := newPipelineTx(t, repo, "tx1", tx0.Outputs[0].ID)
+	rejected := newPipelineTx(t, repo, "rejected", tx0.Outputs[0].ID)
+	tx3 := newPipelineTx(t, repo, "tx3", tx1.Outputs[0].ID)
+
+	for _, tx := range []*transaction.Transaction{tx0, tx1, rejected} {
+		broadcast(tx)
+	}
+	gt.Eventually(func() error { _, err := repo.GetRejected(rejected.ID); return err }).Should(Succeed())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expectEvent := func(sub *Subscription, tx *transaction.Transaction, seq uint64) {
+		event, err := sub.Next(ctx)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(event).To(Equal(&CommitEvent{
+			TxID:      tx.ID,
+			SeqNo:     seq,
+			ReceiptID: transaction.NewReceipt(crypto.SHA256, tx.ID, nil).ID,
+			Consumed:  tx.Inputs,
+			Outputs:   tx.Outputs,
+		}))
+	}
+
+	t.Run("ReplayThenLive", func(t *testing.T) {
+		sub := ns.Subscribe(0)
+		expectEvent(sub, tx0, 0)
+		expectEvent(sub, tx1, 1)
+
+		eventC := make(chan *CommitEvent, 1)
+		go func() {
+			event, err := sub.Next(ctx)
+			gt.Expect(err).NotTo(HaveOccurred())
+			eventC <- event
+		}()
+		gt.Consistently(eventC).ShouldNot(Receive())
+
+		broadcast(tx3)
+		var event *CommitEvent
+		gt.Eventually(eventC).Should(Receive(&event))
+		gt.Expect(event.TxID).To(Equal(tx3.ID))
+		gt.Expect(event.SeqNo).To(Equal(uint64(3)))
+	})
+
+	t.Run("FromSequence", func(t *testing.T) {
+		sub := ns.Subscribe(1)
+		expectEvent(sub, tx1, 1)
+		expectEvent(sub, tx3, 3)
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		sub := ns.Subscribe(4)
+		cctx, ccancel := context.WithCancel(context.Background())
+		ccancel()
+
+		_, err := sub.Next(cctx)
+		gt.Expect(err).To(MatchError(context.Canceled))
+	})
+}
@@ -4,10 +4,25 @@ package fake
 import (
 	"sync"
 
+	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
 type Repository struct {
+	CommitTransactionStub        func(transaction.ID, *transaction.Committed, []*transaction.State, []transaction.StateID) error
+	commitTransactionMutex       sync.RWMutex
+	commitTransactionArgsForCall []struct {
+		arg1 transaction.ID
+		arg2 *transaction.Committed
+		arg3 []*transaction.State
+		arg4 []transaction.StateID
+	}
+	commitTransactionReturns struct {
+		result1 error
+	}
+	commitTransactionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ConsumeStateStub        func(transaction.StateID) error
 	consumeStateMutex       sync.RWMutex
 	consumeStateArgsForCall []struct {
@@ -32,6 +47,43 @@ type Repository struct {
 		result1 *transaction.Committed
 		result2 error
 	}
+	GetCommittedTxIDStub        func(uint64) (transaction.ID, error)
+	getCommittedTxIDMutex       sync.RWMutex
+	getCommittedTxIDArgsForCall []struct {
+		arg1 uint64
+	}
+	getCommittedTxIDReturns struct {
+		result1 transaction.ID
+		result2 error
+	}
+	getCommittedTxIDReturnsOnCall map[int]struct {
+		result1 transaction.ID
+		result2 error
+	}
+	GetLastAttestedSeqStub        func() (uint64, error)
+	getLastAttestedSeqMutex       sync.RWMutex
+	getLastAttestedSeqArgsForCall []struct {
+	}
+	getLastAttestedSeqReturns struct {
+		result1 uint64
+		result2 error
+	}
+	getLastAttestedSeqReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	GetLastCommittedSeqStub        func() (uint64, error)
+	getLastCommittedSeqMutex       sync.RWMutex
+	getLastCommittedSeqArgsForCall []struct {
+	}
+	getLastCommittedSeqReturns struct {
+		result1 uint64
+		result2 error
+	}
+	getLastCommittedSeqReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
 	GetReceiptStub        func([]byte) (*transaction.Receipt, error)
 	getReceiptMutex       sync.RWMutex
 	getReceiptArgsForCall []struct {
@@ -45,6 +97,19 @@ type Repository struct {
 		result1 *transaction.Receipt
 		result2 error
 	}
+	GetRejectedStub        func(transaction.ID) (*transaction.Rejected, error)
+	getRejectedMutex       sync.RWMutex
+	getRejectedArgsForCall []struct {
+		arg1 transaction.ID
+	}
+	getRejectedReturns struct {
+		result1 *transaction.Rejected
+		result2 error
+	}
+	getRejectedReturnsOnCall map[int]struct {
+		result1 *transaction.Rejected
+		result2 error
+	}
 	GetStateStub        func(transaction.StateID, bool) (*transaction.State, error)
 	getStateMutex       sync.RWMutex
 	getStateArgsForCall []struct {
@@ -59,6 +124,32 @@ type Repository struct {
 		result1 *transaction.State
 		result2 error
 	}
+	GetStatesByKindStub        func(string) ([]*transaction.State, error)
+	getStatesByKindMutex       sync.RWMutex
+	getStatesByKindArgsForCall []struct {
+		arg1 string
+	}
+	getStatesByKindReturns struct {
+		result1 []*transaction.State
+		result2 error
+	}
+	getStatesByKindReturnsOnCall map[int]struct {
+		result1 []*transaction.State
+		result2 error
+	}
+	GetStatesByOwnerStub        func([]byte) ([]*transaction.State, error)
+	getStatesByOwnerMutex       sync.RWMutex
+	getStatesByOwnerArgsForCall []struct {
+		arg1 []byte
+	}
+	getStatesByOwnerReturns struct {
+		result1 []*transaction.State
+		result2 error
+	}
+	getStatesByOwnerReturnsOnCall map[int]struct {
+		result1 []*transaction.State
+		result2 error
+	}
 	GetTransactionStub        func(transaction.ID) (*transaction.Transaction, error)
 	getTransactionMutex       sync.RWMutex
 	getTransactionArgsForCall []struct {
@@ -72,6 +163,30 @@ type Repository struct {
 		result1 *transaction.Transaction
 		result2 error
 	}
+	NewViewStub        func() (store.View, error)
+	newViewMutex       sync.RWMutex
+	newViewArgsForCall []struct {
+	}
+	newViewReturns struct {
+		result1 store.View
+		result2 error
+	}
+	newViewReturnsOnCall map[int]struct {
+		result1 store.View
+		result2 error
+	}
+	PutAttestationStub        func(transaction.ID, *transaction.Attestation) error
+	putAttestationMutex       sync.RWMutex
+	putAttestationArgsForCall []struct {
+		arg1 transaction.ID
+		arg2 *transaction.Attestation
+	}
+	putAttestationReturns struct {
+		result1 error
+	}
+	putAttestationReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PutCommittedStub        func(transaction.ID, *transaction.Committed) error
 	putCommittedMutex       sync.RWMutex
 	putCommittedArgsForCall []struct {
@@ -95,6 +210,18 @@ type Repository struct {
 	putReceiptReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PutRejectedStub        func(transaction.ID, *transaction.Rejected) error
+	putRejectedMutex       sync.RWMutex
+	putRejectedArgsForCall []struct {
+		arg1 transaction.ID
+		arg2 *transaction.Rejected
+	}
+	putRejectedReturns struct {
+		result1 error
+	}
+	putRejectedReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PutStateStub        func(*transaction.State) error
 	putStateMutex       sync.RWMutex
 	putStateArgsForCall []struct {
@@ -117,25 +244,112 @@ type Repository struct {
 	putTransactionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RejectTransactionStub        func(transaction.ID, *transaction.Rejected) error
+	rejectTransactionMutex       sync.RWMutex
+	rejectTransactionArgsForCall []struct {
+		arg1 transaction.ID
+		arg2 *transaction.Rejected
+	}
+	rejectTransactionReturns struct {
+		result1 error
+	}
+	rejectTransactionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Repository) CommitTransaction(arg1 transaction.ID, arg2 *transaction.Committed, arg3 []*transaction.State, arg4 []transaction.StateID) error {
+	var arg3Copy []*transaction.State
+	if arg3 != nil {
+		arg3Copy = make([]*transaction.State, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	var arg4Copy []transaction.StateID
+	if arg4 != nil {
+		arg4Copy = make([]transaction.StateID, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.commitTransactionMutex.Lock()
+	ret, specificReturn := fake.commitTransactionReturnsOnCall[len(fake.commitTransactionArgsForCall)]
+	fake.commitTransactionArgsForCall = append(fake.commitTransactionArgsForCall, struct {
+		arg1 transaction.ID
+		arg2 *transaction.Committed
+		arg3 []*transaction.State
+		arg4 []transaction.StateID
+	}{arg1, arg2, arg3Copy, arg4Copy})
+	stub := fake.CommitTransactionStub
+	fakeReturns := fake.commitTransactionReturns
+	fake.recordInvocation("CommitTransaction", []interface{}{arg1, arg2, arg3Copy, arg4Copy})
+	fake.commitTransactionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Repository) CommitTransactionCallCount() int {
+	fake.commitTransactionMutex.RLock()
+	defer fake.commitTransactionMutex.RUnlock()
+	return len(fake.commitTransactionArgsForCall)
+}
+
+func (fake *Repository) CommitTransactionCalls(stub func(transaction.ID, *transaction.Committed, []*transaction.State, []transaction.StateID) error) {
+	fake.commitTransactionMutex.Lock()
+	defer fake.commitTransactionMutex.Unlock()
+	fake.CommitTransactionStub = stub
+}
+
+func (fake *Repository) CommitTransactionArgsForCall(i int) (transaction.ID, *transaction.Committed, []*transaction.State, []transaction.StateID) {
+	fake.commitTransactionMutex.RLock()
+	defer fake.commitTransactionMutex.RUnlock()
+	argsForCall := fake.commitTransactionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Repository) CommitTransactionReturns(result1 error) {
+	fake.commitTransactionMutex.Lock()
+	defer fake.commitTransactionMutex.Unlock()
+	fake.CommitTransactionStub = nil
+	fake.commitTransactionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Repository) CommitTransactionReturnsOnCall(i int, result1 error) {
+	fake.commitTransactionMutex.Lock()
+	defer fake.commitTransactionMutex.Unlock()
+	fake.CommitTransactionStub = nil
+	if fake.commitTransactionReturnsOnCall == nil {
+		fake.commitTransactionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.commitTransactionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Repository) ConsumeState(arg1 transaction.StateID) error {
 	fake.consumeStateMutex.Lock()
 	ret, specificReturn := fake.consumeStateReturnsOnCall[len(fake.consumeStateArgsForCall)]
 	fake.consumeStateArgsForCall = append(fake.consumeStateArgsForCall, struct {
 		arg1 transaction.StateID
 	}{arg1})
+	stub := fake.ConsumeStateStub
+	fakeReturns := fake.consumeStateReturns
 	fake.recordInvocation("ConsumeState", []interface{}{arg1})
 	fake.consumeStateMutex.Unlock()
-	if fake.ConsumeStateStub != nil {
-		return fake.ConsumeStateStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.consumeStateReturns
 	return fakeReturns.result1
 }
 
@@ -187,15 +401,16 @@ func (fake *Repository) GetCommitted(arg1 transaction.ID) (*transaction.Committe
 	fake.getCommittedArgsForCall = append(fake.getCommittedArgsForCall, struct {
 		arg1 transaction.ID
 	}{arg1})
+	stub := fake.GetCommittedStub
+	fakeReturns := fake.getCommittedReturns
 	fake.recordInvocation("GetCommitted", []interface{}{arg1})
 	fake.getCommittedMutex.Unlock()
-	if fake.GetCommittedStub != nil {
-		return fake.GetCommittedStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getCommittedReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -244,6 +459,182 @@ func (fake *Repository) GetCommittedReturnsOnCall(i int, result1 *transaction.Co
 	}{result1, result2}
 }
 
+func (fake *Repository) GetCommittedTxID(arg1 uint64) (transaction.ID, error) {
+	fake.getCommittedTxIDMutex.Lock()
+	ret, specificReturn := fake.getCommittedTxIDReturnsOnCall[len(fake.getCommittedTxIDArgsForCall)]
+	fake.getCommittedTxIDArgsForCall = append(fake.getCommittedTxIDArgsForCall, struct {
+		arg1 uint64
+	}{arg1})
+	stub := fake.GetCommittedTxIDStub
+	fakeReturns := fake.getCommittedTxIDReturns
+	fake.recordInvocation("GetCommittedTxID", []interface{}{arg1})
+	fake.getCommittedTxIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetCommittedTxIDCallCount() int {
+	fake.getCommittedTxIDMutex.RLock()
+	defer fake.getCommittedTxIDMutex.RUnlock()
+	return len(fake.getCommittedTxIDArgsForCall)
+}
+
+func (fake *Repository) GetCommittedTxIDCalls(stub func(uint64) (transaction.ID, error)) {
+	fake.getCommittedTxIDMutex.Lock()
+	defer fake.getCommittedTxIDMutex.Unlock()
+	fake.GetCommittedTxIDStub = stub
+}
+
+func (fake *Repository) GetCommittedTxIDArgsForCall(i int) uint64 {
+	fake.getCommittedTxIDMutex.RLock()
+	defer fake.getCommittedTxIDMutex.RUnlock()
+	argsForCall := fake.getCommittedTxIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Repository) GetCommittedTxIDReturns(result1 transaction.ID, result2 error) {
+	fake.getCommittedTxIDMutex.Lock()
+	defer fake.getCommittedTxIDMutex.Unlock()
+	fake.GetCommittedTxIDStub = nil
+	fake.getCommittedTxIDReturns = struct {
+		result1 transaction.ID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetCommittedTxIDReturnsOnCall(i int, result1 transaction.ID, result2 error) {
+	fake.getCommittedTxIDMutex.Lock()
+	defer fake.getCommittedTxIDMutex.Unlock()
+	fake.GetCommittedTxIDStub = nil
+	if fake.getCommittedTxIDReturnsOnCall == nil {
+		fake.getCommittedTxIDReturnsOnCall = make(map[int]struct {
+			result1 transaction.ID
+			result2 error
+		})
+	}
+	fake.getCommittedTxIDReturnsOnCall[i] = struct {
+		result1 transaction.ID
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetLastAttestedSeq() (uint64, error) {
+	fake.getLastAttestedSeqMutex.Lock()
+	ret, specificReturn := fake.getLastAttestedSeqReturnsOnCall[len(fake.getLastAttestedSeqArgsForCall)]
+	fake.getLastAttestedSeqArgsForCall = append(fake.getLastAttestedSeqArgsForCall, struct {
+	}{})
+	stub := fake.GetLastAttestedSeqStub
+	fakeReturns := fake.getLastAttestedSeqReturns
+	fake.recordInvocation("GetLastAttestedSeq", []interface{}{})
+	fake.getLastAttestedSeqMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetLastAttestedSeqCallCount() int {
+	fake.getLastAttestedSeqMutex.RLock()
+	defer fake.getLastAttestedSeqMutex.RUnlock()
+	return len(fake.getLastAttestedSeqArgsForCall)
+}
+
+func (fake *Repository) GetLastAttestedSeqCalls(stub func() (uint64, error)) {
+	fake.getLastAttestedSeqMutex.Lock()
+	defer fake.getLastAttestedSeqMutex.Unlock()
+	fake.GetLastAttestedSeqStub = stub
+}
+
+func (fake *Repository) GetLastAttestedSeqReturns(result1 uint64, result2 error) {
+	fake.getLastAttestedSeqMutex.Lock()
+	defer fake.getLastAttestedSeqMutex.Unlock()
+	fake.GetLastAttestedSeqStub = nil
+	fake.getLastAttestedSeqReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetLastAttestedSeqReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.getLastAttestedSeqMutex.Lock()
+	defer fake.getLastAttestedSeqMutex.Unlock()
+	fake.GetLastAttestedSeqStub = nil
+	if fake.getLastAttestedSeqReturnsOnCall == nil {
+		fake.getLastAttestedSeqReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.getLastAttestedSeqReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetLastCommittedSeq() (uint64, error) {
+	fake.getLastCommittedSeqMutex.Lock()
+	ret, specificReturn := fake.getLastCommittedSeqReturnsOnCall[len(fake.getLastCommittedSeqArgsForCall)]
+	fake.getLastCommittedSeqArgsForCall = append(fake.getLastCommittedSeqArgsForCall, struct {
+	}{})
+	stub := fake.GetLastCommittedSeqStub
+	fakeReturns := fake.getLastCommittedSeqReturns
+	fake.recordInvocation("GetLastCommittedSeq", []interface{}{})
+	fake.getLastCommittedSeqMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetLastCommittedSeqCallCount() int {
+	fake.getLastCommittedSeqMutex.RLock()
+	defer fake.getLastCommittedSeqMutex.RUnlock()
+	return len(fake.getLastCommittedSeqArgsForCall)
+}
+
+func (fake *Repository) GetLastCommittedSeqCalls(stub func() (uint64, error)) {
+	fake.getLastCommittedSeqMutex.Lock()
+	defer fake.getLastCommittedSeqMutex.Unlock()
+	fake.GetLastCommittedSeqStub = stub
+}
+
+func (fake *Repository) GetLastCommittedSeqReturns(result1 uint64, result2 error) {
+	fake.getLastCommittedSeqMutex.Lock()
+	defer fake.getLastCommittedSeqMutex.Unlock()
+	fake.GetLastCommittedSeqStub = nil
+	fake.getLastCommittedSeqReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetLastCommittedSeqReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.getLastCommittedSeqMutex.Lock()
+	defer fake.getLastCommittedSeqMutex.Unlock()
+	fake.GetLastCommittedSeqStub = nil
+	if fake.getLastCommittedSeqReturnsOnCall == nil {
+		fake.getLastCommittedSeqReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.getLastCommittedSeqReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Repository) GetReceipt(arg1 []byte) (*transaction.Receipt, error) {
 	var arg1Copy []byte
 	if arg1 != nil {
@@ -255,15 +646,16 @@ func (fake *Repository) GetReceipt(arg1 []byte) (*transaction.Receipt, error) {
 	fake.getReceiptArgsForCall = append(fake.getReceiptArgsForCall, struct {
 		arg1 []byte
 	}{arg1Copy})
+	stub := fake.GetReceiptStub
+	fakeReturns := fake.getReceiptReturns
 	fake.recordInvocation("GetReceipt", []interface{}{arg1Copy})
 	fake.getReceiptMutex.Unlock()
-	if fake.GetReceiptStub != nil {
-		return fake.GetReceiptStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getReceiptReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -312,6 +704,70 @@ func (fake *Repository) GetReceiptReturnsOnCall(i int, result1 *transaction.Rece
 	}{result1, result2}
 }
 
+func (fake *Repository) GetRejected(arg1 transaction.ID) (*transaction.Rejected, error) {
+	fake.getRejectedMutex.Lock()
+	ret, specificReturn := fake.getRejectedReturnsOnCall[len(fake.getRejectedArgsForCall)]
+	fake.getRejectedArgsForCall = append(fake.getRejectedArgsForCall, struct {
+		arg1 transaction.ID
+	}{arg1})
+	stub := fake.GetRejectedStub
+	fakeReturns := fake.getRejectedReturns
+	fake.recordInvocation("GetRejected", []interface{}{arg1})
+	fake.getRejectedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetRejectedCallCount() int {
+	fake.getRejectedMutex.RLock()
+	defer fake.getRejectedMutex.RUnlock()
+	return len(fake.getRejectedArgsForCall)
+}
+
+func (fake *Repository) GetRejectedCalls(stub func(transaction.ID) (*transaction.Rejected, error)) {
+	fake.getRejectedMutex.Lock()
+	defer fake.getRejectedMutex.Unlock()
+	fake.GetRejectedStub = stub
+}
+
+func (fake *Repository) GetRejectedArgsForCall(i int) transaction.ID {
+	fake.getRejectedMutex.RLock()
+	defer fake.getRejectedMutex.RUnlock()
+	argsForCall := fake.getRejectedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Repository) GetRejectedReturns(result1 *transaction.Rejected, result2 error) {
+	fake.getRejectedMutex.Lock()
+	defer fake.getRejectedMutex.Unlock()
+	fake.GetRejectedStub = nil
+	fake.getRejectedReturns = struct {
+		result1 *transaction.Rejected
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetRejectedReturnsOnCall(i int, result1 *transaction.Rejected, result2 error) {
+	fake.getRejectedMutex.Lock()
+	defer fake.getRejectedMutex.Unlock()
+	fake.GetRejectedStub = nil
+	if fake.getRejectedReturnsOnCall == nil {
+		fake.getRejectedReturnsOnCall = make(map[int]struct {
+			result1 *transaction.Rejected
+			result2 error
+		})
+	}
+	fake.getRejectedReturnsOnCall[i] = struct {
+		result1 *transaction.Rejected
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Repository) GetState(arg1 transaction.StateID, arg2 bool) (*transaction.State, error) {
 	fake.getStateMutex.Lock()
 	ret, specificReturn := fake.getStateReturnsOnCall[len(fake.getStateArgsForCall)]
@@ -319,15 +775,16 @@ func (fake *Repository) GetState(arg1 transaction.StateID, arg2 bool) (*transact
 		arg1 transaction.StateID
 		arg2 bool
 	}{arg1, arg2})
+	stub := fake.GetStateStub
+	fakeReturns := fake.getStateReturns
 	fake.recordInvocation("GetState", []interface{}{arg1, arg2})
 	fake.getStateMutex.Unlock()
-	if fake.GetStateStub != nil {
-		return fake.GetStateStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getStateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -376,21 +833,155 @@ func (fake *Repository) GetStateReturnsOnCall(i int, result1 *transaction.State,
 	}{result1, result2}
 }
 
+func (fake *Repository) GetStatesByKind(arg1 string) ([]*transaction.State, error) {
+	fake.getStatesByKindMutex.Lock()
+	ret, specificReturn := fake.getStatesByKindReturnsOnCall[len(fake.getStatesByKindArgsForCall)]
+	fake.getStatesByKindArgsForCall = append(fake.getStatesByKindArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetStatesByKindStub
+	fakeReturns := fake.getStatesByKindReturns
+	fake.recordInvocation("GetStatesByKind", []interface{}{arg1})
+	fake.getStatesByKindMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetStatesByKindCallCount() int {
+	fake.getStatesByKindMutex.RLock()
+	defer fake.getStatesByKindMutex.RUnlock()
+	return len(fake.getStatesByKindArgsForCall)
+}
+
+func (fake *Repository) GetStatesByKindCalls(stub func(string) ([]*transaction.State, error)) {
+	fake.getStatesByKindMutex.Lock()
+	defer fake.getStatesByKindMutex.Unlock()
+	fake.GetStatesByKindStub = stub
+}
+
+func (fake *Repository) GetStatesByKindArgsForCall(i int) string {
+	fake.getStatesByKindMutex.RLock()
+	defer fake.getStatesByKindMutex.RUnlock()
+	argsForCall := fake.getStatesByKindArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Repository) GetStatesByKindReturns(result1 []*transaction.State, result2 error) {
+	fake.getStatesByKindMutex.Lock()
+	defer fake.getStatesByKindMutex.Unlock()
+	fake.GetStatesByKindStub = nil
+	fake.getStatesByKindReturns = struct {
+		result1 []*transaction.State
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetStatesByKindReturnsOnCall(i int, result1 []*transaction.State, result2 error) {
+	fake.getStatesByKindMutex.Lock()
+	defer fake.getStatesByKindMutex.Unlock()
+	fake.GetStatesByKindStub = nil
+	if fake.getStatesByKindReturnsOnCall == nil {
+		fake.getStatesByKindReturnsOnCall = make(map[int]struct {
+			result1 []*transaction.State
+			result2 error
+		})
+	}
+	fake.getStatesByKindReturnsOnCall[i] = struct {
+		result1 []*transaction.State
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetStatesByOwner(arg1 []byte) ([]*transaction.State, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getStatesByOwnerMutex.Lock()
+	ret, specificReturn := fake.getStatesByOwnerReturnsOnCall[len(fake.getStatesByOwnerArgsForCall)]
+	fake.getStatesByOwnerArgsForCall = append(fake.getStatesByOwnerArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.GetStatesByOwnerStub
+	fakeReturns := fake.getStatesByOwnerReturns
+	fake.recordInvocation("GetStatesByOwner", []interface{}{arg1Copy})
+	fake.getStatesByOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) GetStatesByOwnerCallCount() int {
+	fake.getStatesByOwnerMutex.RLock()
+	defer fake.getStatesByOwnerMutex.RUnlock()
+	return len(fake.getStatesByOwnerArgsForCall)
+}
+
+func (fake *Repository) GetStatesByOwnerCalls(stub func([]byte) ([]*transaction.State, error)) {
+	fake.getStatesByOwnerMutex.Lock()
+	defer fake.getStatesByOwnerMutex.Unlock()
+	fake.GetStatesByOwnerStub = stub
+}
+
+func (fake *Repository) GetStatesByOwnerArgsForCall(i int) []byte {
+	fake.getStatesByOwnerMutex.RLock()
+	defer fake.getStatesByOwnerMutex.RUnlock()
+	argsForCall := fake.getStatesByOwnerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Repository) GetStatesByOwnerReturns(result1 []*transaction.State, result2 error) {
+	fake.getStatesByOwnerMutex.Lock()
+	defer fake.getStatesByOwnerMutex.Unlock()
+	fake.GetStatesByOwnerStub = nil
+	fake.getStatesByOwnerReturns = struct {
+		result1 []*transaction.State
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) GetStatesByOwnerReturnsOnCall(i int, result1 []*transaction.State, result2 error) {
+	fake.getStatesByOwnerMutex.Lock()
+	defer fake.getStatesByOwnerMutex.Unlock()
+	fake.GetStatesByOwnerStub = nil
+	if fake.getStatesByOwnerReturnsOnCall == nil {
+		fake.getStatesByOwnerReturnsOnCall = make(map[int]struct {
+			result1 []*transaction.State
+			result2 error
+		})
+	}
+	fake.getStatesByOwnerReturnsOnCall[i] = struct {
+		result1 []*transaction.State
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Repository) GetTransaction(arg1 transaction.ID) (*transaction.Transaction, error) {
 	fake.getTransactionMutex.Lock()
 	ret, specificReturn := fake.getTransactionReturnsOnCall[len(fake.getTransactionArgsForCall)]
 	fake.getTransactionArgsForCall = append(fake.getTransactionArgsForCall, struct {
 		arg1 transaction.ID
 	}{arg1})
+	stub := fake.GetTransactionStub
+	fakeReturns := fake.getTransactionReturns
 	fake.recordInvocation("GetTransaction", []interface{}{arg1})
 	fake.getTransactionMutex.Unlock()
-	if fake.GetTransactionStub != nil {
-		return fake.GetTransactionStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getTransactionReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -439,6 +1030,124 @@ func (fake *Repository) GetTransactionReturnsOnCall(i int, result1 *transaction.
 	}{result1, result2}
 }
 
+func (fake *Repository) NewView() (store.View, error) {
+	fake.newViewMutex.Lock()
+	ret, specificReturn := fake.newViewReturnsOnCall[len(fake.newViewArgsForCall)]
+	fake.newViewArgsForCall = append(fake.newViewArgsForCall, struct {
+	}{})
+	stub := fake.NewViewStub
+	fakeReturns := fake.newViewReturns
+	fake.recordInvocation("NewView", []interface{}{})
+	fake.newViewMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Repository) NewViewCallCount() int {
+	fake.newViewMutex.RLock()
+	defer fake.newViewMutex.RUnlock()
+	return len(fake.newViewArgsForCall)
+}
+
+func (fake *Repository) NewViewCalls(stub func() (store.View, error)) {
+	fake.newViewMutex.Lock()
+	defer fake.newViewMutex.Unlock()
+	fake.NewViewStub = stub
+}
+
+func (fake *Repository) NewViewReturns(result1 store.View, result2 error) {
+	fake.newViewMutex.Lock()
+	defer fake.newViewMutex.Unlock()
+	fake.NewViewStub = nil
+	fake.newViewReturns = struct {
+		result1 store.View
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) NewViewReturnsOnCall(i int, result1 store.View, result2 error) {
+	fake.newViewMutex.Lock()
+	defer fake.newViewMutex.Unlock()
+	fake.NewViewStub = nil
+	if fake.newViewReturnsOnCall == nil {
+		fake.newViewReturnsOnCall = make(map[int]struct {
+			result1 store.View
+			result2 error
+		})
+	}
+	fake.newViewReturnsOnCall[i] = struct {
+		result1 store.View
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Repository) PutAttestation(arg1 transaction.ID, arg2 *transaction.Attestation) error {
+	fake.putAttestationMutex.Lock()
+	ret, specificReturn := fake.putAttestationReturnsOnCall[len(fake.putAttestationArgsForCall)]
+	fake.putAttestationArgsForCall = append(fake.putAttestationArgsForCall, struct {
+		arg1 transaction.ID
+		arg2 *transaction.Attestation
+	}{arg1, arg2})
+	stub := fake.PutAttestationStub
+	fakeReturns := fake.putAttestationReturns
+	fake.recordInvocation("PutAttestation", []interface{}{arg1, arg2})
+	fake.putAttestationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Repository) PutAttestationCallCount() int {
+	fake.putAttestationMutex.RLock()
+	defer fake.putAttestationMutex.RUnlock()
+	return len(fake.putAttestationArgsForCall)
+}
+
+func (fake *Repository) PutAttestationCalls(stub func(transaction.ID, *transaction.Attestation) error) {
+	fake.putAttestationMutex.Lock()
+	defer fake.putAttestationMutex.Unlock()
+	fake.PutAttestationStub = stub
+}
+
+func (fake *Repository) PutAttestationArgsForCall(i int) (transaction.ID, *transaction.Attestation) {
+	fake.putAttestationMutex.RLock()
+	defer fake.putAttestationMutex.RUnlock()
+	argsForCall := fake.putAttestationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Repository) PutAttestationReturns(result1 error) {
+	fake.putAttestationMutex.Lock()
+	defer fake.putAttestationMutex.Unlock()
+	fake.PutAttestationStub = nil
+	fake.putAttestationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Repository) PutAttestationReturnsOnCall(i int, result1 error) {
+	fake.putAttestationMutex.Lock()
+	defer fake.putAttestationMutex.Unlock()
+	fake.PutAttestationStub = nil
+	if fake.putAttestationReturnsOnCall == nil {
+		fake.putAttestationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putAttestationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Repository) PutCommitted(arg1 transaction.ID, arg2 *transaction.Committed) error {
 	fake.putCommittedMutex.Lock()
 	ret, specificReturn := fake.putCommittedReturnsOnCall[len(fake.putCommittedArgsForCall)]
@@ -446,15 +1155,16 @@ func (fake *Repository) PutCommitted(arg1 transaction.ID, arg2 *transaction.Comm
 		arg1 transaction.ID
 		arg2 *transaction.Committed
 	}{arg1, arg2})
+	stub := fake.PutCommittedStub
+	fakeReturns := fake.putCommittedReturns
 	fake.recordInvocation("PutCommitted", []interface{}{arg1, arg2})
 	fake.putCommittedMutex.Unlock()
-	if fake.PutCommittedStub != nil {
-		return fake.PutCommittedStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.putCommittedReturns
 	return fakeReturns.result1
 }
 
@@ -506,15 +1216,16 @@ func (fake *Repository) PutReceipt(arg1 *transaction.Receipt) error {
 	fake.putReceiptArgsForCall = append(fake.putReceiptArgsForCall, struct {
 		arg1 *transaction.Receipt
 	}{arg1})
+	stub := fake.PutReceiptStub
+	fakeReturns := fake.putReceiptReturns
 	fake.recordInvocation("PutReceipt", []interface{}{arg1})
 	fake.putReceiptMutex.Unlock()
-	if fake.PutReceiptStub != nil {
-		return fake.PutReceiptStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.putReceiptReturns
 	return fakeReturns.result1
 }
 
@@ -560,21 +1271,84 @@ func (fake *Repository) PutReceiptReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Repository) PutRejected(arg1 transaction.ID, arg2 *transaction.Rejected) error {
+	fake.putRejectedMutex.Lock()
+	ret, specificReturn := fake.putRejectedReturnsOnCall[len(fake.putRejectedArgsForCall)]
+	fake.putRejectedArgsForCall = append(fake.putRejectedArgsForCall, struct {
+		arg1 transaction.ID
+		arg2 *transaction.Rejected
+	}{arg1, arg2})
+	stub := fake.PutRejectedStub
+	fakeReturns := fake.putRejectedReturns
+	fake.recordInvocation("PutRejected", []interface{}{arg1, arg2})
+	fake.putRejectedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Repository) PutRejectedCallCount() int {
+	fake.putRejectedMutex.RLock()
+	defer fake.putRejectedMutex.RUnlock()
+	return len(fake.putRejectedArgsForCall)
+}
+
+func (fake *Repository) PutRejectedCalls(stub func(transaction.ID, *transaction.Rejected) error) {
+	fake.putRejectedMutex.Lock()
+	defer fake.putRejectedMutex.Unlock()
+	fake.PutRejectedStub = stub
+}
+
+func (fake *Repository) PutRejectedArgsForCall(i int) (transaction.ID, *transaction.Rejected) {
+	fake.putRejectedMutex.RLock()
+	defer fake.putRejectedMutex.RUnlock()
+	argsForCall := fake.putRejectedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Repository) PutRejectedReturns(result1 error) {
+	fake.putRejectedMutex.Lock()
+	defer fake.putRejectedMutex.Unlock()
+	fake.PutRejectedStub = nil
+	fake.putRejectedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Repository) PutRejectedReturnsOnCall(i int, result1 error) {
+	fake.putRejectedMutex.Lock()
+	defer fake.putRejectedMutex.Unlock()
+	fake.PutRejectedStub = nil
+	if fake.putRejectedReturnsOnCall == nil {
+		fake.putRejectedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putRejectedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Repository) PutState(arg1 *transaction.State) error {
 	fake.putStateMutex.Lock()
 	ret, specificReturn := fake.putStateReturnsOnCall[len(fake.putStateArgsForCall)]
 	fake.putStateArgsForCall = append(fake.putStateArgsForCall, struct {
 		arg1 *transaction.State
 	}{arg1})
+	stub := fake.PutStateStub
+	fakeReturns := fake.putStateReturns
 	fake.recordInvocation("PutState", []interface{}{arg1})
 	fake.putStateMutex.Unlock()
-	if fake.PutStateStub != nil {
-		return fake.PutStateStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.putStateReturns
 	return fakeReturns.result1
 }
 
@@ -626,15 +1400,16 @@ func (fake *Repository) PutTransaction(arg1 *transaction.Transaction) error {
 	fake.putTransactionArgsForCall = append(fake.putTransactionArgsForCall, struct {
 		arg1 *transaction.Transaction
 	}{arg1})
+	stub := fake.PutTransactionStub
+	fakeReturns := fake.putTransactionReturns
 	fake.recordInvocation("PutTransaction", []interface{}{arg1})
 	fake.putTransactionMutex.Unlock()
-	if fake.PutTransactionStub != nil {
-		return fake.PutTransactionStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.putTransactionReturns
 	return fakeReturns.result1
 }
 
@@ -680,27 +1455,111 @@ func (fake *Repository) PutTransactionReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Repository) RejectTransaction(arg1 transaction.ID, arg2 *transaction.Rejected) error {
+	fake.rejectTransactionMutex.Lock()
+	ret, specificReturn := fake.rejectTransactionReturnsOnCall[len(fake.rejectTransactionArgsForCall)]
+	fake.rejectTransactionArgsForCall = append(fake.rejectTransactionArgsForCall, struct {
+		arg1 transaction.ID
+		arg2 *transaction.Rejected
+	}{arg1, arg2})
+	stub := fake.RejectTransactionStub
+	fakeReturns := fake.rejectTransactionReturns
+	fake.recordInvocation("RejectTransaction", []interface{}{arg1, arg2})
+	fake.rejectTransactionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Repository) RejectTransactionCallCount() int {
+	fake.rejectTransactionMutex.RLock()
+	defer fake.rejectTransactionMutex.RUnlock()
+	return len(fake.rejectTransactionArgsForCall)
+}
+
+func (fake *Repository) RejectTransactionCalls(stub func(transaction.ID, *transaction.Rejected) error) {
+	fake.rejectTransactionMutex.Lock()
+	defer fake.rejectTransactionMutex.Unlock()
+	fake.RejectTransactionStub = stub
+}
+
+func (fake *Repository) RejectTransactionArgsForCall(i int) (transaction.ID, *transaction.Rejected) {
+	fake.rejectTransactionMutex.RLock()
+	defer fake.rejectTransactionMutex.RUnlock()
+	argsForCall := fake.rejectTransactionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Repository) RejectTransactionReturns(result1 error) {
+	fake.rejectTransactionMutex.Lock()
+	defer fake.rejectTransactionMutex.Unlock()
+	fake.RejectTransactionStub = nil
+	fake.rejectTransactionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Repository) RejectTransactionReturnsOnCall(i int, result1 error) {
+	fake.rejectTransactionMutex.Lock()
+	defer fake.rejectTransactionMutex.Unlock()
+	fake.RejectTransactionStub = nil
+	if fake.rejectTransactionReturnsOnCall == nil {
+		fake.rejectTransactionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rejectTransactionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Repository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.commitTransactionMutex.RLock()
+	defer fake.commitTransactionMutex.RUnlock()
 	fake.consumeStateMutex.RLock()
 	defer fake.consumeStateMutex.RUnlock()
 	fake.getCommittedMutex.RLock()
 	defer fake.getCommittedMutex.RUnlock()
+	fake.getCommittedTxIDMutex.RLock()
+	defer fake.getCommittedTxIDMutex.RUnlock()
+	fake.getLastAttestedSeqMutex.RLock()
+	defer fake.getLastAttestedSeqMutex.RUnlock()
+	fake.getLastCommittedSeqMutex.RLock()
+	defer fake.getLastCommittedSeqMutex.RUnlock()
 	fake.getReceiptMutex.RLock()
 	defer fake.getReceiptMutex.RUnlock()
+	fake.getRejectedMutex.RLock()
+	defer fake.getRejectedMutex.RUnlock()
 	fake.getStateMutex.RLock()
 	defer fake.getStateMutex.RUnlock()
+	fake.getStatesByKindMutex.RLock()
+	defer fake.getStatesByKindMutex.RUnlock()
+	fake.getStatesByOwnerMutex.RLock()
+	defer fake.getStatesByOwnerMutex.RUnlock()
 	fake.getTransactionMutex.RLock()
 	defer fake.getTransactionMutex.RUnlock()
+	fake.newViewMutex.RLock()
+	defer fake.newViewMutex.RUnlock()
+	fake.putAttestationMutex.RLock()
+	defer fake.putAttestationMutex.RUnlock()
 	fake.putCommittedMutex.RLock()
 	defer fake.putCommittedMutex.RUnlock()
 	fake.putReceiptMutex.RLock()
 	defer fake.putReceiptMutex.RUnlock()
+	fake.putRejectedMutex.RLock()
+	defer fake.putRejectedMutex.RUnlock()
 	fake.putStateMutex.RLock()
 	defer fake.putStateMutex.RUnlock()
 	fake.putTransactionMutex.RLock()
 	defer fake.putTransactionMutex.RUnlock()
+	fake.rejectTransactionMutex.RLock()
+	defer fake.rejectTransactionMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
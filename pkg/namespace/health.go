@@ -0,0 +1,102 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Health reports whether a namespace is processing ordered receipts. When
+// commit processing has halted, the sequence number of the entry that could
+// not be processed and the cause are recorded.
+type Health struct {
+	Halted   bool
+	Seq      uint64
+	Cause    string
+	HaltedAt time.Time
+}
+
+// Health returns the current health of the namespace.
+func (ns *Namespace) Health() Health {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.halted == nil {
+		return Health{}
+	}
+	return *ns.halted
+}
+
+// Resume restarts delivery of ordered receipts after commit processing has
+// halted. Delivery resumes with the entry that follows the last committed
+// transaction so the entry that caused the halt is processed again. Resume
+// should only be called once the cause of the halt has been addressed.
+func (ns *Namespace) Resume() error {
+	ns.mutex.Lock()
+	if ns.halted == nil {
+		ns.mutex.Unlock()
+		return errors.WithMessagef(ErrNamespaceNotHalted, "namespace %q", ns.Name)
+	}
+	doneC := ns.doneC
+	ns.mutex.Unlock()
+
+	// The delivery loop may still be notifying submitters, which requires
+	// the mutex, while it exits.
+	<-doneC
+
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	// Another caller may have resumed the namespace while waiting.
+	if ns.halted == nil || ns.doneC != doneC {
+		return errors.WithMessagef(ErrNamespaceNotHalted, "namespace %q", ns.Name)
+	}
+
+	ns.Logger.Info("resuming commit processing", zap.Uint64("halted_seq", ns.halted.Seq))
+	ns.halted = nil
+	if ns.started {
+		ns.start()
+	}
+	return nil
+}
+
+// start launches the delivery loop. The mutex must be held.
+func (ns *Namespace) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ns.cancel = cancel
+	ns.doneC = make(chan struct{})
+	go ns.deliver(ctx)
+}
+
+// halt records that commit processing stopped at seq and fails the
+// submitters that are waiting for their receipts to be processed. Only the
+// first halt is recorded until the namespace is resumed.
+func (ns *Namespace) halt(seq uint64, cause error) {
+	ns.Logger.Error("commit processing halted", zap.Uint64("seq", seq), zap.Error(cause))
+
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.halted != nil {
+		return
+	}
+	ns.halted = &Health{
+		Halted:   true,
+		Seq:      seq,
+		Cause:    cause.Error(),
+		HaltedAt: time.Now().UTC(),
+	}
+
+	ns.failWaiters(ns.haltedError())
+}
+
+// haltedError returns the error reported to submitters while the namespace
+// is halted. The mutex must be held.
+func (ns *Namespace) haltedError() error {
+	return errors.WithMessagef(ErrNamespaceHalted, "commit processing halted at seq %d: %s", ns.halted.Seq, ns.halted.Cause)
+}
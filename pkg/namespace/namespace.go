@@ -5,52 +5,248 @@ package namespace
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/sykesm/batik/pkg/merkle"
 	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
+const (
+	// DefaultDeliverRetryInterval is the time to wait before retrying the
+	// delivery of an ordered receipt from an unavailable total order. The
+	// interval doubles with each consecutive failure.
+	DefaultDeliverRetryInterval = 100 * time.Millisecond
+	// MaxDeliverRetryInterval bounds the time to wait between attempts to
+	// deliver from an unavailable total order.
+	MaxDeliverRetryInterval = 10 * time.Second
+)
+
+// A TotalOrder establishes a single sequence of transaction receipts that all
+// members of a namespace commit in the same order.
+type TotalOrder interface {
+	// Broadcast submits an entry for ordering.
+	Broadcast(context.Context, totalorder.TXIDAndHMAC) error
+	// Deliver blocks until the entry at the provided sequence number has been
+	// ordered and returns it.
+	Deliver(context.Context, uint64) (totalorder.TXIDAndHMAC, error)
+}
+
 // Namespace carries all of the resources required
 // for the operation of a namespace.
 type Namespace struct {
+	Name   string
 	Logger *zap.Logger
 	Hasher merkle.Hasher
 
-	LevelDB   *store.LevelDBKV
+	KV        store.KV
 	Repo      Repository
 	committer *committer
+	attestor  *attestor
+	workers   int
+	order     TotalOrder
+	secret    []byte
+	retry     time.Duration // retry is the initial delay before delivering from an unavailable total order again
+
+	mutex    sync.Mutex
+	waiters  map[string][]chan error
+	commitC  chan struct{} // commitC is closed when an ordered receipt has been processed
+	halted   *Health       // halted is set when commit processing has stopped
+	started  bool          // started is set while ordered receipts are delivered
+	closed   bool          // closed is set once the namespace begins closing
+	requests sync.WaitGroup
+	cancel   context.CancelFunc
+	doneC    chan struct{}
 }
 
+// New creates a Namespace. Ordered receipts are not delivered from the total
+// order to the committer until the namespace is started.
+//
+// Transactions must be accepted by every validator registered in kinds for
+// the kinds of their states to be committed. The validators chain is used for
+// kinds without registered validators. The names of the validators are
+// recorded with the transactions they commit or reject.
+//
+// When workers is greater than one, transactions are resolved and validated
+// concurrently by that many workers; commits are always applied in sequence
+// order.
+//
+// The secret is shared by the members of the namespace and authenticates the
+// receipts they submit to the total order; ordered entries that were not
+// authenticated with the secret are not committed. The secret is required;
+// without it anyone with access to the total order could submit receipts to
+// the namespace.
+//
+// When identity is not nil, every commit is attested with a signature from
+// the identity key. Attestations are added to commit records after the
+// commit while ordered receipts are delivered; a failure to attest a commit
+// is retried and does not halt commit processing.
 func New(
+	name string,
 	logger *zap.Logger,
 	hasher merkle.Hasher,
-	level *store.LevelDBKV,
-	validator Validator,
+	kv store.KV,
+	validators ValidatorChain,
+	kinds KindValidators,
+	workers int,
+	order TotalOrder,
+	secret []byte,
+	identity *ecdsa.PrivateKey,
 ) *Namespace {
-	repo := store.NewRepository(level)
+	repo := store.NewRepository(kv)
 
 	return &Namespace{
-		Logger:  logger,
-		Hasher:  hasher,
-		LevelDB: level,
-		Repo:    repo,
-		committer: &committer{
-			repo:      repo,
-			validator: validator,
-		},
+		Name:      name,
+		Logger:    logger,
+		Hasher:    hasher,
+		KV:        kv,
+		Repo:      repo,
+		committer: newCommitter(repo, validators, kinds),
+		attestor:  newAttestor(name, identity, order),
+		workers:   workers,
+		order:     order,
+		secret:    secret,
+		retry:     DefaultDeliverRetryInterval,
+		waiters:   map[string][]chan error{},
+	}
+}
+
+// Start begins delivering ordered receipts from the total order to the
+// committer. Delivery resumes with the entry that follows the last committed
+// transaction. When commit processing fails in a way that requires
+// intervention, the namespace halts and rejects new submissions until it is
+// resumed. Starting a namespace that has already been started or that has
+// been closed has no effect.
+func (ns *Namespace) Start() {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.started || ns.closed {
+		return
+	}
+	ns.started = true
+	ns.start()
+}
+
+// Started returns true when the namespace has been started and not stopped.
+func (ns *Namespace) Started() bool {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	return ns.started
+}
+
+// Stop terminates delivery of ordered receipts and waits for the delivery
+// loop to exit.
+func (ns *Namespace) Stop() {
+	ns.mutex.Lock()
+	cancel, doneC := ns.cancel, ns.doneC
+	ns.started = false
+	ns.mutex.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-doneC
+}
+
+// Close stops delivery of ordered receipts and closes the namespace
+// database. Requests are refused once the namespace begins closing.
+// Submitters that are waiting for their receipts to be processed are failed
+// and the database is closed after the requests in flight have been
+// released.
+func (ns *Namespace) Close() error {
+	ns.mutex.Lock()
+	if ns.closed {
+		ns.mutex.Unlock()
+		return ns.closedError()
+	}
+	ns.closed = true
+	ns.mutex.Unlock()
+
+	ns.Stop()
+
+	ns.mutex.Lock()
+	ns.failWaiters(ns.closedError())
+	// Wake subscribers so they observe the namespace is closed.
+	if ns.commitC != nil {
+		close(ns.commitC)
+		ns.commitC = nil
+	}
+	ns.mutex.Unlock()
+
+	ns.requests.Wait()
+	if ns.KV == nil {
+		return nil
+	}
+	return ns.KV.Close()
+}
+
+// Acquire registers a request that uses the namespace database. The database
+// is not closed until every acquired request has been released. An error
+// that matches ErrNamespaceClosed is returned once the namespace has begun
+// closing.
+func (ns *Namespace) Acquire() error {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.closed {
+		return ns.closedError()
 	}
+	ns.requests.Add(1)
+	return nil
+}
+
+// Release completes a request registered with Acquire.
+func (ns *Namespace) Release() {
+	ns.requests.Done()
+}
+
+// closedError returns the error reported to requests once the namespace has
+// begun closing.
+func (ns *Namespace) closedError() error {
+	return errors.WithMessagef(ErrNamespaceClosed, "namespace %q", ns.Name)
 }
 
+// Submit stores the transaction and its receipt, broadcasts the receipt to the
+// total order, and waits for the ordered receipt to be committed. The result
+// of commit processing is returned to the caller.
+//
+// Transactions that have already been committed or rejected are not
+// processed again; the existing outcome is returned instead. Pending
+// transactions are broadcast again in case the original receipt was never
+// ordered. While the namespace is halted, other transactions are rejected
+// with an error that matches ErrNamespaceHalted.
 func (ns *Namespace) Submit(ctx context.Context, signed *transaction.Signed) error {
-	// TODO, optimization, check if this transaction exists and if it's already been
-	// committed.
+	if err := ns.Acquire(); err != nil {
+		return err
+	}
+	defer ns.Release()
+
+	status, err := txStatus(ns.Repo, signed.Transaction.ID)
+	if err != nil {
+		return err
+	}
+	switch status.State {
+	case TxCommitted:
+		return nil
+	case TxRejected:
+		return newRejectedError(status.Rejected)
+	}
+
+	if err := ns.checkHalted(); err != nil {
+		return err
+	}
 
 	// TODO, mark in the store that this tx has been disseminated (by us).
-	err := ns.Repo.PutTransaction(signed.Transaction)
+	err = ns.Repo.PutTransaction(signed.Transaction)
 	if err != nil {
 		return errors.WithMessage(err, "failed to store transaction")
 	}
@@ -64,7 +260,193 @@ func (ns *Namespace) Submit(ctx context.Context, signed *transaction.Signed) err
 	// TODO, actually disseminate once we have some notion of
 	// other peers in this namespace.
 
-	// TODO, order the receipt ID
+	resultC, err := ns.wait(receipt.ID)
+	if err != nil {
+		return err
+	}
+	defer ns.cancelWait(receipt.ID, resultC)
+
+	err = ns.order.Broadcast(ctx, totalorder.NewTXIDAndHMAC(ns.secret, receipt.ID))
+	if err != nil {
+		return errors.WithMessage(err, "failed to order transaction receipt")
+	}
+
+	select {
+	case err := <-resultC:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver retrieves ordered entries from the total order, in sequence, and
+// commits the receipts that belong to this namespace. Delivery stops when
+// commit processing halts. Commits are attested by a separate goroutine that
+// runs for the lifetime of the delivery loop.
+func (ns *Namespace) deliver(ctx context.Context) {
+	defer close(ns.doneC)
+
+	if ns.attestor != nil {
+		ctx, cancel := context.WithCancel(ctx)
+		attestedC := make(chan struct{})
+		go func() {
+			defer close(attestedC)
+			ns.attestCommits(ctx)
+		}()
+		defer func() {
+			cancel()
+			<-attestedC
+		}()
+	}
+
+	start, err := ns.NextSeq()
+	if err != nil {
+		ns.halt(start, errors.WithMessage(err, "failed to determine the sequence to resume delivery from"))
+		return
+	}
+	if start != 0 {
+		ns.Logger.Info("resuming delivery of ordered receipts", zap.Uint64("seq", start))
+	}
+
+	if ns.workers > 1 {
+		ns.deliverParallel(ctx, start)
+		return
+	}
+
+	for seq := start; ; seq++ {
+		tah, err := ns.deliverSeq(ctx, seq)
+		if err != nil {
+			if ctx.Err() == nil {
+				ns.halt(seq, errors.WithMessage(err, "failed to deliver ordered receipt"))
+			}
+			return
+		}
+
+		if !tah.Verify(ns.secret) {
+			// Not a receipt for this namespace or one that was not submitted
+			// by a namespace member.
+			ns.Logger.Debug("skipping ordered receipt with unverified hmac", zap.Uint64("seq", seq))
+			continue
+		}
+
+		err = ns.committer.commit(tah.ID, seq)
+		ns.notify(tah.ID, err)
+		if errors.Is(err, ErrHalt) {
+			ns.halt(seq, err)
+			return
+		}
+	}
+}
+
+// deliverSeq returns the ordered entry at seq. Deliveries that fail because
+// the total order is unavailable are retried with an increasing delay until
+// the context is done; other failures are returned.
+func (ns *Namespace) deliverSeq(ctx context.Context, seq uint64) (totalorder.TXIDAndHMAC, error) {
+	delay := ns.retry
+	for {
+		tah, err := ns.order.Deliver(ctx, seq)
+		if !errors.Is(err, totalorder.ErrUnavailable) || ctx.Err() != nil {
+			return tah, err
+		}
+
+		ns.Logger.Warn("total order is unavailable, retrying delivery", zap.Uint64("seq", seq), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return totalorder.TXIDAndHMAC{}, ctx.Err()
+		}
+		if delay *= 2; delay > MaxDeliverRetryInterval {
+			delay = MaxDeliverRetryInterval
+		}
+	}
+}
+
+// NextSeq returns the sequence number of the first ordered entry that
+// follows the last committed transaction.
+func (ns *Namespace) NextSeq() (uint64, error) {
+	last, err := ns.Repo.GetLastCommittedSeq()
+	if store.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// checkHalted returns an error that matches ErrNamespaceHalted when commit
+// processing has halted.
+func (ns *Namespace) checkHalted() error {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.halted != nil {
+		return ns.haltedError()
+	}
+	return nil
+}
+
+// wait registers a submitter waiting for a receipt to be processed. An error
+// is returned when the namespace is halted or closing since the receipt would
+// never be processed.
+func (ns *Namespace) wait(receiptID []byte) (chan error, error) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if ns.closed {
+		return nil, ns.closedError()
+	}
+	if ns.halted != nil {
+		return nil, ns.haltedError()
+	}
+
+	resultC := make(chan error, 1)
+	ns.waiters[string(receiptID)] = append(ns.waiters[string(receiptID)], resultC)
+	return resultC, nil
+}
+
+func (ns *Namespace) cancelWait(receiptID []byte, resultC chan error) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
 
-	return ns.committer.commit(receipt.ID)
+	waiters := ns.waiters[string(receiptID)]
+	for i, w := range waiters {
+		if w == resultC {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(ns.waiters, string(receiptID))
+		return
+	}
+	ns.waiters[string(receiptID)] = waiters
+}
+
+// failWaiters delivers an error to every submitter waiting for a receipt to
+// be processed. The mutex must be held.
+func (ns *Namespace) failWaiters(err error) {
+	for receiptID, waiters := range ns.waiters {
+		for _, resultC := range waiters {
+			resultC <- err
+		}
+		delete(ns.waiters, receiptID)
+	}
+}
+
+// notify delivers the result of commit processing to the submitters waiting
+// on a receipt and wakes subscribers waiting for commits.
+func (ns *Namespace) notify(receiptID []byte, err error) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	for _, resultC := range ns.waiters[string(receiptID)] {
+		resultC <- err
+	}
+	delete(ns.waiters, string(receiptID))
+
+	if ns.commitC != nil {
+		close(ns.commitC)
+		ns.commitC = nil
+	}
 }
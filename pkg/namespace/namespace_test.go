@@ -7,14 +7,20 @@ import (
 	"context"
 	"crypto"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 
 	"github.com/sykesm/batik/pkg/namespace/fake"
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
 	"github.com/sykesm/batik/pkg/store"
 	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
 	"github.com/sykesm/batik/pkg/transaction"
 	"github.com/sykesm/batik/pkg/validator"
 )
@@ -28,9 +34,17 @@ func TestNamespace_New(t *testing.T) {
 	logger := zap.NewExample()
 	v := validator.NewSignature()
 
-	ns := New(logger, crypto.SHA256, storeDB, v)
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	ns := New("namespace", logger, crypto.SHA256, storeDB, ValidatorChain{{Name: "signature-builtin", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	gt.Expect(ns.Started()).To(BeFalse())
+	ns.Start()
+	defer ns.Stop()
+	gt.Expect(ns.Started()).To(BeTrue())
+	gt.Expect(ns.Name).To(Equal("namespace"))
 	gt.Expect(ns.Logger).To(Equal(logger))
-	gt.Expect(ns.LevelDB).To(Equal(storeDB))
+	gt.Expect(ns.KV).To(Equal(storeDB))
 	gt.Expect(ns.Repo).NotTo(BeNil())
 }
 
@@ -43,6 +57,20 @@ func newKVDB(t *testing.T) (*store.LevelDBKV, func()) {
 	return db, cleanup
 }
 
+func newTotalOrder(t testing.TB) (*totalorder.InProcess, func()) {
+	db, err := store.NewLevelDB("")
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	orderStore, err := totalorder.NewStore(crypto.SHA256, db)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	order := totalorder.NewInProcess(orderStore)
+	return order, func() {
+		order.Stop()
+		tested.Close(t, db)
+	}
+}
+
 func TestNamespace_Submit(t *testing.T) {
 	var (
 		ns       *Namespace
@@ -50,18 +78,25 @@ func TestNamespace_Submit(t *testing.T) {
 	)
 
 	setup := func() {
-		fakeRepo = &fake.Repository{}
+		fakeRepo = newFakeRepository()
 
 		ns = &Namespace{
-			Logger:  zap.NewExample(),
-			Hasher:  crypto.SHA256,
-			LevelDB: nil, // We use a faked repo, so no db needed
-			Repo:    fakeRepo,
+			Name:   "namespace",
+			Logger: zap.NewExample(),
+			Hasher: crypto.SHA256,
+			KV:     nil, // We use a faked repo, so no db needed
+			Repo:   fakeRepo,
 			committer: &committer{
-				validator: validator.NewSignature(),
-				repo:      fakeRepo,
+				validators: ValidatorChain{{Name: "test", Validator: validator.NewSignature()}},
+				repo:       fakeRepo,
 			},
+			order:   fakeOrder{},
+			waiters: map[string][]chan error{},
 		}
+
+		fakeRepo.GetCommittedReturns(nil, &store.NotFoundError{Err: errors.New("not-committed")})
+		fakeRepo.GetRejectedReturns(nil, &store.NotFoundError{Err: errors.New("not-rejected")})
+		fakeRepo.GetTransactionReturns(nil, &store.NotFoundError{Err: errors.New("not-found")})
 	}
 
 	signed := &transaction.Signed{
@@ -70,6 +105,41 @@ func TestNamespace_Submit(t *testing.T) {
 		},
 	}
 
+	t.Run("StatusFails", func(t *testing.T) {
+		setup()
+		gt := NewGomegaWithT(t)
+
+		fakeRepo.GetCommittedReturns(nil, errors.New("get-committed-error"))
+
+		err := ns.Submit(context.Background(), signed)
+		gt.Expect(err).To(MatchError(ContainSubstring("get-committed-error")))
+		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
+	})
+
+	t.Run("AlreadyCommitted", func(t *testing.T) {
+		setup()
+		gt := NewGomegaWithT(t)
+
+		fakeRepo.GetCommittedReturns(&transaction.Committed{SeqNo: 1}, nil)
+
+		err := ns.Submit(context.Background(), signed)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.PutReceiptCallCount()).To(Equal(0))
+	})
+
+	t.Run("AlreadyRejected", func(t *testing.T) {
+		setup()
+		gt := NewGomegaWithT(t)
+
+		fakeRepo.GetRejectedReturns(&transaction.Rejected{Reason: "validation failed: bad-tx"}, nil)
+
+		err := ns.Submit(context.Background(), signed)
+		gt.Expect(err).To(MatchError("validation failed: bad-tx"))
+		gt.Expect(fakeRepo.PutTransactionCallCount()).To(Equal(0))
+		gt.Expect(fakeRepo.PutReceiptCallCount()).To(Equal(0))
+	})
+
 	t.Run("PutTransactionFails", func(t *testing.T) {
 		setup()
 		gt := NewGomegaWithT(t)
@@ -90,4 +160,733 @@ func TestNamespace_Submit(t *testing.T) {
 		err := ns.Submit(context.Background(), signed)
 		gt.Expect(err).To(MatchError("failed to store transaction receipt: put-receipt-error"))
 	})
+
+	t.Run("BroadcastFails", func(t *testing.T) {
+		setup()
+		gt := NewGomegaWithT(t)
+
+		ns.order = fakeOrder{broadcastErr: errors.New("broadcast-error")}
+
+		err := ns.Submit(context.Background(), signed)
+		gt.Expect(err).To(MatchError("failed to order transaction receipt: broadcast-error"))
+		gt.Expect(ns.waiters).To(BeEmpty())
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		setup()
+		gt := NewGomegaWithT(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := ns.Submit(ctx, signed)
+		gt.Expect(err).To(MatchError(context.Canceled))
+		gt.Expect(ns.waiters).To(BeEmpty())
+	})
+}
+
+func TestNamespace_SubmitOrdered(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db1, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db1)
+	db2, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db2)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	ns1 := New("ns1", zap.NewNop(), crypto.SHA256, db1, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret1"), nil)
+	ns1.Start()
+	defer ns1.Stop()
+	ns2 := New("ns2", zap.NewNop(), crypto.SHA256, db2, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret2"), nil)
+	ns2.Start()
+	defer ns2.Stop()
+
+	submit := func(ns *Namespace, salt string) *transaction.Transaction {
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+			Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+		gt.Expect(err).NotTo(HaveOccurred())
+		return tx
+	}
+
+	tx1 := submit(ns1, "tx1")
+	tx2 := submit(ns2, "tx2")
+	tx3 := submit(ns1, "tx3")
+
+	committed, err := ns1.Repo.GetCommitted(tx1.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(0)))
+
+	committed, err = ns2.Repo.GetCommitted(tx2.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(1)))
+
+	committed, err = ns1.Repo.GetCommitted(tx3.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(2)))
+
+	_, err = ns1.Repo.GetCommitted(tx2.ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+	_, err = ns2.Repo.GetCommitted(tx1.ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+}
+
+func TestNamespace_Status(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	validated := 0
+	rejectingValidator := validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		validated++
+		if len(req.ResolvedTransaction.Outputs) != 1 {
+			return &validationv1.ValidateResponse{Valid: false, ErrorMessage: "single-output-required"}, nil
+		}
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	ns := New("ns", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "rejecting", Validator: rejectingValidator}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	newTx := func(salt string, outputs int) *transaction.Transaction {
+		txOutputs := make([]*txv1.State, outputs)
+		for i := range txOutputs {
+			txOutputs[i] = &txv1.State{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}
+		}
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+			Outputs: txOutputs,
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		return tx
+	}
+
+	valid := newTx("valid", 1)
+	status, err := ns.Status(valid.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(status).To(Equal(&TxStatus{State: TxUnknown}))
+
+	gt.Expect(ns.Repo.PutTransaction(valid)).To(Succeed())
+	status, err = ns.Status(valid.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(status).To(Equal(&TxStatus{State: TxPending}))
+
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: valid})
+	gt.Expect(err).NotTo(HaveOccurred())
+	receipt := transaction.NewReceipt(crypto.SHA256, valid.ID, nil)
+	status, err = ns.Status(valid.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(status).To(Equal(&TxStatus{
+		State:     TxCommitted,
+		Committed: &transaction.Committed{SeqNo: 0, ReceiptID: receipt.ID, Validators: []string{"rejecting"}},
+	}))
+
+	invalid := newTx("invalid", 2)
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: invalid})
+	gt.Expect(err).To(MatchError("validation failed: single-output-required"))
+	receipt = transaction.NewReceipt(crypto.SHA256, invalid.ID, nil)
+	status, err = ns.Status(invalid.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(status.State).To(Equal(TxRejected))
+	gt.Expect(status.Rejected.Timestamp).NotTo(BeZero())
+	status.Rejected.Timestamp = time.Time{}
+	gt.Expect(status.Rejected).To(Equal(&transaction.Rejected{
+		SeqNo:        1,
+		ReceiptID:    receipt.ID,
+		Reason:       "validation failed: single-output-required",
+		Validators:   []string{"rejecting"},
+		ErrorMessage: "single-output-required",
+	}))
+	gt.Expect(validated).To(Equal(2))
+
+	// Duplicates return the existing outcome without being processed again.
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: valid})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: invalid})
+	gt.Expect(err).To(MatchError("validation failed: single-output-required"))
+	gt.Expect(validated).To(Equal(2))
+
+	checkpoint, err := order.LatestCheckpoint(context.Background())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(checkpoint.Seq).To(Equal(uint64(1)))
+}
+
+func TestNamespace_RejectsUnverifiedReceipts(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	newTransaction := func(salt string) *transaction.Transaction {
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+			Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		return tx
+	}
+
+	// A receipt known to the namespace is ordered by someone without the
+	// namespace secret.
+	forged := newTransaction("forged")
+	gt.Expect(ns.Repo.PutTransaction(forged)).To(Succeed())
+	receipt := transaction.NewReceipt(crypto.SHA256, forged.ID, nil)
+	gt.Expect(ns.Repo.PutReceipt(receipt)).To(Succeed())
+	for _, secret := range [][]byte{[]byte("ns1"), []byte("other-secret"), nil} {
+		err = order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC(secret, receipt.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	err = order.Broadcast(context.Background(), totalorder.TXIDAndHMAC{ID: receipt.ID, HMAC: make([]byte, 32)})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tx := newTransaction("tx1")
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	committed, err := ns.Repo.GetCommitted(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(4)))
+
+	_, err = ns.Repo.GetCommitted(forged.ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+}
+
+func TestNamespace_Attestation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	identity := newTestIdentity(t)
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret"), identity)
+	ns.Start()
+	defer ns.Stop()
+
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+		Salt:    []byte("tx1-0123456789abcdef0123456789abcdef"),
+		Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("tx1")}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// Commits are attested after they are committed.
+	var committed *transaction.Committed
+	gt.Eventually(func() *transaction.Attestation {
+		committed, err = ns.Repo.GetCommitted(tx.ID)
+		gt.Expect(err).NotTo(HaveOccurred())
+		return committed.Attestation
+	}).ShouldNot(BeNil())
+	checkpoint, err := order.Checkpoint(context.Background(), committed.SeqNo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	attestation := committed.Attestation
+	gt.Expect(attestation.Namespace).To(Equal("ns1"))
+	gt.Expect(attestation.SeqNo).To(Equal(committed.SeqNo))
+	gt.Expect(attestation.TxID).To(Equal(tx.ID))
+	gt.Expect(attestation.ReceiptID).To(Equal(committed.ReceiptID))
+	gt.Expect(attestation.Accumulator).To(Equal(checkpoint.Accumulator))
+	gt.Expect(attestation.Verify(&identity.PublicKey)).To(Succeed())
+}
+
+// flakyCheckpoints is a total order that fails to provide checkpoints until
+// it is repaired.
+type flakyCheckpoints struct {
+	*totalorder.InProcess
+	failed uint32
+	mutex  sync.Mutex
+	err    error
+}
+
+func (f *flakyCheckpoints) Checkpoint(ctx context.Context, seq uint64) (totalorder.Checkpoint, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.err != nil {
+		f.failed++
+		return totalorder.Checkpoint{}, f.err
+	}
+	return f.InProcess.Checkpoint(ctx, seq)
+}
+
+func (f *flakyCheckpoints) setErr(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.err = err
+}
+
+func (f *flakyCheckpoints) failures() uint32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.failed
+}
+
+func TestNamespace_AttestationRetry(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	inProcess, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+	order := &flakyCheckpoints{InProcess: inProcess, err: errors.New("checkpoints-unavailable")}
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	identity := newTestIdentity(t)
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 1, order, []byte("secret"), identity)
+	ns.attestor.retry = 10 * time.Millisecond
+	ns.Start()
+	defer ns.Stop()
+
+	var txs []*transaction.Transaction
+	for i := 0; i < 3; i++ {
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(fmt.Sprintf("tx%d-0123456789abcdef0123456789abcdef", i)),
+			Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("tx")}},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		txs = append(txs, tx)
+	}
+
+	// Transactions are committed while checkpoints are unavailable.
+	for _, tx := range txs[:2] {
+		err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	gt.Eventually(order.failures).Should(BeNumerically(">", 1))
+	gt.Expect(ns.Health().Halted).To(BeFalse())
+	for _, tx := range txs[:2] {
+		committed, err := ns.Repo.GetCommitted(tx.ID)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(committed.Attestation).To(BeNil())
+	}
+
+	// Attestation catches up once checkpoints are available.
+	order.setErr(nil)
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: txs[2]})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, tx := range txs {
+		gt.Eventually(func() *transaction.Attestation {
+			committed, err := ns.Repo.GetCommitted(tx.ID)
+			gt.Expect(err).NotTo(HaveOccurred())
+			return committed.Attestation
+		}).ShouldNot(BeNil())
+	}
+	lastAttested, err := ns.Repo.GetLastAttestedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	lastCommitted, err := ns.Repo.GetLastCommittedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(lastAttested).To(Equal(lastCommitted))
+}
+
+type flakyDeliver struct {
+	*totalorder.InProcess
+	failed uint32
+	mutex  sync.Mutex
+	err    error
+}
+
+func (f *flakyDeliver) Deliver(ctx context.Context, seq uint64) (totalorder.TXIDAndHMAC, error) {
+	f.mutex.Lock()
+	err := f.err
+	if err != nil {
+		f.failed++
+	}
+	f.mutex.Unlock()
+	if err != nil {
+		return totalorder.TXIDAndHMAC{}, err
+	}
+	return f.InProcess.Deliver(ctx, seq)
+}
+
+func (f *flakyDeliver) setErr(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.err = err
+}
+
+func (f *flakyDeliver) failures() uint32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.failed
+}
+
+func TestNamespace_DeliverRetry(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers %d", workers), func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			inProcess, cleanupOrder := newTotalOrder(t)
+			defer cleanupOrder()
+			order := &flakyDeliver{InProcess: inProcess, err: fmt.Errorf("failed to receive seq 0: %w", totalorder.ErrUnavailable)}
+
+			db, err := store.NewLevelDB("")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer tested.Close(t, db)
+
+			noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+				return &validationv1.ValidateResponse{Valid: true}, nil
+			})
+			ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, workers, order, []byte("secret"), nil)
+			ns.retry = time.Millisecond
+			ns.Start()
+			defer ns.Stop()
+
+			tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+				Salt:    []byte("tx0-0123456789abcdef0123456789abcdef"),
+				Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("tx0")}},
+			})
+			gt.Expect(err).NotTo(HaveOccurred())
+			submitC := make(chan error, 1)
+			go func() { submitC <- ns.Submit(context.Background(), &transaction.Signed{Transaction: tx}) }()
+
+			// Delivery is retried while the total order is unavailable.
+			gt.Eventually(order.failures).Should(BeNumerically(">", 2))
+			gt.Expect(ns.Health().Halted).To(BeFalse())
+			gt.Consistently(submitC).ShouldNot(Receive())
+
+			order.setErr(nil)
+			gt.Eventually(submitC).Should(Receive(BeNil()))
+			committed, err := ns.Repo.GetCommitted(tx.ID)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(committed.SeqNo).To(Equal(uint64(0)))
+
+			// Other delivery failures halt commit processing.
+			order.setErr(errors.New("stream-corrupted"))
+			halting := New("ns2", zap.NewNop(), crypto.SHA256, store.NewMemoryKV(), nil, nil, workers, order, []byte("secret"), nil)
+			halting.Start()
+			defer halting.Stop()
+			gt.Eventually(func() bool { return halting.Health().Halted }).Should(BeTrue())
+			gt.Expect(halting.Health().Seq).To(Equal(uint64(0)))
+			gt.Expect(halting.Health().Cause).To(Equal("failed to deliver ordered receipt: stream-corrupted"))
+		})
+	}
+}
+
+func TestNamespace_Resume(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	validated := 0
+	halt := true
+	v := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		if halt && validated == 1 {
+			return nil, errors.New("validator-crashed")
+		}
+		validated++
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	var txs []*transaction.Transaction
+	for _, salt := range []string{"tx0", "tx1", "tx2"} {
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+			Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		txs = append(txs, tx)
+	}
+
+	// The receipts are ordered while the namespace is down.
+	repo := store.NewRepository(db)
+	for _, tx := range txs {
+		gt.Expect(repo.PutTransaction(tx)).To(Succeed())
+		receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+		gt.Expect(repo.PutReceipt(receipt)).To(Succeed())
+		err := order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Commit processing halts at the second receipt and delivery stops.
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "halting", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	gt.Eventually(ns.doneC).Should(BeClosed())
+	ns.Stop()
+
+	committed, err := repo.GetCommitted(txs[0].ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(0)))
+	_, err = repo.GetCommitted(txs[1].ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	// Delivery resumes after the last committed transaction.
+	halt = false
+	ns = New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "halting", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	gt.Eventually(func() error { _, err := repo.GetCommitted(txs[2].ID); return err }).Should(Succeed())
+	for seq, tx := range txs {
+		committed, err := repo.GetCommitted(tx.ID)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(committed.SeqNo).To(Equal(uint64(seq)))
+	}
+	gt.Expect(validated).To(Equal(3))
+
+	// An ordered receipt for a committed transaction is skipped.
+	receipt := transaction.NewReceipt(crypto.SHA256, txs[0].ID, nil)
+	resultC, err := ns.wait(receipt.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Eventually(resultC).Should(Receive(BeNil()))
+	gt.Expect(validated).To(Equal(3))
+
+	committed, err = repo.GetCommitted(txs[0].ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(0)))
+}
+
+func TestNamespace_RestartAfterRejection(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	validated := 0
+	v := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		validated++
+		return &validationv1.ValidateResponse{Valid: false, ErrorMessage: "invalid"}, nil
+	})
+
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+		Salt:    []byte("rejected-0123456789abcdef0123456789abcdef"),
+		Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte("rejected")}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "rejecting", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+	gt.Expect(err).To(MatchError("validation failed: invalid"))
+	ns.Stop()
+
+	rejected, err := repo.GetRejected(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(validated).To(Equal(1))
+
+	// The rejected entry is not processed again after a restart.
+	ns = New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "rejecting", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	nextSeq, err := ns.NextSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(nextSeq).To(Equal(rejected.SeqNo + 1))
+
+	// A rejected transaction that is ordered again keeps its rejection.
+	ns.Start()
+	defer ns.Stop()
+	receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+	resultC, err := ns.wait(receipt.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Eventually(resultC).Should(Receive(MatchError("validation failed: invalid")))
+	gt.Expect(validated).To(Equal(1))
+
+	again, err := repo.GetRejected(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(again).To(Equal(rejected))
+}
+
+func TestNamespace_Halt(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	crashed := true
+	v := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		if crashed {
+			return nil, errors.New("validator-crashed")
+		}
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "crashing", Validator: v}}, nil, 1, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+	gt.Expect(ns.Health()).To(Equal(Health{}))
+	gt.Expect(ns.Resume()).To(MatchError(`namespace "ns1": namespace not halted`))
+
+	newSigned := func(salt string) *transaction.Signed {
+		tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+			Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+			Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		return &transaction.Signed{Transaction: tx}
+	}
+
+	// The transaction that halts processing reports the halt to its submitter.
+	tx1 := newSigned("tx1")
+	err = ns.Submit(context.Background(), tx1)
+	gt.Expect(err).To(MatchError(ErrHalt))
+
+	health := ns.Health()
+	gt.Expect(health.Halted).To(BeTrue())
+	gt.Expect(health.Seq).To(Equal(uint64(0)))
+	gt.Expect(health.Cause).To(Equal("validation failed: halt processing: validator crashing failed: validator-crashed"))
+	gt.Expect(health.HaltedAt).NotTo(BeZero())
+
+	// New submissions are rejected while the namespace is halted.
+	tx2 := newSigned("tx2")
+	err = ns.Submit(context.Background(), tx2)
+	gt.Expect(err).To(MatchError(ErrNamespaceHalted))
+	gt.Expect(err).To(MatchError("commit processing halted at seq 0: validation failed: halt processing: validator crashing failed: validator-crashed: namespace halted"))
+	_, err = ns.Repo.GetReceipt(transaction.NewReceipt(crypto.SHA256, tx2.Transaction.ID, nil).ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	// Once resumed, the receipt that halted processing is committed.
+	crashed = false
+	gt.Expect(ns.Resume()).To(Succeed())
+	gt.Expect(ns.Health()).To(Equal(Health{}))
+	gt.Eventually(func() error { _, err := ns.Repo.GetCommitted(tx1.Transaction.ID); return err }).Should(Succeed())
+
+	err = ns.Submit(context.Background(), tx2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	committed, err := ns.Repo.GetCommitted(tx2.Transaction.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(1)))
+}
+
+func TestNamespace_HaltFailsWaiters(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	ns := &Namespace{Name: "ns", Logger: zap.NewNop(), waiters: map[string][]chan error{}}
+	resultC, err := ns.wait([]byte("receipt-id"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ns.halt(3, errors.New("store-corrupted"))
+	gt.Expect(resultC).To(Receive(MatchError(ErrNamespaceHalted)))
+	gt.Expect(ns.waiters).To(BeEmpty())
+
+	// Only the first halt is recorded.
+	ns.halt(4, errors.New("other"))
+	gt.Expect(ns.Health().Seq).To(Equal(uint64(3)))
+	gt.Expect(ns.Health().Cause).To(Equal("store-corrupted"))
+
+	_, err = ns.wait([]byte("receipt-id"))
+	gt.Expect(err).To(MatchError("commit processing halted at seq 3: store-corrupted: namespace halted"))
+}
+
+func TestNamespace_Close(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	// The order never delivers so submitters wait until the namespace closes.
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, store.NewMemoryKV(), nil, nil, 1, fakeOrder{}, []byte("secret"), nil)
+	ns.Start()
+
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{Salt: []byte("tx1-0123456789abcdef0123456789abcdef")})
+	gt.Expect(err).NotTo(HaveOccurred())
+	submitC := make(chan error, 1)
+	go func() { submitC <- ns.Submit(context.Background(), &transaction.Signed{Transaction: tx}) }()
+	gt.Eventually(func() int {
+		ns.mutex.Lock()
+		defer ns.mutex.Unlock()
+		return len(ns.waiters)
+	}).Should(Equal(1))
+
+	nextC := make(chan error, 1)
+	go func() {
+		_, err := ns.Subscribe(0).Next(context.Background())
+		nextC <- err
+	}()
+
+	// An acquired request keeps the database open until it is released.
+	gt.Expect(ns.Acquire()).To(Succeed())
+	closeC := make(chan error, 1)
+	go func() { closeC <- ns.Close() }()
+
+	gt.Eventually(submitC).Should(Receive(MatchError(ErrNamespaceClosed)))
+	gt.Eventually(nextC).Should(Receive(MatchError(ErrNamespaceClosed)))
+	gt.Consistently(closeC).ShouldNot(Receive())
+	_, err = ns.KV.Get([]byte("key"))
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	// New requests are refused while the namespace is closing.
+	gt.Expect(ns.Acquire()).To(MatchError(`namespace "ns1": namespace closed`))
+	_, err = ns.Status(tx.ID)
+	gt.Expect(err).To(MatchError(ErrNamespaceClosed))
+	_, err = ns.Simulate(&transaction.Signed{Transaction: tx})
+	gt.Expect(err).To(MatchError(ErrNamespaceClosed))
+	err = ns.Submit(context.Background(), &transaction.Signed{Transaction: tx})
+	gt.Expect(err).To(MatchError(ErrNamespaceClosed))
+
+	ns.Release()
+	gt.Eventually(closeC).Should(Receive(BeNil()))
+	_, err = ns.KV.Get([]byte("key"))
+	gt.Expect(err).To(MatchError("memory: closed"))
+
+	gt.Expect(ns.Close()).To(MatchError(ErrNamespaceClosed))
+	ns.Start()
+	gt.Expect(ns.Started()).To(BeFalse())
+}
+
+type fakeOrder struct {
+	broadcastErr error
+}
+
+func (f fakeOrder) Broadcast(context.Context, totalorder.TXIDAndHMAC) error {
+	return f.broadcastErr
+}
+
+func (f fakeOrder) Deliver(ctx context.Context, seq uint64) (totalorder.TXIDAndHMAC, error) {
+	<-ctx.Done()
+	return totalorder.TXIDAndHMAC{}, ctx.Err()
 }
@@ -0,0 +1,221 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// A pipelineItem tracks an ordered receipt while its transaction is resolved
+// and validated ahead of commit.
+type pipelineItem struct {
+	seq       uint64
+	receiptID []byte
+	version   uint64 // version is the number of commits applied before validation started
+
+	validation *validation
+	err        error
+	doneC      chan struct{}
+}
+
+// A commitRecord identifies the transaction and the inputs of a commit
+// applied by the pipeline.
+type commitRecord struct {
+	version uint64
+	keys    []string
+}
+
+// A pipeline resolves and validates transactions concurrently and applies
+// their commits in sequence order.
+//
+// Transactions are validated speculatively against the state that exists
+// when a worker picks them up. Before a transaction is committed, the
+// pipeline checks whether any commit applied after validation started
+// consumed one of its inputs or references or committed or rejected the
+// same transaction. When it did, or when speculative resolution failed, the
+// transaction is validated again against the current state.
+type pipeline struct {
+	committer *committer
+	workers   int
+
+	version uint64 // version is accessed atomically
+	history []commitRecord
+}
+
+func newPipeline(c *committer, workers int) *pipeline {
+	return &pipeline{
+		committer: c,
+		workers:   workers,
+	}
+}
+
+// deliverParallel retrieves ordered entries from the total order, starting
+// at the provided sequence number, and runs them through a pipeline. Items
+// that are queued when processing halts are discarded.
+func (ns *Namespace) deliverParallel(ctx context.Context, start uint64) {
+	ctx, cancel := context.WithCancel(ctx)
+	p := newPipeline(ns.committer, ns.workers)
+
+	itemsC := make(chan *pipelineItem, p.workers)
+	workC := make(chan *pipelineItem, p.workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range workC {
+				item.validation, item.err = p.committer.validate(item.receiptID)
+				close(item.doneC)
+			}
+		}()
+	}
+	defer func() {
+		cancel()
+		for range itemsC {
+		}
+		wg.Wait()
+	}()
+
+	go func() {
+		defer close(itemsC)
+		defer close(workC)
+		for seq := start; ; seq++ {
+			tah, err := ns.deliverSeq(ctx, seq)
+			if err != nil {
+				if ctx.Err() == nil {
+					ns.halt(seq, errors.WithMessage(err, "failed to deliver ordered receipt"))
+					// The items that are queued are not committed once
+					// processing has halted.
+					cancel()
+				}
+				return
+			}
+
+			if !tah.Verify(ns.secret) {
+				ns.Logger.Debug("skipping ordered receipt with unverified hmac", zap.Uint64("seq", seq))
+				continue
+			}
+
+			item := &pipelineItem{
+				seq:       seq,
+				receiptID: tah.ID,
+				version:   atomic.LoadUint64(&p.version),
+				doneC:     make(chan struct{}),
+			}
+			select {
+			case itemsC <- item:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case workC <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for item := range itemsC {
+		select {
+		case <-item.doneC:
+		case <-ctx.Done():
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := p.apply(item)
+		ns.notify(item.receiptID, err)
+		if errors.Is(err, ErrHalt) {
+			ns.halt(item.seq, err)
+			return
+		}
+	}
+}
+
+// apply commits the transaction of a pipeline item. Items must be applied in
+// sequence order.
+func (p *pipeline) apply(item *pipelineItem) error {
+	p.prune(item.version)
+
+	v, err := item.validation, item.err
+	if err != nil || v.stateErr != nil || p.conflicts(item.version, v) {
+		v, err = p.committer.validate(item.receiptID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.committer.apply(v, item.seq)
+	if !v.processed && !errors.Is(err, ErrHalt) {
+		p.record(v)
+	}
+	return err
+}
+
+// conflicts returns true when a commit applied after the provided version
+// committed or rejected the same transaction or consumed one of its inputs
+// or references.
+func (p *pipeline) conflicts(version uint64, v *validation) bool {
+	if v.processed || len(p.history) == 0 {
+		return false
+	}
+
+	keys := map[string]struct{}{txKey(v.tx.ID): {}}
+	for _, input := range v.tx.Inputs {
+		keys[stateKey(*input)] = struct{}{}
+	}
+	for _, ref := range v.tx.References {
+		keys[stateKey(*ref)] = struct{}{}
+	}
+
+	for _, record := range p.history {
+		if record.version <= version {
+			continue
+		}
+		for _, key := range record.keys {
+			if _, ok := keys[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// record adds a commit or rejection to the history and advances the
+// version. Only commits consume inputs.
+func (p *pipeline) record(v *validation) {
+	keys := []string{txKey(v.tx.ID)}
+	if v.stateErr == nil && v.resp.Valid {
+		for _, input := range v.inputs() {
+			keys = append(keys, stateKey(input))
+		}
+	}
+
+	version := atomic.AddUint64(&p.version, 1)
+	p.history = append(p.history, commitRecord{version: version, keys: keys})
+}
+
+// prune drops the history that can not conflict with items validated at or
+// after the provided version. Items are dispatched in sequence order so
+// their versions never decrease.
+func (p *pipeline) prune(version uint64) {
+	i := 0
+	for i < len(p.history) && p.history[i].version <= version {
+		i++
+	}
+	p.history = p.history[i:]
+}
+
+func txKey(id transaction.ID) string         { return "tx:" + string(id) }
+func stateKey(id transaction.StateID) string { return id.String() }
@@ -0,0 +1,336 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/totalorder"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+func TestPipelineApply(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	var mutex sync.Mutex
+	validated := map[string]int{}
+	v := validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		mutex.Lock()
+		validated[string(req.ResolvedTransaction.Txid)]++
+		mutex.Unlock()
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+	p := newPipeline(newCommitter(repo, ValidatorChain{{Name: "counting", Validator: v}}, nil), 4)
+
+	existing := []*transaction.State{
+		{ID: transaction.StateID{TxID: transaction.ID("existing"), OutputIndex: 0}, StateInfo: &transaction.StateInfo{Kind: "kind"}, Data: []byte("s0")},
+		{ID: transaction.StateID{TxID: transaction.ID("existing"), OutputIndex: 1}, StateInfo: &transaction.StateInfo{Kind: "kind"}, Data: []byte("s1")},
+	}
+	for _, s := range existing {
+		gt.Expect(repo.PutState(s)).To(Succeed())
+	}
+
+	spend := newPipelineTx(t, repo, "spend", existing[0].ID)
+	doubleSpend := newPipelineTx(t, repo, "double-spend", existing[0].ID)
+	independent := newPipelineTx(t, repo, "independent", existing[1].ID)
+
+	// All transactions are validated before any are committed.
+	items := []*pipelineItem{
+		newPipelineItem(p, 0, spend),
+		newPipelineItem(p, 1, doubleSpend),
+		newPipelineItem(p, 2, independent),
+		newPipelineItem(p, 3, spend),
+	}
+	for _, item := range items {
+		gt.Expect(item.err).NotTo(HaveOccurred())
+		gt.Expect(item.validation.resp.Valid).To(BeTrue())
+	}
+
+	gt.Expect(p.apply(items[0])).To(Succeed())
+
+	// The double spend conflicts with the earlier commit and is revalidated
+	// against the current state.
+	err = p.apply(items[1])
+	gt.Expect(err).To(MatchError(ErrStateConsumed))
+	var stateErr *StateError
+	gt.Expect(errors.As(err, &stateErr)).To(BeTrue())
+	gt.Expect(stateErr.ConsumedBy).To(Equal(spend.ID))
+
+	// Transactions that do not conflict are not validated again.
+	gt.Expect(p.apply(items[2])).To(Succeed())
+
+	// The duplicate is revalidated and skipped because it has been committed.
+	gt.Expect(p.apply(items[3])).To(Succeed())
+
+	committed, err := repo.GetCommitted(spend.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(0)))
+	committed, err = repo.GetCommitted(independent.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.SeqNo).To(Equal(uint64(2)))
+	_, err = repo.GetCommitted(doubleSpend.ID)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	gt.Expect(validated).To(Equal(map[string]int{
+		string(spend.ID):       2,
+		string(doubleSpend.ID): 1,
+		string(independent.ID): 1,
+	}))
+
+	// Commits and rejections are recorded; history that precedes the
+	// version of later items is pruned.
+	gt.Expect(p.history).To(HaveLen(3))
+	p.prune(3)
+	gt.Expect(p.history).To(BeEmpty())
+}
+
+func newPipelineTx(t testing.TB, repo *store.TransactionRepository, salt string, inputs ...transaction.StateID) *transaction.Transaction {
+	gt := NewGomegaWithT(t)
+
+	var refs []*txv1.StateReference
+	for _, input := range inputs {
+		refs = append(refs, &txv1.StateReference{Txid: input.TxID, OutputIndex: input.OutputIndex})
+	}
+	tx, err := transaction.New(crypto.SHA256, &txv1.Transaction{
+		Salt:    []byte(salt + "-0123456789abcdef0123456789abcdef"),
+		Inputs:  refs,
+		Outputs: []*txv1.State{{Info: &txv1.StateInfo{Kind: "kind"}, State: []byte(salt)}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(repo.PutTransaction(tx)).To(Succeed())
+	gt.Expect(repo.PutReceipt(transaction.NewReceipt(crypto.SHA256, tx.ID, nil))).To(Succeed())
+	return tx
+}
+
+func newPipelineItem(p *pipeline, seq uint64, tx *transaction.Transaction) *pipelineItem {
+	item := &pipelineItem{
+		seq:       seq,
+		receiptID: transaction.NewReceipt(crypto.SHA256, tx.ID, nil).ID,
+		version:   p.version,
+	}
+	item.validation, item.err = p.committer.validate(item.receiptID)
+	return item
+}
+
+func TestNamespace_ParallelCommit(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	order, cleanupOrder := newTotalOrder(t)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	noopValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	// Each transaction spends the output of the transaction before it so
+	// every transaction depends on the commit of its predecessor.
+	var txs []*transaction.Transaction
+	var inputs []transaction.StateID
+	for i := 0; i < 20; i++ {
+		tx := newPipelineTx(t, repo, fmt.Sprintf("chain-%d", i), inputs...)
+		txs = append(txs, tx)
+		inputs = []transaction.StateID{tx.Outputs[0].ID}
+
+		receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+		err := order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	// A transaction that spends an output that was already spent.
+	doubleSpend := newPipelineTx(t, repo, "double-spend", txs[10].Outputs[0].ID)
+	receipt := transaction.NewReceipt(crypto.SHA256, doubleSpend.ID, nil)
+	err = order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "noop", Validator: noopValidator}}, nil, 4, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	gt.Eventually(func() error { _, err := repo.GetRejected(doubleSpend.ID); return err }).Should(Succeed())
+	for seq, tx := range txs {
+		committed, err := repo.GetCommitted(tx.ID)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(committed.SeqNo).To(Equal(uint64(seq)))
+	}
+	rejected, err := repo.GetRejected(doubleSpend.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(rejected.SeqNo).To(Equal(uint64(len(txs))))
+	gt.Expect(rejected.Reason).To(ContainSubstring("state consumed"))
+
+	// The rejected entry is the last entry that was processed.
+	lastSeq, err := repo.GetLastCommittedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(lastSeq).To(Equal(uint64(len(txs))))
+}
+
+func TestNamespace_ParallelHalt(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	order := &haltingOrder{failedC: make(chan struct{}), resumeC: make(chan struct{})}
+	var txs []*transaction.Transaction
+	for i := 0; i < 3; i++ {
+		tx := newPipelineTx(t, repo, fmt.Sprintf("halt-%d", i))
+		txs = append(txs, tx)
+		receipt := transaction.NewReceipt(crypto.SHA256, tx.ID, nil)
+		order.entries = append(order.entries, totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+	}
+
+	releaseC := make(chan struct{})
+	blockingValidator := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		<-releaseC
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "blocking", Validator: blockingValidator}}, nil, 4, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	// Delivery fails while the queued entries are being validated.
+	gt.Eventually(func() bool { return ns.Health().Halted }).Should(BeTrue())
+	gt.Expect(ns.Health().Seq).To(Equal(uint64(3)))
+
+	// Resume waits for the pipeline without holding the namespace lock.
+	resumedC := make(chan error, 1)
+	go func() { resumedC <- ns.Resume() }()
+	gt.Consistently(resumedC, 50*time.Millisecond).ShouldNot(Receive())
+	gt.Expect(ns.Health().Halted).To(BeTrue())
+
+	close(releaseC)
+	gt.Eventually(resumedC).Should(Receive(BeNil()))
+
+	// The entries that were queued when processing halted were discarded.
+	_, err = repo.GetLastCommittedSeq()
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	// Once resumed, the entries are delivered and committed again.
+	close(order.resumeC)
+	gt.Eventually(func() error { _, err := repo.GetCommitted(txs[2].ID); return err }).Should(Succeed())
+	for seq, tx := range txs {
+		committed, err := repo.GetCommitted(tx.ID)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(committed.SeqNo).To(Equal(uint64(seq)))
+	}
+}
+
+// haltingOrder delivers its entries and fails the first delivery past the
+// last entry. Deliveries that follow the failure wait for resumeC to close.
+type haltingOrder struct {
+	entries []totalorder.TXIDAndHMAC
+	failedC chan struct{}
+	resumeC chan struct{}
+	once    sync.Once
+}
+
+func (h *haltingOrder) Broadcast(context.Context, totalorder.TXIDAndHMAC) error {
+	return nil
+}
+
+func (h *haltingOrder) Deliver(ctx context.Context, seq uint64) (totalorder.TXIDAndHMAC, error) {
+	select {
+	case <-h.failedC:
+		select {
+		case <-h.resumeC:
+		case <-ctx.Done():
+			return totalorder.TXIDAndHMAC{}, ctx.Err()
+		}
+	default:
+	}
+
+	if seq < uint64(len(h.entries)) {
+		return h.entries[seq], nil
+	}
+
+	failed := false
+	h.once.Do(func() {
+		close(h.failedC)
+		failed = true
+	})
+	if failed {
+		return totalorder.TXIDAndHMAC{}, errors.New("deliver-failed")
+	}
+	<-ctx.Done()
+	return totalorder.TXIDAndHMAC{}, ctx.Err()
+}
+
+// busyValidator approximates a CPU heavy validator.
+func busyValidator(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+	sum := sha256.Sum256([]byte("busy"))
+	for i := 0; i < 5000; i++ {
+		sum = sha256.Sum256(sum[:])
+	}
+	return &validationv1.ValidateResponse{Valid: true}, nil
+}
+
+func BenchmarkCommit(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			benchmarkCommit(b, workers)
+		})
+	}
+}
+
+func benchmarkCommit(b *testing.B, workers int) {
+	gt := NewGomegaWithT(b)
+
+	order, cleanupOrder := newTotalOrder(b)
+	defer cleanupOrder()
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(b, db)
+	repo := store.NewRepository(db)
+
+	var last *transaction.Transaction
+	for i := 0; i < b.N; i++ {
+		last = newPipelineTx(b, repo, fmt.Sprintf("bench-%d", i))
+		receipt := transaction.NewReceipt(crypto.SHA256, last.ID, nil)
+		err := order.Broadcast(context.Background(), totalorder.NewTXIDAndHMAC([]byte("secret"), receipt.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	_, err = order.Deliver(context.Background(), uint64(b.N-1))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	b.ResetTimer()
+	ns := New("ns1", zap.NewNop(), crypto.SHA256, db, ValidatorChain{{Name: "busy", Validator: validatorFunc(busyValidator)}}, nil, workers, order, []byte("secret"), nil)
+	ns.Start()
+	defer ns.Stop()
+
+	for {
+		if _, err := repo.GetCommitted(last.ID); err == nil {
+			break
+		}
+		runtime.Gosched()
+	}
+	b.StopTimer()
+}
@@ -0,0 +1,78 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A Registry tracks the active namespaces by name. It is safe for concurrent
+// use so namespaces can be added and removed while requests are routed to
+// them.
+type Registry struct {
+	mutex      sync.RWMutex
+	namespaces map[string]*Namespace
+}
+
+// NewRegistry creates a Registry that contains the provided namespaces.
+func NewRegistry(namespaces map[string]*Namespace) *Registry {
+	r := &Registry{namespaces: map[string]*Namespace{}}
+	for name, ns := range namespaces {
+		r.namespaces[name] = ns
+	}
+	return r
+}
+
+// Lookup returns the namespace with the provided name.
+func (r *Registry) Lookup(name string) (*Namespace, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ns, ok := r.namespaces[name]
+	return ns, ok
+}
+
+// Add adds a namespace to the registry. An error matching ErrNamespaceExists
+// is returned when a namespace with the same name is already registered.
+func (r *Registry) Add(ns *Namespace) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.namespaces[ns.Name]; ok {
+		return errors.WithMessagef(ErrNamespaceExists, "namespace %q", ns.Name)
+	}
+	r.namespaces[ns.Name] = ns
+	return nil
+}
+
+// Remove removes a namespace from the registry and returns it. An error
+// matching ErrNamespaceNotFound is returned when the namespace is not
+// registered.
+func (r *Registry) Remove(name string) (*Namespace, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ns, ok := r.namespaces[name]
+	if !ok {
+		return nil, errors.WithMessagef(ErrNamespaceNotFound, "namespace %q", name)
+	}
+	delete(r.namespaces, name)
+	return ns, nil
+}
+
+// Names returns the sorted names of the registered namespaces.
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var names []string
+	for name := range r.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,42 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRegistry(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	ns1 := &Namespace{Name: "ns1"}
+	ns2 := &Namespace{Name: "ns2"}
+	r := NewRegistry(map[string]*Namespace{"ns1": ns1})
+
+	ns, ok := r.Lookup("ns1")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(ns).To(BeIdenticalTo(ns1))
+	_, ok = r.Lookup("ns2")
+	gt.Expect(ok).To(BeFalse())
+
+	gt.Expect(r.Add(ns2)).To(Succeed())
+	gt.Expect(r.Names()).To(Equal([]string{"ns1", "ns2"}))
+
+	err := r.Add(&Namespace{Name: "ns2"})
+	gt.Expect(err).To(MatchError(ErrNamespaceExists))
+	gt.Expect(err).To(MatchError(`namespace "ns2": namespace exists`))
+	ns, _ = r.Lookup("ns2")
+	gt.Expect(ns).To(BeIdenticalTo(ns2))
+
+	ns, err = r.Remove("ns1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ns).To(BeIdenticalTo(ns1))
+	gt.Expect(r.Names()).To(Equal([]string{"ns2"}))
+
+	_, err = r.Remove("ns1")
+	gt.Expect(err).To(MatchError(ErrNamespaceNotFound))
+	gt.Expect(err).To(MatchError(`namespace "ns1": namespace not found`))
+}
@@ -0,0 +1,70 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"github.com/pkg/errors"
+
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// A Simulation is the outcome of resolving and validating a transaction
+// against the current state without committing it.
+type Simulation struct {
+	// Resolved contains the transaction with the inputs and references that
+	// could be resolved.
+	Resolved *transaction.Resolved
+	// Missing identifies the inputs and references that have been consumed
+	// or that do not exist.
+	Missing []*StateError
+	// Response is the response from the validator. The validator is only
+	// invoked when every input and reference has been resolved so Response
+	// is nil when states are missing.
+	Response *validationv1.ValidateResponse
+}
+
+// Simulate resolves and validates a transaction against the current state
+// in the same way it would be during commit processing. Nothing is written
+// to the repository and the transaction is not ordered.
+func (ns *Namespace) Simulate(signed *transaction.Signed) (*Simulation, error) {
+	if err := ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer ns.Release()
+
+	return ns.committer.simulate(signed.Transaction, signed.Signatures)
+}
+
+func (c *committer) simulate(tx *transaction.Transaction, sigs []*transaction.Signature) (*Simulation, error) {
+	view, err := c.repo.NewView()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "state resolution for transaction %s failed", tx.ID)
+	}
+	resolved, missing, err := resolveAll(view, tx, sigs)
+	view.Release()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "state resolution for transaction %s failed", tx.ID)
+	}
+
+	sim := &Simulation{Resolved: resolved}
+	for _, m := range missing {
+		stateErr, ok := NewStateError(m).(*StateError)
+		if !ok {
+			return nil, errors.WithMessagef(m, "state resolution for transaction %s failed", tx.ID)
+		}
+		sim.Missing = append(sim.Missing, stateErr)
+	}
+	if len(sim.Missing) != 0 {
+		return sim, nil
+	}
+
+	sim.Response, err = c.kinds.chain(resolved, c.validators).Validate(&validationv1.ValidateRequest{
+		ResolvedTransaction: transaction.FromResolved(resolved),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
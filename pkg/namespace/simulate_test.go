@@ -0,0 +1,122 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+func TestNamespace_Simulate(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+	repo := store.NewRepository(db)
+
+	existing := &transaction.State{
+		ID:        transaction.StateID{TxID: transaction.ID("existing"), OutputIndex: 0},
+		StateInfo: &transaction.StateInfo{Kind: "kind"},
+		Data:      []byte("existing"),
+	}
+	gt.Expect(repo.PutState(existing)).To(Succeed())
+
+	spent := newPipelineTx(t, repo, "spent")
+	gt.Expect(repo.CommitTransaction(spent.ID, &transaction.Committed{SeqNo: 0}, spent.Outputs, nil)).To(Succeed())
+	spender := newPipelineTx(t, repo, "spender", spent.Outputs[0].ID)
+	gt.Expect(repo.CommitTransaction(spender.ID, &transaction.Committed{SeqNo: 1}, spender.Outputs, []transaction.StateID{spent.Outputs[0].ID})).To(Succeed())
+
+	unknown := transaction.StateID{TxID: transaction.ID("unknown"), OutputIndex: 3}
+
+	var validated int
+	v := validatorFunc(func(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		validated++
+		if string(req.ResolvedTransaction.Outputs[0].State) == "invalid" {
+			return &validationv1.ValidateResponse{Valid: false, ErrorMessage: "invalid-state"}, nil
+		}
+		if string(req.ResolvedTransaction.Outputs[0].State) == "fail" {
+			return nil, errors.New("validator-error")
+		}
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+	ns := &Namespace{Repo: repo, committer: newCommitter(repo, ValidatorChain{{Name: "test", Validator: v}}, nil)}
+
+	newSigned := func(salt string, inputs ...transaction.StateID) *transaction.Signed {
+		return &transaction.Signed{Transaction: newPipelineTx(t, repo, salt, inputs...)}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+		validated = 0
+
+		signed := newSigned("valid", existing.ID)
+		sim, err := ns.Simulate(signed)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(sim.Missing).To(BeEmpty())
+		gt.Expect(sim.Response).To(Equal(&validationv1.ValidateResponse{Valid: true}))
+		gt.Expect(sim.Resolved.ID).To(Equal(signed.Transaction.ID))
+		gt.Expect(sim.Resolved.Inputs).To(Equal([]*transaction.State{existing}))
+		gt.Expect(validated).To(Equal(1))
+
+		// Nothing is committed or consumed.
+		_, err = repo.GetCommitted(signed.Transaction.ID)
+		gt.Expect(store.IsNotFound(err)).To(BeTrue())
+		_, err = repo.GetState(existing.ID, false)
+		gt.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		sim, err := ns.Simulate(newSigned("invalid", existing.ID))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(sim.Missing).To(BeEmpty())
+		gt.Expect(sim.Response).To(Equal(&validationv1.ValidateResponse{Valid: false, ErrorMessage: "invalid-state"}))
+	})
+
+	t.Run("MissingStates", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+		validated = 0
+
+		sim, err := ns.Simulate(newSigned("missing", existing.ID, spent.Outputs[0].ID, unknown))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(sim.Response).To(BeNil())
+		gt.Expect(sim.Resolved.Inputs).To(Equal([]*transaction.State{existing}))
+		gt.Expect(validated).To(Equal(0))
+
+		gt.Expect(sim.Missing).To(HaveLen(2))
+		gt.Expect(sim.Missing[0]).To(MatchError(ErrStateConsumed))
+		gt.Expect(sim.Missing[0].StateID).To(Equal(spent.Outputs[0].ID))
+		gt.Expect(sim.Missing[0].ConsumedBy).To(Equal(spender.ID))
+		gt.Expect(sim.Missing[1]).To(MatchError(ErrStateUnknown))
+		gt.Expect(sim.Missing[1].StateID).To(Equal(unknown))
+	})
+
+	t.Run("ValidatorFailure", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		_, err := ns.Simulate(newSigned("fail", existing.ID))
+		gt.Expect(err).To(MatchError("validator test failed: validator-error"))
+	})
+
+	t.Run("StoreFailure", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		fakeRepo := newFakeRepository()
+		fakeRepo.GetStateReturns(nil, errors.New("get-state-error"))
+		ns := &Namespace{committer: newCommitter(fakeRepo, ValidatorChain{{Name: "test", Validator: v}}, nil)}
+
+		signed := newSigned("store", existing.ID)
+		_, err := ns.Simulate(signed)
+		gt.Expect(err).To(MatchError("state resolution for transaction " + signed.Transaction.ID.String() + " failed: get-state-error"))
+	})
+}
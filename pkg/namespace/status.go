@@ -0,0 +1,72 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// A TxState identifies how far a transaction has progressed through commit
+// processing.
+type TxState int
+
+const (
+	// TxUnknown indicates the transaction has not been submitted.
+	TxUnknown TxState = iota
+	// TxPending indicates the transaction has been submitted but has not been
+	// committed or rejected.
+	TxPending
+	// TxCommitted indicates the transaction has been committed.
+	TxCommitted
+	// TxRejected indicates the transaction failed commit processing.
+	TxRejected
+)
+
+// TxStatus is the commit processing status of a transaction. Committed is set
+// for committed transactions and Rejected is set for rejected transactions.
+type TxStatus struct {
+	State     TxState
+	Committed *transaction.Committed
+	Rejected  *transaction.Rejected
+}
+
+// Status returns the commit processing status of a transaction.
+func (ns *Namespace) Status(txID transaction.ID) (*TxStatus, error) {
+	if err := ns.Acquire(); err != nil {
+		return nil, err
+	}
+	defer ns.Release()
+
+	return txStatus(ns.Repo, txID)
+}
+
+func txStatus(repo Repository, txID transaction.ID) (*TxStatus, error) {
+	committed, err := repo.GetCommitted(txID)
+	if err == nil {
+		return &TxStatus{State: TxCommitted, Committed: committed}, nil
+	}
+	if !store.IsNotFound(err) {
+		return nil, errors.WithMessagef(err, "failed to get commit status of transaction %s", txID)
+	}
+
+	rejected, err := repo.GetRejected(txID)
+	if err == nil {
+		return &TxStatus{State: TxRejected, Rejected: rejected}, nil
+	}
+	if !store.IsNotFound(err) {
+		return nil, errors.WithMessagef(err, "failed to get rejection status of transaction %s", txID)
+	}
+
+	_, err = repo.GetTransaction(txID)
+	if store.IsNotFound(err) {
+		return &TxStatus{State: TxUnknown}, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get transaction %s", txID)
+	}
+	return &TxStatus{State: TxPending}, nil
+}
@@ -3,13 +3,21 @@
 
 package namespace
 
-import validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/transaction"
+)
 
 // A Validator is responsible for validating resolved transactions.
 //
 // Implementations must be iplemented as pure functions and any
 // parameterization should be statically defined within the validator or be
-// derived from the contents of the resolved transactions.
+// derived from the contents of the resolved transactions. Transactions are
+// validated concurrently so implementations must be safe for concurrent use.
 type Validator interface {
 	// Validate is used to ensure that a transaction is semantically valid prior
 	// to committing it to the ledger. This operation is only invoked if a
@@ -24,3 +32,108 @@ type Validator interface {
 	// If an error is returned, transaction processing is halted.
 	Validate(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error)
 }
+
+// A NamedValidator associates a Validator with its configured name.
+type NamedValidator struct {
+	Name      string
+	Validator Validator
+}
+
+// A ValidatorChain is an ordered list of validators that must all accept a
+// transaction for it to be valid. Every validator in the chain is run, even
+// after one has rejected the transaction, so the reasons from all of the
+// validators that rejected it are reported.
+type ValidatorChain []NamedValidator
+
+// Names returns the names of the validators in the chain in order.
+func (vc ValidatorChain) Names() []string {
+	var names []string
+	for _, v := range vc {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// Validate runs each validator in the chain and aggregates their responses.
+// When more than one validator is in the chain, the error message of each
+// validator that rejected the transaction is prefixed with its name.
+func (vc ValidatorChain) Validate(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+	resp, _, err := vc.validate(req)
+	return resp, err
+}
+
+// validate runs each validator in the chain and returns the aggregated
+// response along with the names of the validators that rejected the
+// transaction.
+func (vc ValidatorChain) validate(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, []string, error) {
+	var rejectedBy, messages []string
+	for _, v := range vc {
+		resp, err := v.Validator.Validate(req)
+		if err != nil {
+			return nil, nil, errors.WithMessagef(err, "validator %s failed", v.Name)
+		}
+		if resp.Valid {
+			continue
+		}
+
+		rejectedBy = append(rejectedBy, v.Name)
+		switch {
+		case len(vc) == 1:
+			messages = append(messages, resp.ErrorMessage)
+		case resp.ErrorMessage == "":
+			messages = append(messages, v.Name+": transaction is not valid")
+		default:
+			messages = append(messages, v.Name+": "+resp.ErrorMessage)
+		}
+	}
+
+	if len(rejectedBy) == 0 {
+		return &validationv1.ValidateResponse{Valid: true}, nil, nil
+	}
+	return &validationv1.ValidateResponse{
+		Valid:        false,
+		ErrorMessage: strings.Join(messages, "; "),
+	}, rejectedBy, nil
+}
+
+// KindValidators maps state kinds to the validators responsible for them.
+type KindValidators map[string]ValidatorChain
+
+// chain returns the validators for a resolved transaction. The transaction
+// is validated by the validators of every kind among its inputs, references,
+// and outputs. The fallback validators are used for kinds that are not
+// mapped and for transactions without states. Validators registered for more
+// than one kind are only run once.
+func (kv KindValidators) chain(resolved *transaction.Resolved, fallback ValidatorChain) ValidatorChain {
+	if len(kv) == 0 {
+		return fallback
+	}
+
+	var chain ValidatorChain
+	selected := map[string]bool{}
+	add := func(validators ValidatorChain) {
+		for _, v := range validators {
+			if !selected[v.Name] {
+				selected[v.Name] = true
+				chain = append(chain, v)
+			}
+		}
+	}
+
+	states := append(append(append([]*transaction.State(nil), resolved.Inputs...), resolved.References...), resolved.Outputs...)
+	if len(states) == 0 {
+		return fallback
+	}
+	for _, state := range states {
+		var kind string
+		if state.StateInfo != nil {
+			kind = state.StateInfo.Kind
+		}
+		validators, ok := kv[kind]
+		if !ok {
+			validators = fallback
+		}
+		add(validators)
+	}
+	return chain
+}
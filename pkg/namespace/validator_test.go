@@ -0,0 +1,147 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package namespace
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	validationv1 "github.com/sykesm/batik/pkg/pb/validation/v1"
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+func TestValidatorChain(t *testing.T) {
+	accept := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return &validationv1.ValidateResponse{Valid: true}, nil
+	})
+	reject := func(msg string) Validator {
+		return validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+			return &validationv1.ValidateResponse{Valid: false, ErrorMessage: msg}, nil
+		})
+	}
+	fail := validatorFunc(func(*validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := map[string]struct {
+		chain      ValidatorChain
+		expected   *validationv1.ValidateResponse
+		rejectedBy []string
+		err        string
+	}{
+		"all accept": {
+			chain:    ValidatorChain{{"first", accept}, {"second", accept}},
+			expected: &validationv1.ValidateResponse{Valid: true},
+		},
+		"single rejection": {
+			chain:      ValidatorChain{{"only", reject("bad-signature")}},
+			expected:   &validationv1.ValidateResponse{Valid: false, ErrorMessage: "bad-signature"},
+			rejectedBy: []string{"only"},
+		},
+		"one of many rejects": {
+			chain:      ValidatorChain{{"first", accept}, {"second", reject("bad-rule")}},
+			expected:   &validationv1.ValidateResponse{Valid: false, ErrorMessage: "second: bad-rule"},
+			rejectedBy: []string{"second"},
+		},
+		"many reject": {
+			chain:      ValidatorChain{{"first", reject("bad-signature")}, {"second", accept}, {"third", reject("")}},
+			expected:   &validationv1.ValidateResponse{Valid: false, ErrorMessage: "first: bad-signature; third: transaction is not valid"},
+			rejectedBy: []string{"first", "third"},
+		},
+		"validator error": {
+			chain: ValidatorChain{{"first", reject("bad-signature")}, {"second", fail}, {"third", accept}},
+			err:   "validator second failed: boom",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			resp, rejectedBy, err := tt.chain.validate(&validationv1.ValidateRequest{})
+			if tt.err != "" {
+				gt.Expect(err).To(MatchError(tt.err))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(resp).To(Equal(tt.expected))
+			gt.Expect(rejectedBy).To(Equal(tt.rejectedBy))
+
+			resp, err = tt.chain.Validate(&validationv1.ValidateRequest{})
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(resp).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestValidatorChain_Names(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	chain := ValidatorChain{{Name: "first"}, {Name: "second"}}
+	gt.Expect(chain.Names()).To(Equal([]string{"first", "second"}))
+	gt.Expect(ValidatorChain{}.Names()).To(BeNil())
+}
+
+func TestKindValidators_Chain(t *testing.T) {
+	named := func(names ...string) ValidatorChain {
+		var chain ValidatorChain
+		for _, name := range names {
+			chain = append(chain, NamedValidator{Name: name})
+		}
+		return chain
+	}
+	state := func(kind string) *transaction.State {
+		return &transaction.State{StateInfo: &transaction.StateInfo{Kind: kind}}
+	}
+
+	kinds := KindValidators{
+		"token": named("signature", "token-rules"),
+		"bond":  named("signature", "bond-rules"),
+	}
+	fallback := named("signature")
+
+	tests := map[string]struct {
+		kinds    KindValidators
+		resolved *transaction.Resolved
+		expected []string
+	}{
+		"no kinds": {
+			resolved: &transaction.Resolved{Outputs: []*transaction.State{state("token")}},
+			expected: []string{"signature"},
+		},
+		"no states": {
+			kinds:    kinds,
+			resolved: &transaction.Resolved{},
+			expected: []string{"signature"},
+		},
+		"single kind": {
+			kinds:    kinds,
+			resolved: &transaction.Resolved{Inputs: []*transaction.State{state("token")}, Outputs: []*transaction.State{state("token")}},
+			expected: []string{"signature", "token-rules"},
+		},
+		"mixed kinds": {
+			kinds: kinds,
+			resolved: &transaction.Resolved{
+				Inputs:     []*transaction.State{state("bond")},
+				References: []*transaction.State{state("token")},
+				Outputs:    []*transaction.State{state("bond")},
+			},
+			expected: []string{"signature", "bond-rules", "token-rules"},
+		},
+		"unknown kind": {
+			kinds:    KindValidators{"token": named("token-rules")},
+			resolved: &transaction.Resolved{Outputs: []*transaction.State{state("token"), state("other"), {}}},
+			expected: []string{"token-rules", "signature"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			gt.Expect(tt.kinds.chain(tt.resolved, fallback).Names()).To(Equal(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+
+	"github.com/pkg/errors"
+	cli "github.com/urfave/cli/v2"
+)
+
+// AdminServer exposes configuration for the gRPC server that hosts the
+// administrative API. The administrative API is served on its own listener
+// so it is not reachable through the public gRPC listener.
+type AdminServer struct {
+	// ListenAddress determines the address that the admin server will listen
+	// on. The address should be in a form that is compatible with net.Listen
+	// from the Go standard library. The default is a loopback address so the
+	// admin API is only reachable from the local host; other addresses require
+	// client certificate authentication.
+	ListenAddress string `yaml:"listen_address,omitempty"`
+	// ClientCAFile is the name of a file containing the PEM encoded
+	// certificates of the authorities that issue admin client certificates.
+	// When a client CA is configured, admin clients must present a certificate
+	// issued by one of the authorities.
+	ClientCAFile string `yaml:"client_ca_file,omitempty" batik:"relpath"`
+	// ClientCAData is the PEM encoded certificates of the authorities that
+	// issue admin client certificates. If ClientCAFile is set, ClientCAData is
+	// ignored.
+	ClientCAData string `yaml:"client_ca,omitempty"`
+}
+
+// AdminServerDefaults returns the default configuration values for the admin
+// server.
+func AdminServerDefaults() *AdminServer {
+	return &AdminServer{
+		ListenAddress: "127.0.0.1:9444",
+	}
+}
+
+// ApplyDefaults applies default values for missing configuration fields.
+func (a *AdminServer) ApplyDefaults() {
+	defaults := AdminServerDefaults()
+	if a.ListenAddress == "" {
+		a.ListenAddress = defaults.ListenAddress
+	}
+}
+
+// Flags exposes configuration fields as flags. The current value of the
+// receiver is used as the default value of the flag so ApplyDefaults should be
+// called before requesting flags.
+func (a *AdminServer) Flags() []cli.Flag {
+	def := AdminServerDefaults()
+	return []cli.Flag{
+		NewStringFlag(&cli.StringFlag{
+			Name:        "admin-listen-address",
+			Value:       a.ListenAddress,
+			Destination: &a.ListenAddress,
+			Usage: flow(`The address the admin server listens on. Addresses other than
+				loopback addresses require an admin client CA.`),
+			DefaultText: def.ListenAddress,
+		}),
+		NewStringFlag(&cli.StringFlag{
+			Name:        "admin-client-ca-file",
+			Value:       a.ClientCAFile,
+			Destination: &a.ClientCAFile,
+			TakesFile:   true,
+			Usage:       flow(`File containing the PEM encoded certificates of the authorities that issue admin client certificates.`),
+			DefaultText: def.ClientCAFile,
+		}),
+	}
+}
+
+// TLSConfig returns a *tls.Config for the admin server derived from the
+// server TLS configuration. When a client CA is configured, clients must
+// present a certificate issued by the CA. An error is returned when the admin
+// server would listen on an address that is not a loopback address without
+// client authentication.
+func (a *AdminServer) TLSConfig(server *tls.Config) (*tls.Config, error) {
+	caData, err := a.clientCAData()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := server.Clone()
+	if len(caData) == 0 {
+		if !isLoopback(a.ListenAddress) {
+			return nil, errors.Errorf("admin listen address %q is not a loopback address and no admin client CA is configured", a.ListenAddress)
+		}
+		return conf, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, errors.New("admin client CA does not contain a PEM encoded certificate")
+	}
+	conf.ClientCAs = pool
+	conf.ClientAuth = tls.RequireAndVerifyClientCert
+	return conf, nil
+}
+
+func (a *AdminServer) clientCAData() (data []byte, err error) {
+	switch {
+	case a.ClientCAFile != "":
+		data, err = ioutil.ReadFile(a.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read admin client CA file")
+		}
+	case a.ClientCAData != "":
+		data = []byte(a.ClientCAData)
+	}
+	return data, nil
+}
+
+// isLoopback returns true when the host of a listen address is a loopback
+// address. An empty host listens on all addresses and is not a loopback
+// address.
+func isLoopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,93 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func TestAdminServerApplyDefaults(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	admin := &AdminServer{}
+	admin.ApplyDefaults()
+	gt.Expect(admin).To(Equal(AdminServerDefaults()))
+
+	admin = &AdminServer{ListenAddress: "127.0.0.1:1234", ClientCAFile: "ca.pem"}
+	admin.ApplyDefaults()
+	gt.Expect(admin).To(Equal(&AdminServer{ListenAddress: "127.0.0.1:1234", ClientCAFile: "ca.pem"}))
+}
+
+func TestAdminServerFlagsDefaultText(t *testing.T) {
+	flags := AdminServerDefaults().Flags()
+	assertWrappedFlagWithDefaultText(t, flags...)
+}
+
+func TestAdminServerTLSConfig(t *testing.T) {
+	ca := tested.NewCA(t, "admin-ca")
+	skp := ca.IssueServerCertificate(t, "server", "127.0.0.1")
+	server := &tls.Config{Certificates: []tls.Certificate{skp.Certificate}, MinVersion: tls.VersionTLS12}
+
+	tempDir, cleanup := tested.TempDir(t, "", "admin-tls")
+	defer cleanup()
+	caFile := filepath.Join(tempDir, "ca.pem")
+	err := ioutil.WriteFile(caFile, ca.Cert, 0o644)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := map[string]struct {
+		admin      AdminServer
+		clientAuth bool
+		errMatch   string
+	}{
+		"loopback":       {admin: AdminServer{ListenAddress: "127.0.0.1:9444"}},
+		"loopback ipv6":  {admin: AdminServer{ListenAddress: "[::1]:9444"}},
+		"localhost":      {admin: AdminServer{ListenAddress: "localhost:9444"}},
+		"all interfaces": {admin: AdminServer{ListenAddress: ":9444"}, errMatch: `admin listen address ":9444" is not a loopback address and no admin client CA is configured`},
+		"public address": {admin: AdminServer{ListenAddress: "10.0.0.1:9444"}, errMatch: `admin listen address "10.0.0.1:9444" is not a loopback address and no admin client CA is configured`},
+		"client ca data": {admin: AdminServer{ListenAddress: ":9444", ClientCAData: string(ca.Cert)}, clientAuth: true},
+		"client ca file": {admin: AdminServer{ListenAddress: ":9444", ClientCAFile: caFile}, clientAuth: true},
+		"missing ca file": {
+			admin:    AdminServer{ListenAddress: "127.0.0.1:9444", ClientCAFile: "missing.pem"},
+			errMatch: "unable to read admin client CA file: open missing.pem: no such file or directory",
+		},
+		"invalid ca": {
+			admin:    AdminServer{ListenAddress: ":9444", ClientCAData: "PEM ME"},
+			errMatch: "admin client CA does not contain a PEM encoded certificate",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			conf, err := tt.admin.TLSConfig(server)
+			if tt.errMatch != "" {
+				gt.Expect(err).To(MatchError(tt.errMatch))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(conf).NotTo(BeIdenticalTo(server))
+			gt.Expect(conf.Certificates).To(Equal(server.Certificates))
+			gt.Expect(server.ClientCAs).To(BeNil())
+
+			if !tt.clientAuth {
+				gt.Expect(conf.ClientAuth).To(Equal(tls.NoClientCert))
+				gt.Expect(conf.ClientCAs).To(BeNil())
+				return
+			}
+			gt.Expect(conf.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			caCert, err := x509.ParseCertificate(ca.Certificate.Certificate[0])
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(conf.ClientCAs.Subjects()).To(Equal([][]byte{caCert.RawSubject}))
+		})
+	}
+}
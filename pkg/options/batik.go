@@ -11,6 +11,7 @@ import (
 type Batik struct {
 	DataDir     string       `yaml:"data_dir,omitempty" batik:"relpath"`
 	Server      Server       `yaml:"server,omitempty"`
+	Identity    Identity     `yaml:"identity,omitempty"`
 	Namespaces  []Namespace  `yaml:"namespaces,omitempty"`
 	Validators  []Validator  `yaml:"validators,omitempty"`
 	TotalOrders []TotalOrder `yaml:"total_orders,omitempty"`
@@ -29,6 +30,13 @@ func BatikDefaults() *Batik {
 				Type: "builtin",
 			},
 		},
+		TotalOrders: []TotalOrder{
+			{
+				Name:    "default",
+				Type:    "in-process",
+				Storage: "leveldb",
+			},
+		},
 	}
 }
 
@@ -42,6 +50,9 @@ func (c *Batik) ApplyDefaults() {
 
 	c.Server.ApplyDefaults()
 
+	if len(c.TotalOrders) == 0 {
+		c.TotalOrders = defaults.TotalOrders
+	}
 	for i := range c.TotalOrders {
 		(&c.TotalOrders[i]).ApplyDefaults(c.DataDir)
 	}
@@ -6,6 +6,7 @@ package options
 import (
 	"flag"
 	"os"
+	"runtime"
 	"testing"
 	"time"
 
@@ -25,6 +26,13 @@ func TestBatikDefaults(t *testing.T) {
 				Type: "builtin",
 			},
 		},
+		TotalOrders: []TotalOrder{
+			{
+				Name:    "default",
+				Type:    "in-process",
+				Storage: "leveldb",
+			},
+		},
 		Logging: *LoggingDefaults(),
 	}))
 }
@@ -98,8 +106,11 @@ func TestBatikApplyDefaults(t *testing.T) {
 			input := BatikDefaults()
 			tt.setup(input)
 
+			expected := BatikDefaults()
+			expected.TotalOrders[0].DataDir = "data/totalorders/default"
+
 			input.ApplyDefaults()
-			gt.Expect(input).To(Equal(BatikDefaults()))
+			gt.Expect(input).To(Equal(expected))
 		})
 	}
 }
@@ -130,20 +141,41 @@ func TestReadConfigFileApplyDefaults(t *testing.T) {
 				ServerCert: CertKeyPair{CertData: "PEM ME\n", KeyData: "PEM ME\n"},
 				CertsDir:   "relative/certs-dir-path",
 			},
+			Admin: AdminServer{
+				ListenAddress: "127.0.0.1:7880",
+				ClientCAFile:  "relative/admin-ca.pem",
+			},
+			Peer: PeerServer{
+				ListenAddress: "127.0.0.1:7881",
+				ClientCAFile:  "relative/peer-ca.pem",
+			},
 		},
+		Identity: Identity{KeyFile: "relative/identity-key.pem"},
 		Namespaces: []Namespace{
 			{
 				Name:    "ns1",
 				DataDir: "override/path",
 			},
 			{
-				Name:      "ns2",
-				Validator: "wasm-validator1",
+				Name:              "ns2",
+				Validator:         "wasm-validator1",
+				TotalOrder:        "order1",
+				HMACSecret:        "ns2-secret",
+				ValidationWorkers: 4,
+			},
+			{
+				Name:       "ns3",
+				Storage:    "memory",
+				Validators: []string{"builtin-validator", "wasm-validator2"},
+				KindValidators: []KindValidators{
+					{Kind: "token", Validators: []string{"wasm-validator1"}},
+				},
 			},
 		},
 		TotalOrders: []TotalOrder{
 			{
-				Name: "order1",
+				Name:    "order1",
+				Storage: "bbolt",
 			},
 		},
 		Validators: []Validator{
@@ -188,17 +220,44 @@ func TestReadConfigFileApplyDefaults(t *testing.T) {
 				},
 				CertsDir: "relative/certs-dir-path",
 			},
+			Admin: AdminServer{
+				ListenAddress: "127.0.0.1:7880",
+				ClientCAFile:  "relative/admin-ca.pem",
+			},
+			Peer: PeerServer{
+				ListenAddress: "127.0.0.1:7881",
+				ClientCAFile:  "relative/peer-ca.pem",
+			},
 		},
+		Identity: Identity{KeyFile: "relative/identity-key.pem"},
 		Namespaces: []Namespace{
 			{
-				Name:      "ns1",
-				DataDir:   "override/path",
-				Validator: "signature-builtin",
+				Name:              "ns1",
+				DataDir:           "override/path",
+				Storage:           "leveldb",
+				Validator:         "signature-builtin",
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
+			},
+			{
+				Name:              "ns2",
+				DataDir:           "relative/path/namespaces/ns2",
+				Storage:           "leveldb",
+				Validator:         "wasm-validator1",
+				TotalOrder:        "order1",
+				HMACSecret:        "ns2-secret",
+				ValidationWorkers: 4,
 			},
 			{
-				Name:      "ns2",
-				DataDir:   "relative/path/namespaces/ns2",
-				Validator: "wasm-validator1",
+				Name:       "ns3",
+				DataDir:    "relative/path/namespaces/ns3",
+				Storage:    "memory",
+				Validators: []string{"builtin-validator", "wasm-validator2"},
+				KindValidators: []KindValidators{
+					{Kind: "token", Validators: []string{"wasm-validator1"}},
+				},
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
 			},
 		},
 		TotalOrders: []TotalOrder{
@@ -206,6 +265,7 @@ func TestReadConfigFileApplyDefaults(t *testing.T) {
 				Name:    "order1",
 				Type:    "in-process",
 				DataDir: "relative/path/totalorders/order1",
+				Storage: "bbolt",
 			},
 		},
 		Validators: []Validator{
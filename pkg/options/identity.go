@@ -0,0 +1,47 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/ecdsautil"
+)
+
+// Identity references the ECDSA private key a node uses to sign commit
+// attestations.
+type Identity struct {
+	// KeyFile is the name of a file containing a PEM encoded ECDSA private key.
+	KeyFile string `yaml:"key_file,omitempty" batik:"relpath"`
+	// KeyData is the PEM encoded ECDSA private key. If KeyFile is set, KeyData
+	// is ignored.
+	KeyData string `yaml:"key,omitempty"`
+}
+
+// PrivateKey returns the identity's ECDSA private key. When neither KeyFile
+// nor KeyData is set, a nil key is returned.
+func (i *Identity) PrivateKey() (*ecdsa.PrivateKey, error) {
+	var data []byte
+	switch {
+	case i.KeyFile != "":
+		d, err := ioutil.ReadFile(i.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read identity key file")
+		}
+		data = d
+	case i.KeyData != "":
+		data = []byte(i.KeyData)
+	default:
+		return nil, nil
+	}
+
+	key, err := ecdsautil.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to parse identity key")
+	}
+	return key, nil
+}
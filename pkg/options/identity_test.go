@@ -0,0 +1,64 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/ecdsautil"
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func TestIdentityPrivateKey(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	tempDir, cleanup := tested.TempDir(t, "", "options_identity")
+	defer cleanup()
+
+	key, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	der, err := ecdsautil.MarshalPrivateKey(key)
+	gt.Expect(err).NotTo(HaveOccurred())
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	keyFile := filepath.Join(tempDir, "identity.pem")
+	err = ioutil.WriteFile(keyFile, keyPEM, 0o600)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tests := map[string]struct {
+		identity Identity
+		nilKey   bool
+		errMatch string
+	}{
+		"unset":    {identity: Identity{}, nilKey: true},
+		"KeyData":  {identity: Identity{KeyData: string(keyPEM)}},
+		"KeyFile":  {identity: Identity{KeyFile: keyFile, KeyData: "ignored"}},
+		"bad file": {identity: Identity{KeyFile: filepath.Join(tempDir, "missing.pem")}, errMatch: "unable to read identity key file: open .*: no such file or directory"},
+		"bad data": {identity: Identity{KeyData: "PEM ME"}, errMatch: "unable to parse identity key: no private key PEM block found"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			pk, err := tt.identity.PrivateKey()
+			if tt.errMatch != "" {
+				gt.Expect(err).To(MatchError(MatchRegexp(tt.errMatch)))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			if tt.nilKey {
+				gt.Expect(pk).To(BeNil())
+				return
+			}
+			gt.Expect(pk).To(Equal(key))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package options
 
 import (
 	"path/filepath"
+	"runtime"
 )
 
 // Namespace exposes configuration for a namespace.
@@ -18,10 +19,53 @@ type Namespace struct {
 	// the BaseDir in the Namespaces configuration.
 	DataDir string `yaml:"data_dir,omitempty" batik:"relpath"`
 
+	// Storage is the storage engine of the database that holds the ledger
+	// artifacts for this namespace.  It may be one of 'leveldb', 'bbolt', or
+	// 'memory'.  The contents of a 'memory' database are lost when the
+	// namespace is stopped, so it is only suitable for ephemeral namespaces.
+	// If this field is not specified, 'leveldb' is used.
+	Storage string `yaml:"storage,omitempty"`
+
 	// Validator is the name of the validator used to validate transactions
 	// in this namespace.  It must be defined in the top level Validators
 	// section of the Batik configuration.
 	Validator string `yaml:"validator,omitempty"`
+
+	// Validators is an ordered list of validators that must all accept a
+	// transaction for it to be committed. Each must be defined in the top
+	// level Validators section of the Batik configuration. Validators and
+	// Validator must not both be specified.
+	Validators []string `yaml:"validators,omitempty"`
+
+	// KindValidators maps state kinds to the validators responsible for
+	// them. A transaction is validated by the validators of every kind among
+	// its inputs, references, and outputs. States with a kind that is not
+	// mapped are validated by Validator or Validators.
+	KindValidators []KindValidators `yaml:"kind_validators,omitempty"`
+
+	// TotalOrder is the name of the total order used to sequence transaction
+	// receipts in this namespace.  It must be defined in the top level
+	// TotalOrders section of the Batik configuration.
+	TotalOrder string `yaml:"total_order,omitempty"`
+
+	// HMACSecret is the secret shared by the members of this namespace that
+	// is used to authenticate the transaction receipts they submit to the
+	// total order.  Receipts that are not authenticated with this secret are
+	// not committed.  This field is required.
+	HMACSecret string `yaml:"hmac_secret,omitempty"`
+
+	// ValidationWorkers is the number of transactions that are resolved and
+	// validated concurrently. Commits are always applied in the order the
+	// transactions were sequenced. If this field is not specified, the number
+	// of CPUs is used.
+	ValidationWorkers int `yaml:"validation_workers,omitempty"`
+}
+
+// KindValidators associates a state kind with an ordered list of validators
+// that must all accept the transactions that include states of that kind.
+type KindValidators struct {
+	Kind       string   `yaml:"kind"`
+	Validators []string `yaml:"validators"`
 }
 
 // ApplyDefaults applies default values for missing configuration fields.
@@ -29,7 +73,28 @@ func (n *Namespace) ApplyDefaults(baseDataDir string) {
 	if n.DataDir == "" {
 		n.DataDir = filepath.Join(baseDataDir, "namespaces", n.Name)
 	}
-	if n.Validator == "" {
+	if n.Storage == "" {
+		n.Storage = "leveldb"
+	}
+	if n.Validator == "" && len(n.Validators) == 0 {
 		n.Validator = "signature-builtin"
 	}
+	if n.TotalOrder == "" {
+		n.TotalOrder = "default"
+	}
+	if n.ValidationWorkers == 0 {
+		n.ValidationWorkers = runtime.NumCPU()
+	}
+}
+
+// ValidatorNames returns the names of the validators used by the namespace in
+// the order they run.
+func (n *Namespace) ValidatorNames() []string {
+	if len(n.Validators) != 0 {
+		return n.Validators
+	}
+	if n.Validator == "" {
+		return nil
+	}
+	return []string{n.Validator}
 }
@@ -4,6 +4,7 @@
 package options
 
 import (
+	"runtime"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -11,9 +12,12 @@ import (
 
 func TestNamespaceApplyDefaults(t *testing.T) {
 	defaults := Namespace{
-		Name:      "name",
-		DataDir:   "data/namespaces/name",
-		Validator: "signature-builtin",
+		Name:              "name",
+		DataDir:           "data/namespaces/name",
+		Storage:           "leveldb",
+		Validator:         "signature-builtin",
+		TotalOrder:        "default",
+		ValidationWorkers: runtime.NumCPU(),
 	}
 
 	tests := map[string]struct {
@@ -28,24 +32,82 @@ func TestNamespaceApplyDefaults(t *testing.T) {
 			setup:    func(l *Namespace) { l.DataDir = "" },
 			expected: defaults,
 		},
+		"storage": {
+			setup:    func(l *Namespace) { l.Storage = "" },
+			expected: defaults,
+		},
 		"validator": {
 			setup:    func(l *Namespace) { l.Validator = "" },
 			expected: defaults,
 		},
+		"total order": {
+			setup:    func(l *Namespace) { l.TotalOrder = "" },
+			expected: defaults,
+		},
 		"overridden data dir": {
 			setup: func(l *Namespace) { l.DataDir = "some/path" },
 			expected: Namespace{
-				Name:      "name",
-				DataDir:   "some/path",
-				Validator: "signature-builtin",
+				Name:              "name",
+				DataDir:           "some/path",
+				Storage:           "leveldb",
+				Validator:         "signature-builtin",
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
+			},
+		},
+		"overridden storage": {
+			setup: func(l *Namespace) { l.Storage = "memory" },
+			expected: Namespace{
+				Name:              "name",
+				DataDir:           "data/namespaces/name",
+				Storage:           "memory",
+				Validator:         "signature-builtin",
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
 			},
 		},
 		"overridden validator": {
 			setup: func(l *Namespace) { l.Validator = "custom" },
 			expected: Namespace{
-				Name:      "name",
-				DataDir:   "data/namespaces/name",
-				Validator: "custom",
+				Name:              "name",
+				DataDir:           "data/namespaces/name",
+				Storage:           "leveldb",
+				Validator:         "custom",
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
+			},
+		},
+		"validator chain": {
+			setup: func(l *Namespace) { l.Validator, l.Validators = "", []string{"first", "second"} },
+			expected: Namespace{
+				Name:              "name",
+				DataDir:           "data/namespaces/name",
+				Storage:           "leveldb",
+				Validators:        []string{"first", "second"},
+				TotalOrder:        "default",
+				ValidationWorkers: runtime.NumCPU(),
+			},
+		},
+		"overridden total order": {
+			setup: func(l *Namespace) { l.TotalOrder = "custom" },
+			expected: Namespace{
+				Name:              "name",
+				DataDir:           "data/namespaces/name",
+				Storage:           "leveldb",
+				Validator:         "signature-builtin",
+				TotalOrder:        "custom",
+				ValidationWorkers: runtime.NumCPU(),
+			},
+		},
+		"overridden validation workers": {
+			setup: func(l *Namespace) { l.ValidationWorkers = 4 },
+			expected: Namespace{
+				Name:              "name",
+				DataDir:           "data/namespaces/name",
+				Storage:           "leveldb",
+				Validator:         "signature-builtin",
+				TotalOrder:        "default",
+				ValidationWorkers: 4,
 			},
 		},
 	}
@@ -62,3 +124,21 @@ func TestNamespaceApplyDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceValidatorNames(t *testing.T) {
+	tests := map[string]struct {
+		namespace Namespace
+		expected  []string
+	}{
+		"none":      {namespace: Namespace{}, expected: nil},
+		"validator": {namespace: Namespace{Validator: "single"}, expected: []string{"single"}},
+		"chain":     {namespace: Namespace{Validators: []string{"first", "second"}}, expected: []string{"first", "second"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			gt.Expect(tt.namespace.ValidatorNames()).To(Equal(tt.expected))
+		})
+	}
+}
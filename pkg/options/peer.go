@@ -0,0 +1,100 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	cli "github.com/urfave/cli/v2"
+)
+
+// PeerServer exposes configuration for the gRPC server that the members of
+// raft total orders hosted by other nodes use to exchange messages. The peer
+// server is only started when a raft total order has other members and peers
+// must authenticate with a client certificate.
+type PeerServer struct {
+	// ListenAddress determines the address that the peer server will listen
+	// on. The address should be in a form that is compatible with net.Listen
+	// from the Go standard library.
+	ListenAddress string `yaml:"listen_address,omitempty"`
+	// ClientCAFile is the name of a file containing the PEM encoded
+	// certificates of the authorities that issue the client certificates of
+	// raft peers.
+	ClientCAFile string `yaml:"client_ca_file,omitempty" batik:"relpath"`
+	// ClientCAData is the PEM encoded certificates of the authorities that
+	// issue the client certificates of raft peers. If ClientCAFile is set,
+	// ClientCAData is ignored.
+	ClientCAData string `yaml:"client_ca,omitempty"`
+}
+
+// PeerServerDefaults returns the default configuration values for the peer
+// server.
+func PeerServerDefaults() *PeerServer {
+	return &PeerServer{
+		ListenAddress: ":9445",
+	}
+}
+
+// ApplyDefaults applies default values for missing configuration fields.
+func (p *PeerServer) ApplyDefaults() {
+	defaults := PeerServerDefaults()
+	if p.ListenAddress == "" {
+		p.ListenAddress = defaults.ListenAddress
+	}
+}
+
+// Flags exposes configuration fields as flags. The current value of the
+// receiver is used as the default value of the flag so ApplyDefaults should be
+// called before requesting flags.
+func (p *PeerServer) Flags() []cli.Flag {
+	def := PeerServerDefaults()
+	return []cli.Flag{
+		NewStringFlag(&cli.StringFlag{
+			Name:        "peer-listen-address",
+			Value:       p.ListenAddress,
+			Destination: &p.ListenAddress,
+			Usage:       flow(`The address the server for raft peers listens on.`),
+			DefaultText: def.ListenAddress,
+		}),
+		NewStringFlag(&cli.StringFlag{
+			Name:        "peer-client-ca-file",
+			Value:       p.ClientCAFile,
+			Destination: &p.ClientCAFile,
+			TakesFile:   true,
+			Usage:       flow(`File containing the PEM encoded certificates of the authorities that issue raft peer client certificates.`),
+			DefaultText: def.ClientCAFile,
+		}),
+	}
+}
+
+// TLSConfig returns a *tls.Config for the peer server derived from the
+// server TLS configuration. Clients must present a certificate issued by the
+// peer client CA; an error is returned when no client CA is configured.
+func (p *PeerServer) TLSConfig(server *tls.Config) (*tls.Config, error) {
+	var caData []byte
+	switch {
+	case p.ClientCAFile != "":
+		data, err := ioutil.ReadFile(p.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read peer client CA file")
+		}
+		caData = data
+	case p.ClientCAData != "":
+		caData = []byte(p.ClientCAData)
+	default:
+		return nil, errors.New("no peer client CA is configured")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, errors.New("peer client CA does not contain a PEM encoded certificate")
+	}
+	conf := server.Clone()
+	conf.ClientCAs = pool
+	conf.ClientAuth = tls.RequireAndVerifyClientCert
+	return conf, nil
+}
@@ -0,0 +1,82 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func TestPeerServerApplyDefaults(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	peer := &PeerServer{}
+	peer.ApplyDefaults()
+	gt.Expect(peer).To(Equal(PeerServerDefaults()))
+
+	peer = &PeerServer{ListenAddress: "127.0.0.1:1234", ClientCAFile: "ca.pem"}
+	peer.ApplyDefaults()
+	gt.Expect(peer).To(Equal(&PeerServer{ListenAddress: "127.0.0.1:1234", ClientCAFile: "ca.pem"}))
+}
+
+func TestPeerServerFlagsDefaultText(t *testing.T) {
+	flags := PeerServerDefaults().Flags()
+	assertWrappedFlagWithDefaultText(t, flags...)
+}
+
+func TestPeerServerTLSConfig(t *testing.T) {
+	ca := tested.NewCA(t, "peer-ca")
+	skp := ca.IssueServerCertificate(t, "server", "127.0.0.1")
+	server := &tls.Config{Certificates: []tls.Certificate{skp.Certificate}, MinVersion: tls.VersionTLS12}
+
+	tempDir, cleanup := tested.TempDir(t, "", "peer-tls")
+	defer cleanup()
+	caFile := filepath.Join(tempDir, "ca.pem")
+	err := ioutil.WriteFile(caFile, ca.Cert, 0o644)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := map[string]struct {
+		peer     PeerServer
+		errMatch string
+	}{
+		"client ca data": {peer: PeerServer{ClientCAData: string(ca.Cert)}},
+		"client ca file": {peer: PeerServer{ClientCAFile: caFile, ClientCAData: "ignored"}},
+		"no client ca":   {peer: PeerServer{ListenAddress: "127.0.0.1:9445"}, errMatch: "no peer client CA is configured"},
+		"missing ca file": {
+			peer:     PeerServer{ClientCAFile: "missing.pem"},
+			errMatch: "unable to read peer client CA file: open missing.pem: no such file or directory",
+		},
+		"invalid ca": {
+			peer:     PeerServer{ClientCAData: "PEM ME"},
+			errMatch: "peer client CA does not contain a PEM encoded certificate",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			conf, err := tt.peer.TLSConfig(server)
+			if tt.errMatch != "" {
+				gt.Expect(err).To(MatchError(tt.errMatch))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(conf).NotTo(BeIdenticalTo(server))
+			gt.Expect(conf.Certificates).To(Equal(server.Certificates))
+			gt.Expect(server.ClientCAs).To(BeNil())
+			gt.Expect(conf.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			caCert, err := x509.ParseCertificate(ca.Certificate.Certificate[0])
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(conf.ClientCAs.Subjects()).To(Equal([][]byte{caCert.RawSubject}))
+		})
+	}
+}
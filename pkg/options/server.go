@@ -15,14 +15,22 @@ type Server struct {
 	HTTP HTTPServer `yaml:"http,omitempty"`
 	// TLS references the TLS configuration for a server.
 	TLS ServerTLS `yaml:"tls,omitempty"`
+	// Admin maintains the configuration of the server that hosts the
+	// administrative API.
+	Admin AdminServer `yaml:"admin,omitempty"`
+	// Peer maintains the configuration of the server that hosts the API used
+	// by the members of raft total orders.
+	Peer PeerServer `yaml:"peer,omitempty"`
 }
 
 // ServerDefault returns the default configuration values for the server component.
 func ServerDefaults() *Server {
 	return &Server{
-		GRPC: *GRPCServerDefaults(),
-		HTTP: *HTTPServerDefaults(),
-		TLS:  *ServerTLSDefaults(),
+		GRPC:  *GRPCServerDefaults(),
+		HTTP:  *HTTPServerDefaults(),
+		TLS:   *ServerTLSDefaults(),
+		Admin: *AdminServerDefaults(),
+		Peer:  *PeerServerDefaults(),
 	}
 }
 
@@ -31,6 +39,8 @@ func (s *Server) ApplyDefaults() {
 	s.GRPC.ApplyDefaults()
 	s.HTTP.ApplyDefaults()
 	s.TLS.ApplyDefaults()
+	s.Admin.ApplyDefaults()
+	s.Peer.ApplyDefaults()
 }
 
 // Flags exposes configuration fields as flags. The current value of the
@@ -41,5 +51,7 @@ func (s *Server) Flags() []cli.Flag {
 	flags = append(flags, s.GRPC.Flags()...)
 	flags = append(flags, s.HTTP.Flags()...)
 	flags = append(flags, s.TLS.Flags()...)
+	flags = append(flags, s.Admin.Flags()...)
+	flags = append(flags, s.Peer.Flags()...)
 	return flags
 }
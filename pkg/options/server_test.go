@@ -15,9 +15,11 @@ func TestServerDefaults(t *testing.T) {
 	gt := NewGomegaWithT(t)
 	server := ServerDefaults()
 	gt.Expect(server).To(Equal(&Server{
-		GRPC: *GRPCServerDefaults(),
-		HTTP: *HTTPServerDefaults(),
-		TLS:  *ServerTLSDefaults(),
+		GRPC:  *GRPCServerDefaults(),
+		HTTP:  *HTTPServerDefaults(),
+		TLS:   *ServerTLSDefaults(),
+		Admin: *AdminServerDefaults(),
+		Peer:  *PeerServerDefaults(),
 	}))
 }
 
@@ -28,6 +30,8 @@ func TestServerApplyDefaults(t *testing.T) {
 		"empty": {setup: func(s *Server) { *s = Server{} }},
 		"GRPC":  {setup: func(s *Server) { s.GRPC = GRPCServer{} }},
 		"TLS":   {setup: func(s *Server) { s.TLS = ServerTLS{} }},
+		"Admin": {setup: func(s *Server) { s.Admin = AdminServer{} }},
+		"Peer":  {setup: func(s *Server) { s.Peer = PeerServer{} }},
 	}
 
 	for name, tt := range tests {
@@ -53,7 +57,7 @@ func TestServerFlagNames(t *testing.T) {
 		names = append(names, f.Names()...)
 	}
 
-	gt.Expect(flags).To(HaveLen(12))
+	gt.Expect(flags).To(HaveLen(16))
 	gt.Expect(names).To(ConsistOf(
 		"grpc-conn-timeout",
 		"grpc-listen-address",
@@ -67,6 +71,10 @@ func TestServerFlagNames(t *testing.T) {
 		"tls-cert-file",
 		"tls-private-key-file",
 		"tls-certs-dir",
+		"admin-listen-address",
+		"admin-client-ca-file",
+		"peer-listen-address",
+		"peer-client-ca-file",
 	))
 }
 
@@ -95,6 +103,10 @@ func TestServerFlags(t *testing.T) {
 				"--tls-cert-file", "file.crt",
 				"--tls-private-key-file", "private.key",
 				"--tls-certs-dir", "custom/tls-certs",
+				"--admin-listen-address", "127.0.0.1:7443",
+				"--admin-client-ca-file", "admin-ca.crt",
+				"--peer-listen-address", "127.0.0.1:7445",
+				"--peer-client-ca-file", "peer-ca.crt",
 			},
 			expected: Server{
 				GRPC: GRPCServer{
@@ -105,6 +117,8 @@ func TestServerFlags(t *testing.T) {
 					ServerCert: CertKeyPair{CertFile: "file.crt", KeyFile: "private.key"},
 					CertsDir:   "custom/tls-certs",
 				},
+				Admin: AdminServer{ListenAddress: "127.0.0.1:7443", ClientCAFile: "admin-ca.crt"},
+				Peer:  PeerServer{ListenAddress: "127.0.0.1:7445", ClientCAFile: "peer-ca.crt"},
 			},
 		},
 	}
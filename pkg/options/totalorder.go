@@ -5,6 +5,7 @@ package options
 
 import (
 	"path/filepath"
+	"time"
 )
 
 // Namespace exposes configuration for a namespace.
@@ -14,14 +15,91 @@ type TotalOrder struct {
 	Name string `yaml:"name"`
 
 	// Type is the consensus type for this total order.  Depending on the
-	// type, other configuration may be set.  Currently, the only type
-	// is 'in-process', but other types including 'static-leader', and ultimately
-	// more robust consensus types will be added.
+	// type, other configuration may be set.  Currently, the types are
+	// 'in-process', 'raft', and 'grpc', but other types including
+	// 'static-leader' may be added.
 	Type string `yaml:"type,omitempty"`
 
 	// DataDir is the path where the db for this total order will be stored.  Note
 	// the database will only be created if this peer is a consenter on order.
 	DataDir string `yaml:"data_dir,omitempty" batik:"relpath"`
+
+	// Storage is the storage engine of the database for this total order.
+	// It may be one of 'leveldb', 'bbolt', or 'memory'.  The contents of a
+	// 'memory' database are lost when the total order is stopped.  If this
+	// field is not specified, 'leveldb' is used.  It is ignored by the
+	// 'grpc' consensus type.
+	Storage string `yaml:"storage,omitempty"`
+
+	// Batch is the block cutting configuration for the 'in-process'
+	// consensus type.  It is ignored for other types.
+	Batch BatchTotalOrder `yaml:"batch,omitempty"`
+
+	// Raft is the configuration for the 'raft' consensus type.  It is
+	// ignored for other types.
+	Raft RaftTotalOrder `yaml:"raft,omitempty"`
+
+	// GRPC is the configuration for the 'grpc' consensus type.  It is
+	// ignored for other types.
+	GRPC GRPCTotalOrder `yaml:"grpc,omitempty"`
+}
+
+// BatchTotalOrder exposes the configuration used to cut blocks of entries.
+// Blocks are only cut when MaxEntries is greater than one; otherwise every
+// entry is appended to the total order on its own.
+type BatchTotalOrder struct {
+	// MaxEntries is the maximum number of entries in a block.
+	MaxEntries int `yaml:"max_entries,omitempty"`
+
+	// MaxBytes is the maximum size of the entries in a block.  When it is
+	// 0, the size of a block is not limited.
+	MaxBytes int `yaml:"max_bytes,omitempty"`
+
+	// Timeout is the maximum amount of time an entry waits for a block to
+	// fill before the block is cut.  When it is 0, the default timeout of
+	// the total order is used.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// RaftTotalOrder exposes configuration for a raft total order.
+type RaftTotalOrder struct {
+	// NodeID is the identifier of this node within the raft cluster.  It
+	// must be unique within the cluster and may not be 0.
+	NodeID uint64 `yaml:"node_id,omitempty"`
+
+	// Peers is the list of node identifiers for all members of the raft
+	// cluster, including this node.
+	Peers []uint64 `yaml:"peers,omitempty"`
+
+	// PeerAddresses maps the node identifiers of the other members of the
+	// raft cluster to the host and port of the peer server of the node
+	// hosting them.  An address is required for every peer other than this
+	// node.  Peers host the raft total order under the same name.  Messages
+	// from a peer are only accepted when its client certificate is valid for
+	// the host of its address.
+	PeerAddresses map[uint64]string `yaml:"peer_addresses,omitempty"`
+
+	// RootCAFile is the path to a file containing the PEM encoded
+	// certificates trusted to verify the servers of the peers.  When it is
+	// not specified, the system trust store is used.
+	RootCAFile string `yaml:"root_ca_file,omitempty" batik:"relpath"`
+
+	// ClientCert is the certificate and private key presented to the peer
+	// servers of the other members of the raft cluster.  It must be issued
+	// by a peer client CA trusted by the peers for the host this node is
+	// reachable at.
+	ClientCert CertKeyPair `yaml:"client_cert,omitempty"`
+
+	// TickInterval is the duration of a raft clock tick.
+	TickInterval time.Duration `yaml:"tick_interval,omitempty"`
+
+	// ElectionTicks is the number of ticks a follower waits without hearing
+	// from the leader before starting an election.
+	ElectionTicks int `yaml:"election_ticks,omitempty"`
+
+	// HeartbeatTicks is the number of ticks between heartbeats sent by the
+	// leader.  It must be less than ElectionTicks.
+	HeartbeatTicks int `yaml:"heartbeat_ticks,omitempty"`
 }
 
 // ApplyDefaults applies default values for missing configuration fields.
@@ -33,4 +111,52 @@ func (n *TotalOrder) ApplyDefaults(baseDataDir string) {
 	if n.DataDir == "" {
 		n.DataDir = filepath.Join(baseDataDir, "totalorders", n.Name)
 	}
+	if n.Storage == "" {
+		n.Storage = "leveldb"
+	}
+
+	switch n.Type {
+	case "raft":
+		n.Raft.ApplyDefaults()
+	case "grpc":
+		if n.GRPC.TotalOrder == "" {
+			n.GRPC.TotalOrder = n.Name
+		}
+	}
+}
+
+// GRPCTotalOrder exposes configuration for a total order that is hosted by
+// another batik node and accessed through its ordering API.
+type GRPCTotalOrder struct {
+	// Address is the host and port of the gRPC server of the node hosting
+	// the total order.
+	Address string `yaml:"address,omitempty"`
+
+	// TotalOrder is the name of the total order on the remote node.  If this
+	// field is not specified, the name of the local total order is used.
+	TotalOrder string `yaml:"total_order,omitempty"`
+
+	// RootCAFile is the path to a file containing the PEM encoded
+	// certificates trusted to verify the remote server.  When it is not
+	// specified, the system trust store is used.
+	RootCAFile string `yaml:"root_ca_file,omitempty" batik:"relpath"`
+}
+
+// ApplyDefaults applies default values for missing configuration fields.
+func (r *RaftTotalOrder) ApplyDefaults() {
+	if r.NodeID == 0 {
+		r.NodeID = 1
+	}
+	if len(r.Peers) == 0 {
+		r.Peers = []uint64{r.NodeID}
+	}
+	if r.TickInterval == 0 {
+		r.TickInterval = 100 * time.Millisecond
+	}
+	if r.ElectionTicks == 0 {
+		r.ElectionTicks = 10
+	}
+	if r.HeartbeatTicks == 0 {
+		r.HeartbeatTicks = 1
+	}
 }
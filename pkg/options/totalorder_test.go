@@ -5,6 +5,7 @@ package options
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 )
@@ -14,6 +15,7 @@ func TestTotalOrderApplyDefaults(t *testing.T) {
 		Name:    "name", // TODO / note, if name is unspecified, what behavior do we want?
 		Type:    "in-process",
 		DataDir: "base/dir/totalorders/name",
+		Storage: "leveldb",
 	}
 
 	tests := map[string]struct {
@@ -32,12 +34,103 @@ func TestTotalOrderApplyDefaults(t *testing.T) {
 			setup:    func(l *TotalOrder) { l.DataDir = "" },
 			expected: defaults,
 		},
+		"storage": {
+			setup:    func(l *TotalOrder) { l.Storage = "" },
+			expected: defaults,
+		},
+		"overridden storage": {
+			setup: func(l *TotalOrder) { l.Storage = "bbolt" },
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "in-process",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "bbolt",
+			},
+		},
 		"overridden data dir": {
 			setup: func(l *TotalOrder) { l.DataDir = "some/path" },
 			expected: TotalOrder{
 				Name:    "name",
 				Type:    "in-process",
 				DataDir: "some/path",
+				Storage: "leveldb",
+			},
+		},
+		"batch": {
+			setup: func(l *TotalOrder) { l.Batch.MaxEntries = 100 },
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "in-process",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "leveldb",
+				Batch: BatchTotalOrder{
+					MaxEntries: 100,
+				},
+			},
+		},
+		"batch timeout": {
+			setup: func(l *TotalOrder) { l.Batch = BatchTotalOrder{MaxEntries: 10, MaxBytes: 640, Timeout: time.Second} },
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "in-process",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "leveldb",
+				Batch: BatchTotalOrder{
+					MaxEntries: 10,
+					MaxBytes:   640,
+					Timeout:    time.Second,
+				},
+			},
+		},
+		"raft": {
+			setup: func(l *TotalOrder) { l.Type = "raft" },
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "raft",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "leveldb",
+				Raft: RaftTotalOrder{
+					NodeID:         1,
+					Peers:          []uint64{1},
+					TickInterval:   100 * time.Millisecond,
+					ElectionTicks:  10,
+					HeartbeatTicks: 1,
+				},
+			},
+		},
+		"grpc": {
+			setup: func(l *TotalOrder) {
+				l.Type = "grpc"
+				l.GRPC.Address = "127.0.0.1:9443"
+			},
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "grpc",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "leveldb",
+				GRPC: GRPCTotalOrder{
+					Address:    "127.0.0.1:9443",
+					TotalOrder: "name",
+				},
+			},
+		},
+		"raft peers": {
+			setup: func(l *TotalOrder) {
+				l.Type = "raft"
+				l.Raft = RaftTotalOrder{NodeID: 2, TickInterval: time.Second}
+			},
+			expected: TotalOrder{
+				Name:    "name",
+				Type:    "raft",
+				DataDir: "base/dir/totalorders/name",
+				Storage: "leveldb",
+				Raft: RaftTotalOrder{
+					NodeID:         2,
+					Peers:          []uint64{2},
+					TickInterval:   time.Second,
+					ElectionTicks:  10,
+					HeartbeatTicks: 1,
+				},
 			},
 		},
 	}
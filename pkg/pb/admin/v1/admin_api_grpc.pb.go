@@ -0,0 +1,299 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package adminv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminAPIClient is the client API for AdminAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminAPIClient interface {
+	// CreateNamespace opens the database of a namespace and starts commit
+	// processing for it.
+	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error)
+	// ListNamespaces lists the active namespaces.
+	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	// DescribeNamespace retrieves the configuration and commit progress of a
+	// namespace.
+	DescribeNamespace(ctx context.Context, in *DescribeNamespaceRequest, opts ...grpc.CallOption) (*DescribeNamespaceResponse, error)
+	// RemoveNamespace stops commit processing for a namespace and closes its
+	// database. The data in the database is not deleted.
+	RemoveNamespace(ctx context.Context, in *RemoveNamespaceRequest, opts ...grpc.CallOption) (*RemoveNamespaceResponse, error)
+	// GetHealth reports whether commit processing is running for each active
+	// namespace.
+	GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error)
+	// ResumeNamespace restarts commit processing for a halted namespace. The
+	// ordered entry that caused the halt is processed again.
+	ResumeNamespace(ctx context.Context, in *ResumeNamespaceRequest, opts ...grpc.CallOption) (*ResumeNamespaceResponse, error)
+}
+
+type adminAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminAPIClient(cc grpc.ClientConnInterface) AdminAPIClient {
+	return &adminAPIClient{cc}
+}
+
+func (c *adminAPIClient) CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error) {
+	out := new(CreateNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/CreateNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error) {
+	out := new(ListNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/ListNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) DescribeNamespace(ctx context.Context, in *DescribeNamespaceRequest, opts ...grpc.CallOption) (*DescribeNamespaceResponse, error) {
+	out := new(DescribeNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/DescribeNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) RemoveNamespace(ctx context.Context, in *RemoveNamespaceRequest, opts ...grpc.CallOption) (*RemoveNamespaceResponse, error) {
+	out := new(RemoveNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/RemoveNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error) {
+	out := new(GetHealthResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/GetHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ResumeNamespace(ctx context.Context, in *ResumeNamespaceRequest, opts ...grpc.CallOption) (*ResumeNamespaceResponse, error) {
+	out := new(ResumeNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.AdminAPI/ResumeNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminAPIServer is the server API for AdminAPI service.
+// All implementations must embed UnimplementedAdminAPIServer
+// for forward compatibility
+type AdminAPIServer interface {
+	// CreateNamespace opens the database of a namespace and starts commit
+	// processing for it.
+	CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error)
+	// ListNamespaces lists the active namespaces.
+	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
+	// DescribeNamespace retrieves the configuration and commit progress of a
+	// namespace.
+	DescribeNamespace(context.Context, *DescribeNamespaceRequest) (*DescribeNamespaceResponse, error)
+	// RemoveNamespace stops commit processing for a namespace and closes its
+	// database. The data in the database is not deleted.
+	RemoveNamespace(context.Context, *RemoveNamespaceRequest) (*RemoveNamespaceResponse, error)
+	// GetHealth reports whether commit processing is running for each active
+	// namespace.
+	GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error)
+	// ResumeNamespace restarts commit processing for a halted namespace. The
+	// ordered entry that caused the halt is processed again.
+	ResumeNamespace(context.Context, *ResumeNamespaceRequest) (*ResumeNamespaceResponse, error)
+	mustEmbedUnimplementedAdminAPIServer()
+}
+
+// UnimplementedAdminAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminAPIServer struct {
+}
+
+func (UnimplementedAdminAPIServer) CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNamespace not implemented")
+}
+func (UnimplementedAdminAPIServer) ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNamespaces not implemented")
+}
+func (UnimplementedAdminAPIServer) DescribeNamespace(context.Context, *DescribeNamespaceRequest) (*DescribeNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeNamespace not implemented")
+}
+func (UnimplementedAdminAPIServer) RemoveNamespace(context.Context, *RemoveNamespaceRequest) (*RemoveNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNamespace not implemented")
+}
+func (UnimplementedAdminAPIServer) GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHealth not implemented")
+}
+func (UnimplementedAdminAPIServer) ResumeNamespace(context.Context, *ResumeNamespaceRequest) (*ResumeNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeNamespace not implemented")
+}
+func (UnimplementedAdminAPIServer) mustEmbedUnimplementedAdminAPIServer() {}
+
+// UnsafeAdminAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminAPIServer will
+// result in compilation errors.
+type UnsafeAdminAPIServer interface {
+	mustEmbedUnimplementedAdminAPIServer()
+}
+
+func RegisterAdminAPIServer(s grpc.ServiceRegistrar, srv AdminAPIServer) {
+	s.RegisterService(&_AdminAPI_serviceDesc, srv)
+}
+
+func _AdminAPI_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_DescribeNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).DescribeNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/DescribeNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).DescribeNamespace(ctx, req.(*DescribeNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_RemoveNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).RemoveNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/RemoveNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).RemoveNamespace(ctx, req.(*RemoveNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_GetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).GetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/GetHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).GetHealth(ctx, req.(*GetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ResumeNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ResumeNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.AdminAPI/ResumeNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ResumeNamespace(ctx, req.(*ResumeNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AdminAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.v1.AdminAPI",
+	HandlerType: (*AdminAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _AdminAPI_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _AdminAPI_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "DescribeNamespace",
+			Handler:    _AdminAPI_DescribeNamespace_Handler,
+		},
+		{
+			MethodName: "RemoveNamespace",
+			Handler:    _AdminAPI_RemoveNamespace_Handler,
+		},
+		{
+			MethodName: "GetHealth",
+			Handler:    _AdminAPI_GetHealth_Handler,
+		},
+		{
+			MethodName: "ResumeNamespace",
+			Handler:    _AdminAPI_ResumeNamespace_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/v1/admin_api.proto",
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package orderv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// OrderingAPIClient is the client API for OrderingAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderingAPIClient interface {
+	// Broadcast submits an entry to a total order for sequencing.
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error)
+	// Deliver streams the ordered entries of a total order, in sequence,
+	// starting at from_seq. When the end of the order is reached, the stream
+	// waits for new entries to be ordered.
+	Deliver(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (OrderingAPI_DeliverClient, error)
+	// Checkpoint returns the hash chain accumulator of a total order through a
+	// sequence number. Nodes with the same checkpoint have the same entries.
+	Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error)
+}
+
+type orderingAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderingAPIClient(cc grpc.ClientConnInterface) OrderingAPIClient {
+	return &orderingAPIClient{cc}
+}
+
+func (c *orderingAPIClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error) {
+	out := new(BroadcastResponse)
+	err := c.cc.Invoke(ctx, "/order.v1.OrderingAPI/Broadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderingAPIClient) Deliver(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (OrderingAPI_DeliverClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OrderingAPI_serviceDesc.Streams[0], "/order.v1.OrderingAPI/Deliver", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderingAPIDeliverClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderingAPI_DeliverClient interface {
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type orderingAPIDeliverClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderingAPIDeliverClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderingAPIClient) Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error) {
+	out := new(CheckpointResponse)
+	err := c.cc.Invoke(ctx, "/order.v1.OrderingAPI/Checkpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderingAPIServer is the server API for OrderingAPI service.
+// All implementations must embed UnimplementedOrderingAPIServer
+// for forward compatibility
+type OrderingAPIServer interface {
+	// Broadcast submits an entry to a total order for sequencing.
+	Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error)
+	// Deliver streams the ordered entries of a total order, in sequence,
+	// starting at from_seq. When the end of the order is reached, the stream
+	// waits for new entries to be ordered.
+	Deliver(*DeliverRequest, OrderingAPI_DeliverServer) error
+	// Checkpoint returns the hash chain accumulator of a total order through a
+	// sequence number. Nodes with the same checkpoint have the same entries.
+	Checkpoint(context.Context, *CheckpointRequest) (*CheckpointResponse, error)
+	mustEmbedUnimplementedOrderingAPIServer()
+}
+
+// UnimplementedOrderingAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedOrderingAPIServer struct {
+}
+
+func (UnimplementedOrderingAPIServer) Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedOrderingAPIServer) Deliver(*DeliverRequest, OrderingAPI_DeliverServer) error {
+	return status.Errorf(codes.Unimplemented, "method Deliver not implemented")
+}
+func (UnimplementedOrderingAPIServer) Checkpoint(context.Context, *CheckpointRequest) (*CheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkpoint not implemented")
+}
+func (UnimplementedOrderingAPIServer) mustEmbedUnimplementedOrderingAPIServer() {}
+
+// UnsafeOrderingAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderingAPIServer will
+// result in compilation errors.
+type UnsafeOrderingAPIServer interface {
+	mustEmbedUnimplementedOrderingAPIServer()
+}
+
+func RegisterOrderingAPIServer(s grpc.ServiceRegistrar, srv OrderingAPIServer) {
+	s.RegisterService(&_OrderingAPI_serviceDesc, srv)
+}
+
+func _OrderingAPI_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderingAPIServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/order.v1.OrderingAPI/Broadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderingAPIServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderingAPI_Deliver_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeliverRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderingAPIServer).Deliver(m, &orderingAPIDeliverServer{stream})
+}
+
+type OrderingAPI_DeliverServer interface {
+	Send(*DeliverResponse) error
+	grpc.ServerStream
+}
+
+type orderingAPIDeliverServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderingAPIDeliverServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderingAPI_Checkpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderingAPIServer).Checkpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/order.v1.OrderingAPI/Checkpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderingAPIServer).Checkpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _OrderingAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v1.OrderingAPI",
+	HandlerType: (*OrderingAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Broadcast",
+			Handler:    _OrderingAPI_Broadcast_Handler,
+		},
+		{
+			MethodName: "Checkpoint",
+			Handler:    _OrderingAPI_Checkpoint_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Deliver",
+			Handler:       _OrderingAPI_Deliver_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "order/v1/order_api.proto",
+}
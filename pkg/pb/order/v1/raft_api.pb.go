@@ -0,0 +1,530 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: order/v1/raft_api.proto
+
+package orderv1
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// RaftMessageType identifies the purpose of a RaftMessage.
+type RaftMessageType int32
+
+const (
+	// The message type was not provided.
+	RaftMessageType_RAFT_MESSAGE_TYPE_UNSPECIFIED RaftMessageType = 0
+	// A candidate requests a vote.
+	RaftMessageType_RAFT_MESSAGE_TYPE_VOTE RaftMessageType = 1
+	// A vote is granted or rejected.
+	RaftMessageType_RAFT_MESSAGE_TYPE_VOTE_RESP RaftMessageType = 2
+	// The leader replicates log entries and the commit index.
+	RaftMessageType_RAFT_MESSAGE_TYPE_APP RaftMessageType = 3
+	// A follower acknowledges or rejects replicated entries.
+	RaftMessageType_RAFT_MESSAGE_TYPE_APP_RESP RaftMessageType = 4
+	// A follower forwards a proposal to the leader.
+	RaftMessageType_RAFT_MESSAGE_TYPE_PROP RaftMessageType = 5
+)
+
+// Enum value maps for RaftMessageType.
+var (
+	RaftMessageType_name = map[int32]string{
+		0: "RAFT_MESSAGE_TYPE_UNSPECIFIED",
+		1: "RAFT_MESSAGE_TYPE_VOTE",
+		2: "RAFT_MESSAGE_TYPE_VOTE_RESP",
+		3: "RAFT_MESSAGE_TYPE_APP",
+		4: "RAFT_MESSAGE_TYPE_APP_RESP",
+		5: "RAFT_MESSAGE_TYPE_PROP",
+	}
+	RaftMessageType_value = map[string]int32{
+		"RAFT_MESSAGE_TYPE_UNSPECIFIED": 0,
+		"RAFT_MESSAGE_TYPE_VOTE":        1,
+		"RAFT_MESSAGE_TYPE_VOTE_RESP":   2,
+		"RAFT_MESSAGE_TYPE_APP":         3,
+		"RAFT_MESSAGE_TYPE_APP_RESP":    4,
+		"RAFT_MESSAGE_TYPE_PROP":        5,
+	}
+)
+
+func (x RaftMessageType) Enum() *RaftMessageType {
+	p := new(RaftMessageType)
+	*p = x
+	return p
+}
+
+func (x RaftMessageType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RaftMessageType) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_v1_raft_api_proto_enumTypes[0].Descriptor()
+}
+
+func (RaftMessageType) Type() protoreflect.EnumType {
+	return &file_order_v1_raft_api_proto_enumTypes[0]
+}
+
+func (x RaftMessageType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RaftMessageType.Descriptor instead.
+func (RaftMessageType) EnumDescriptor() ([]byte, []int) {
+	return file_order_v1_raft_api_proto_rawDescGZIP(), []int{0}
+}
+
+// RaftEntry is an entry in the replicated raft log. Entries appended by a new
+// leader to commit entries from prior terms do not contain an entry.
+type RaftEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Term  uint64 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Entry *Entry `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+}
+
+func (x *RaftEntry) Reset() {
+	*x = RaftEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_v1_raft_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RaftEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaftEntry) ProtoMessage() {}
+
+func (x *RaftEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_raft_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaftEntry.ProtoReflect.Descriptor instead.
+func (*RaftEntry) Descriptor() ([]byte, []int) {
+	return file_order_v1_raft_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RaftEntry) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *RaftEntry) GetEntry() *Entry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+// RaftMessage is a message sent from one member of a raft cluster to another.
+type RaftMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    RaftMessageType `protobuf:"varint,1,opt,name=type,proto3,enum=order.v1.RaftMessageType" json:"type,omitempty"`
+	From    uint64          `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To      uint64          `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	Term    uint64          `protobuf:"varint,4,opt,name=term,proto3" json:"term,omitempty"`
+	Index   uint64          `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	LogTerm uint64          `protobuf:"varint,6,opt,name=log_term,json=logTerm,proto3" json:"log_term,omitempty"`
+	Entries []*RaftEntry    `protobuf:"bytes,7,rep,name=entries,proto3" json:"entries,omitempty"`
+	Commit  uint64          `protobuf:"varint,8,opt,name=commit,proto3" json:"commit,omitempty"`
+	Reject  bool            `protobuf:"varint,9,opt,name=reject,proto3" json:"reject,omitempty"`
+}
+
+func (x *RaftMessage) Reset() {
+	*x = RaftMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_v1_raft_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RaftMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaftMessage) ProtoMessage() {}
+
+func (x *RaftMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_raft_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaftMessage.ProtoReflect.Descriptor instead.
+func (*RaftMessage) Descriptor() ([]byte, []int) {
+	return file_order_v1_raft_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RaftMessage) GetType() RaftMessageType {
+	if x != nil {
+		return x.Type
+	}
+	return RaftMessageType_RAFT_MESSAGE_TYPE_UNSPECIFIED
+}
+
+func (x *RaftMessage) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetTo() uint64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetLogTerm() uint64 {
+	if x != nil {
+		return x.LogTerm
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetEntries() []*RaftEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *RaftMessage) GetCommit() uint64 {
+	if x != nil {
+		return x.Commit
+	}
+	return 0
+}
+
+func (x *RaftMessage) GetReject() bool {
+	if x != nil {
+		return x.Reject
+	}
+	return false
+}
+
+// StepRequest contains the name of the total order and the message for the
+// member of the total order hosted by the node.
+type StepRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalOrder string       `protobuf:"bytes,1,opt,name=total_order,json=totalOrder,proto3" json:"total_order,omitempty"`
+	Message    *RaftMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *StepRequest) Reset() {
+	*x = StepRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_v1_raft_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepRequest) ProtoMessage() {}
+
+func (x *StepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_raft_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepRequest.ProtoReflect.Descriptor instead.
+func (*StepRequest) Descriptor() ([]byte, []int) {
+	return file_order_v1_raft_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StepRequest) GetTotalOrder() string {
+	if x != nil {
+		return x.TotalOrder
+	}
+	return ""
+}
+
+func (x *StepRequest) GetMessage() *RaftMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// StepResponse is returned when the message has been handed to the member.
+type StepResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StepResponse) Reset() {
+	*x = StepResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_v1_raft_api_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepResponse) ProtoMessage() {}
+
+func (x *StepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_v1_raft_api_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepResponse.ProtoReflect.Descriptor instead.
+func (*StepResponse) Descriptor() ([]byte, []int) {
+	return file_order_v1_raft_api_proto_rawDescGZIP(), []int{3}
+}
+
+var File_order_v1_raft_api_proto protoreflect.FileDescriptor
+
+var file_order_v1_raft_api_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x61, 0x66, 0x74, 0x5f,
+	0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x1a, 0x18, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x5f, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x46, 0x0a,
+	0x09, 0x52, 0x61, 0x66, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x25,
+	0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05,
+	0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x84, 0x02, 0x0a, 0x0b, 0x52, 0x61, 0x66, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x61, 0x66, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x14, 0x0a, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x54, 0x65, 0x72, 0x6d, 0x12, 0x2d, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x66, 0x74, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x5f, 0x0a, 0x0b,
+	0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x66, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x0e, 0x0a,
+	0x0c, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2a, 0xc8, 0x01,
+	0x0a, 0x0f, 0x52, 0x61, 0x66, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53,
+	0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x4f, 0x54, 0x45, 0x10, 0x01,
+	0x12, 0x1f, 0x0a, 0x1b, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x4f, 0x54, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10,
+	0x02, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x50, 0x50, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a,
+	0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x41, 0x50, 0x50, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16,
+	0x52, 0x41, 0x46, 0x54, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x10, 0x05, 0x32, 0x40, 0x0a, 0x07, 0x52, 0x61, 0x66, 0x74,
+	0x41, 0x50, 0x49, 0x12, 0x35, 0x0a, 0x04, 0x53, 0x74, 0x65, 0x70, 0x12, 0x15, 0x2e, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x65, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x79, 0x6b, 0x65, 0x73, 0x6d, 0x2f,
+	0x62, 0x61, 0x74, 0x69, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x2f, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_order_v1_raft_api_proto_rawDescOnce sync.Once
+	file_order_v1_raft_api_proto_rawDescData = file_order_v1_raft_api_proto_rawDesc
+)
+
+func file_order_v1_raft_api_proto_rawDescGZIP() []byte {
+	file_order_v1_raft_api_proto_rawDescOnce.Do(func() {
+		file_order_v1_raft_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_order_v1_raft_api_proto_rawDescData)
+	})
+	return file_order_v1_raft_api_proto_rawDescData
+}
+
+var file_order_v1_raft_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_order_v1_raft_api_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_order_v1_raft_api_proto_goTypes = []interface{}{
+	(RaftMessageType)(0), // 0: order.v1.RaftMessageType
+	(*RaftEntry)(nil),    // 1: order.v1.RaftEntry
+	(*RaftMessage)(nil),  // 2: order.v1.RaftMessage
+	(*StepRequest)(nil),  // 3: order.v1.StepRequest
+	(*StepResponse)(nil), // 4: order.v1.StepResponse
+	(*Entry)(nil),        // 5: order.v1.Entry
+}
+var file_order_v1_raft_api_proto_depIdxs = []int32{
+	5, // 0: order.v1.RaftEntry.entry:type_name -> order.v1.Entry
+	0, // 1: order.v1.RaftMessage.type:type_name -> order.v1.RaftMessageType
+	1, // 2: order.v1.RaftMessage.entries:type_name -> order.v1.RaftEntry
+	2, // 3: order.v1.StepRequest.message:type_name -> order.v1.RaftMessage
+	3, // 4: order.v1.RaftAPI.Step:input_type -> order.v1.StepRequest
+	4, // 5: order.v1.RaftAPI.Step:output_type -> order.v1.StepResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_order_v1_raft_api_proto_init() }
+func file_order_v1_raft_api_proto_init() {
+	if File_order_v1_raft_api_proto != nil {
+		return
+	}
+	file_order_v1_order_api_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_order_v1_raft_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RaftEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_v1_raft_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RaftMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_v1_raft_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StepRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_v1_raft_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StepResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_order_v1_raft_api_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_order_v1_raft_api_proto_goTypes,
+		DependencyIndexes: file_order_v1_raft_api_proto_depIdxs,
+		EnumInfos:         file_order_v1_raft_api_proto_enumTypes,
+		MessageInfos:      file_order_v1_raft_api_proto_msgTypes,
+	}.Build()
+	File_order_v1_raft_api_proto = out.File
+	file_order_v1_raft_api_proto_rawDesc = nil
+	file_order_v1_raft_api_proto_goTypes = nil
+	file_order_v1_raft_api_proto_depIdxs = nil
+}
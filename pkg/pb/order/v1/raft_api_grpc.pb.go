@@ -0,0 +1,103 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package orderv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// RaftAPIClient is the client API for RaftAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RaftAPIClient interface {
+	// Step delivers a raft message to the member of a total order hosted by the
+	// node. Delivery is best effort; the message may be dropped if the member
+	// is not keeping up.
+	Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error)
+}
+
+type raftAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRaftAPIClient(cc grpc.ClientConnInterface) RaftAPIClient {
+	return &raftAPIClient{cc}
+}
+
+func (c *raftAPIClient) Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error) {
+	out := new(StepResponse)
+	err := c.cc.Invoke(ctx, "/order.v1.RaftAPI/Step", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RaftAPIServer is the server API for RaftAPI service.
+// All implementations must embed UnimplementedRaftAPIServer
+// for forward compatibility
+type RaftAPIServer interface {
+	// Step delivers a raft message to the member of a total order hosted by the
+	// node. Delivery is best effort; the message may be dropped if the member
+	// is not keeping up.
+	Step(context.Context, *StepRequest) (*StepResponse, error)
+	mustEmbedUnimplementedRaftAPIServer()
+}
+
+// UnimplementedRaftAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedRaftAPIServer struct {
+}
+
+func (UnimplementedRaftAPIServer) Step(context.Context, *StepRequest) (*StepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Step not implemented")
+}
+func (UnimplementedRaftAPIServer) mustEmbedUnimplementedRaftAPIServer() {}
+
+// UnsafeRaftAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RaftAPIServer will
+// result in compilation errors.
+type UnsafeRaftAPIServer interface {
+	mustEmbedUnimplementedRaftAPIServer()
+}
+
+func RegisterRaftAPIServer(s grpc.ServiceRegistrar, srv RaftAPIServer) {
+	s.RegisterService(&_RaftAPI_serviceDesc, srv)
+}
+
+func _RaftAPI_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftAPIServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/order.v1.RaftAPI/Step",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftAPIServer).Step(ctx, req.(*StepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RaftAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v1.RaftAPI",
+	HandlerType: (*RaftAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Step",
+			Handler:    _RaftAPI_Step_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "order/v1/raft_api.proto",
+}
@@ -29,6 +29,12 @@ type StoreAPIClient interface {
 	// a txid and output index that the State was originally created at in the
 	// transaction output list.
 	GetState(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*GetStateResponse, error)
+	// GetRejection retrieves the record of a transaction that failed commit
+	// processing.
+	GetRejection(ctx context.Context, in *GetRejectionRequest, opts ...grpc.CallOption) (*GetRejectionResponse, error)
+	// GetAttestation retrieves the node's signed attestation for the commit of
+	// a transaction.
+	GetAttestation(ctx context.Context, in *GetAttestationRequest, opts ...grpc.CallOption) (*GetAttestationResponse, error)
 	// PutState stores the encoded resolved state in the backing store.
 	// Note: This API is temporary and intended for test. DO NOT USE.
 	PutState(ctx context.Context, in *PutStateRequest, opts ...grpc.CallOption) (*PutStateResponse, error)
@@ -69,6 +75,24 @@ func (c *storeAPIClient) GetState(ctx context.Context, in *GetStateRequest, opts
 	return out, nil
 }
 
+func (c *storeAPIClient) GetRejection(ctx context.Context, in *GetRejectionRequest, opts ...grpc.CallOption) (*GetRejectionResponse, error) {
+	out := new(GetRejectionResponse)
+	err := c.cc.Invoke(ctx, "/store.v1.StoreAPI/GetRejection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeAPIClient) GetAttestation(ctx context.Context, in *GetAttestationRequest, opts ...grpc.CallOption) (*GetAttestationResponse, error) {
+	out := new(GetAttestationResponse)
+	err := c.cc.Invoke(ctx, "/store.v1.StoreAPI/GetAttestation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *storeAPIClient) PutState(ctx context.Context, in *PutStateRequest, opts ...grpc.CallOption) (*PutStateResponse, error) {
 	out := new(PutStateResponse)
 	err := c.cc.Invoke(ctx, "/store.v1.StoreAPI/PutState", in, out, opts...)
@@ -94,6 +118,12 @@ type StoreAPIServer interface {
 	// a txid and output index that the State was originally created at in the
 	// transaction output list.
 	GetState(context.Context, *GetStateRequest) (*GetStateResponse, error)
+	// GetRejection retrieves the record of a transaction that failed commit
+	// processing.
+	GetRejection(context.Context, *GetRejectionRequest) (*GetRejectionResponse, error)
+	// GetAttestation retrieves the node's signed attestation for the commit of
+	// a transaction.
+	GetAttestation(context.Context, *GetAttestationRequest) (*GetAttestationResponse, error)
 	// PutState stores the encoded resolved state in the backing store.
 	// Note: This API is temporary and intended for test. DO NOT USE.
 	PutState(context.Context, *PutStateRequest) (*PutStateResponse, error)
@@ -113,6 +143,12 @@ func (UnimplementedStoreAPIServer) PutTransaction(context.Context, *PutTransacti
 func (UnimplementedStoreAPIServer) GetState(context.Context, *GetStateRequest) (*GetStateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
 }
+func (UnimplementedStoreAPIServer) GetRejection(context.Context, *GetRejectionRequest) (*GetRejectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRejection not implemented")
+}
+func (UnimplementedStoreAPIServer) GetAttestation(context.Context, *GetAttestationRequest) (*GetAttestationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttestation not implemented")
+}
 func (UnimplementedStoreAPIServer) PutState(context.Context, *PutStateRequest) (*PutStateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutState not implemented")
 }
@@ -183,6 +219,42 @@ func _StoreAPI_GetState_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StoreAPI_GetRejection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRejectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreAPIServer).GetRejection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.v1.StoreAPI/GetRejection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreAPIServer).GetRejection(ctx, req.(*GetRejectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoreAPI_GetAttestation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAttestationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreAPIServer).GetAttestation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/store.v1.StoreAPI/GetAttestation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreAPIServer).GetAttestation(ctx, req.(*GetAttestationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StoreAPI_PutState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PutStateRequest)
 	if err := dec(in); err != nil {
@@ -217,6 +289,14 @@ var _StoreAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetState",
 			Handler:    _StoreAPI_GetState_Handler,
 		},
+		{
+			MethodName: "GetRejection",
+			Handler:    _StoreAPI_GetRejection_Handler,
+		},
+		{
+			MethodName: "GetAttestation",
+			Handler:    _StoreAPI_GetAttestation_Handler,
+		},
 		{
 			MethodName: "PutState",
 			Handler:    _StoreAPI_PutState_Handler,
@@ -0,0 +1,351 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: tx/v1/commit_api.proto
+
+package txv1
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// WatchCommitsRequest contains the namespace and the sequence number the
+// feed should start from.
+type WatchCommitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	StartSeq  uint64 `protobuf:"varint,2,opt,name=start_seq,json=startSeq,proto3" json:"start_seq,omitempty"`
+}
+
+func (x *WatchCommitsRequest) Reset() {
+	*x = WatchCommitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_commit_api_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchCommitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCommitsRequest) ProtoMessage() {}
+
+func (x *WatchCommitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_commit_api_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCommitsRequest.ProtoReflect.Descriptor instead.
+func (*WatchCommitsRequest) Descriptor() ([]byte, []int) {
+	return file_tx_v1_commit_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WatchCommitsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *WatchCommitsRequest) GetStartSeq() uint64 {
+	if x != nil {
+		return x.StartSeq
+	}
+	return 0
+}
+
+// WatchCommitsResponse contains a commit event.
+type WatchCommitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Event *CommitEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+}
+
+func (x *WatchCommitsResponse) Reset() {
+	*x = WatchCommitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_commit_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchCommitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCommitsResponse) ProtoMessage() {}
+
+func (x *WatchCommitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_commit_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCommitsResponse.ProtoReflect.Descriptor instead.
+func (*WatchCommitsResponse) Descriptor() ([]byte, []int) {
+	return file_tx_v1_commit_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchCommitsResponse) GetEvent() *CommitEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// CommitEvent describes a committed transaction. The outputs are in
+// transaction order so the output index of each created state is its
+// position in the list.
+type CommitEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txid      []byte            `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Seq       uint64            `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	ReceiptId []byte            `protobuf:"bytes,3,opt,name=receipt_id,json=receiptId,proto3" json:"receipt_id,omitempty"`
+	Consumed  []*StateReference `protobuf:"bytes,4,rep,name=consumed,proto3" json:"consumed,omitempty"`
+	Outputs   []*State          `protobuf:"bytes,5,rep,name=outputs,proto3" json:"outputs,omitempty"`
+}
+
+func (x *CommitEvent) Reset() {
+	*x = CommitEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_commit_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitEvent) ProtoMessage() {}
+
+func (x *CommitEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_commit_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitEvent.ProtoReflect.Descriptor instead.
+func (*CommitEvent) Descriptor() ([]byte, []int) {
+	return file_tx_v1_commit_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CommitEvent) GetTxid() []byte {
+	if x != nil {
+		return x.Txid
+	}
+	return nil
+}
+
+func (x *CommitEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *CommitEvent) GetReceiptId() []byte {
+	if x != nil {
+		return x.ReceiptId
+	}
+	return nil
+}
+
+func (x *CommitEvent) GetConsumed() []*StateReference {
+	if x != nil {
+		return x.Consumed
+	}
+	return nil
+}
+
+func (x *CommitEvent) GetOutputs() []*State {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+var File_tx_v1_commit_api_proto protoreflect.FileDescriptor
+
+var file_tx_v1_commit_api_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x74, 0x78, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x61,
+	0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x1a,
+	0x17, 0x74, 0x78, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x50, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x73, 0x74, 0x61, 0x72, 0x74, 0x53, 0x65, 0x71, 0x22, 0x40, 0x0a, 0x14, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xad, 0x01, 0x0a,
+	0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x74, 0x78, 0x69, 0x64,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73,
+	0x65, 0x71, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x49,
+	0x64, 0x12, 0x31, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x32, 0x56, 0x0a, 0x09,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x41, 0x50, 0x49, 0x12, 0x49, 0x0a, 0x0c, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x74, 0x78, 0x2e, 0x76,
+	0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x30, 0x01, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x73, 0x79, 0x6b, 0x65, 0x73, 0x6d, 0x2f, 0x62, 0x61, 0x74, 0x69, 0x6b, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x2f, 0x74, 0x78, 0x2f, 0x76, 0x31, 0x3b, 0x74, 0x78, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tx_v1_commit_api_proto_rawDescOnce sync.Once
+	file_tx_v1_commit_api_proto_rawDescData = file_tx_v1_commit_api_proto_rawDesc
+)
+
+func file_tx_v1_commit_api_proto_rawDescGZIP() []byte {
+	file_tx_v1_commit_api_proto_rawDescOnce.Do(func() {
+		file_tx_v1_commit_api_proto_rawDescData = protoimpl.X.CompressGZIP(file_tx_v1_commit_api_proto_rawDescData)
+	})
+	return file_tx_v1_commit_api_proto_rawDescData
+}
+
+var file_tx_v1_commit_api_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_tx_v1_commit_api_proto_goTypes = []interface{}{
+	(*WatchCommitsRequest)(nil),  // 0: tx.v1.WatchCommitsRequest
+	(*WatchCommitsResponse)(nil), // 1: tx.v1.WatchCommitsResponse
+	(*CommitEvent)(nil),          // 2: tx.v1.CommitEvent
+	(*StateReference)(nil),       // 3: tx.v1.StateReference
+	(*State)(nil),                // 4: tx.v1.State
+}
+var file_tx_v1_commit_api_proto_depIdxs = []int32{
+	2, // 0: tx.v1.WatchCommitsResponse.event:type_name -> tx.v1.CommitEvent
+	3, // 1: tx.v1.CommitEvent.consumed:type_name -> tx.v1.StateReference
+	4, // 2: tx.v1.CommitEvent.outputs:type_name -> tx.v1.State
+	0, // 3: tx.v1.CommitAPI.WatchCommits:input_type -> tx.v1.WatchCommitsRequest
+	1, // 4: tx.v1.CommitAPI.WatchCommits:output_type -> tx.v1.WatchCommitsResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_tx_v1_commit_api_proto_init() }
+func file_tx_v1_commit_api_proto_init() {
+	if File_tx_v1_commit_api_proto != nil {
+		return
+	}
+	file_tx_v1_transaction_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_tx_v1_commit_api_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchCommitsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_commit_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchCommitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_commit_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tx_v1_commit_api_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tx_v1_commit_api_proto_goTypes,
+		DependencyIndexes: file_tx_v1_commit_api_proto_depIdxs,
+		MessageInfos:      file_tx_v1_commit_api_proto_msgTypes,
+	}.Build()
+	File_tx_v1_commit_api_proto = out.File
+	file_tx_v1_commit_api_proto_rawDesc = nil
+	file_tx_v1_commit_api_proto_goTypes = nil
+	file_tx_v1_commit_api_proto_depIdxs = nil
+}
@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package txv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// CommitAPIClient is the client API for CommitAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CommitAPIClient interface {
+	// WatchCommits streams an event for each transaction committed at or after
+	// the requested sequence number. Transactions that have already been
+	// committed are replayed before events for new commits are streamed.
+	WatchCommits(ctx context.Context, in *WatchCommitsRequest, opts ...grpc.CallOption) (CommitAPI_WatchCommitsClient, error)
+}
+
+type commitAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommitAPIClient(cc grpc.ClientConnInterface) CommitAPIClient {
+	return &commitAPIClient{cc}
+}
+
+func (c *commitAPIClient) WatchCommits(ctx context.Context, in *WatchCommitsRequest, opts ...grpc.CallOption) (CommitAPI_WatchCommitsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CommitAPI_serviceDesc.Streams[0], "/tx.v1.CommitAPI/WatchCommits", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commitAPIWatchCommitsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommitAPI_WatchCommitsClient interface {
+	Recv() (*WatchCommitsResponse, error)
+	grpc.ClientStream
+}
+
+type commitAPIWatchCommitsClient struct {
+	grpc.ClientStream
+}
+
+func (x *commitAPIWatchCommitsClient) Recv() (*WatchCommitsResponse, error) {
+	m := new(WatchCommitsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CommitAPIServer is the server API for CommitAPI service.
+// All implementations must embed UnimplementedCommitAPIServer
+// for forward compatibility
+type CommitAPIServer interface {
+	// WatchCommits streams an event for each transaction committed at or after
+	// the requested sequence number. Transactions that have already been
+	// committed are replayed before events for new commits are streamed.
+	WatchCommits(*WatchCommitsRequest, CommitAPI_WatchCommitsServer) error
+	mustEmbedUnimplementedCommitAPIServer()
+}
+
+// UnimplementedCommitAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedCommitAPIServer struct {
+}
+
+func (UnimplementedCommitAPIServer) WatchCommits(*WatchCommitsRequest, CommitAPI_WatchCommitsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCommits not implemented")
+}
+func (UnimplementedCommitAPIServer) mustEmbedUnimplementedCommitAPIServer() {}
+
+// UnsafeCommitAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommitAPIServer will
+// result in compilation errors.
+type UnsafeCommitAPIServer interface {
+	mustEmbedUnimplementedCommitAPIServer()
+}
+
+func RegisterCommitAPIServer(s grpc.ServiceRegistrar, srv CommitAPIServer) {
+	s.RegisterService(&_CommitAPI_serviceDesc, srv)
+}
+
+func _CommitAPI_WatchCommits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCommitsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommitAPIServer).WatchCommits(m, &commitAPIWatchCommitsServer{stream})
+}
+
+type CommitAPI_WatchCommitsServer interface {
+	Send(*WatchCommitsResponse) error
+	grpc.ServerStream
+}
+
+type commitAPIWatchCommitsServer struct {
+	grpc.ServerStream
+}
+
+func (x *commitAPIWatchCommitsServer) Send(m *WatchCommitsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _CommitAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tx.v1.CommitAPI",
+	HandlerType: (*CommitAPIServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCommits",
+			Handler:       _CommitAPI_WatchCommits_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tx/v1/commit_api.proto",
+}
@@ -0,0 +1,99 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package txv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// StatusAPIClient is the client API for StatusAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StatusAPIClient interface {
+	// GetStatus retrieves the commit processing status of a transaction.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+}
+
+type statusAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStatusAPIClient(cc grpc.ClientConnInterface) StatusAPIClient {
+	return &statusAPIClient{cc}
+}
+
+func (c *statusAPIClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, "/tx.v1.StatusAPI/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StatusAPIServer is the server API for StatusAPI service.
+// All implementations must embed UnimplementedStatusAPIServer
+// for forward compatibility
+type StatusAPIServer interface {
+	// GetStatus retrieves the commit processing status of a transaction.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	mustEmbedUnimplementedStatusAPIServer()
+}
+
+// UnimplementedStatusAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedStatusAPIServer struct {
+}
+
+func (UnimplementedStatusAPIServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedStatusAPIServer) mustEmbedUnimplementedStatusAPIServer() {}
+
+// UnsafeStatusAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StatusAPIServer will
+// result in compilation errors.
+type UnsafeStatusAPIServer interface {
+	mustEmbedUnimplementedStatusAPIServer()
+}
+
+func RegisterStatusAPIServer(s grpc.ServiceRegistrar, srv StatusAPIServer) {
+	s.RegisterService(&_StatusAPI_serviceDesc, srv)
+}
+
+func _StatusAPI_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatusAPIServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tx.v1.StatusAPI/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatusAPIServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _StatusAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tx.v1.StatusAPI",
+	HandlerType: (*StatusAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _StatusAPI_GetStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tx/v1/status_api.proto",
+}
@@ -134,6 +134,323 @@ func (x *SubmitResponse) GetTxid() []byte {
 	return nil
 }
 
+// SimulateRequest contains the transaction to simulate.
+type SimulateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Namespace         string             `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	SignedTransaction *SignedTransaction `protobuf:"bytes,2,opt,name=signed_transaction,json=signedTransaction,proto3" json:"signed_transaction,omitempty"`
+}
+
+func (x *SimulateRequest) Reset() {
+	*x = SimulateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_submit_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateRequest) ProtoMessage() {}
+
+func (x *SimulateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_submit_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateRequest.ProtoReflect.Descriptor instead.
+func (*SimulateRequest) Descriptor() ([]byte, []int) {
+	return file_tx_v1_submit_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SimulateRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SimulateRequest) GetSignedTransaction() *SignedTransaction {
+	if x != nil {
+		return x.SignedTransaction
+	}
+	return nil
+}
+
+// SimulateResponse contains the outcome of a simulation. The validation
+// result is only provided when every input and reference of the transaction
+// was resolved; the states that could not be resolved are listed in missing.
+type SimulateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Txid       []byte               `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Validation *SimulatedValidation `protobuf:"bytes,2,opt,name=validation,proto3" json:"validation,omitempty"`
+	Inputs     []*SimulatedState    `protobuf:"bytes,3,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	References []*SimulatedState    `protobuf:"bytes,4,rep,name=references,proto3" json:"references,omitempty"`
+	Missing    []*MissingState      `protobuf:"bytes,5,rep,name=missing,proto3" json:"missing,omitempty"`
+}
+
+func (x *SimulateResponse) Reset() {
+	*x = SimulateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_submit_api_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateResponse) ProtoMessage() {}
+
+func (x *SimulateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_submit_api_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateResponse.ProtoReflect.Descriptor instead.
+func (*SimulateResponse) Descriptor() ([]byte, []int) {
+	return file_tx_v1_submit_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SimulateResponse) GetTxid() []byte {
+	if x != nil {
+		return x.Txid
+	}
+	return nil
+}
+
+func (x *SimulateResponse) GetValidation() *SimulatedValidation {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
+func (x *SimulateResponse) GetInputs() []*SimulatedState {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *SimulateResponse) GetReferences() []*SimulatedState {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *SimulateResponse) GetMissing() []*MissingState {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+// SimulatedValidation is the response from the namespace validator.
+type SimulatedValidation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid        bool   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	ErrorMessage string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+}
+
+func (x *SimulatedValidation) Reset() {
+	*x = SimulatedValidation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_submit_api_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulatedValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulatedValidation) ProtoMessage() {}
+
+func (x *SimulatedValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_submit_api_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulatedValidation.ProtoReflect.Descriptor instead.
+func (*SimulatedValidation) Descriptor() ([]byte, []int) {
+	return file_tx_v1_submit_api_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SimulatedValidation) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *SimulatedValidation) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// SimulatedState binds a state reference to the state it resolved to.
+type SimulatedState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reference *StateReference `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	State     *State          `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *SimulatedState) Reset() {
+	*x = SimulatedState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_submit_api_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimulatedState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulatedState) ProtoMessage() {}
+
+func (x *SimulatedState) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_submit_api_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulatedState.ProtoReflect.Descriptor instead.
+func (*SimulatedState) Descriptor() ([]byte, []int) {
+	return file_tx_v1_submit_api_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SimulatedState) GetReference() *StateReference {
+	if x != nil {
+		return x.Reference
+	}
+	return nil
+}
+
+func (x *SimulatedState) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+// MissingState identifies a state that could not be resolved. The reason is
+// STATE_CONSUMED for states that have been consumed and STATE_UNKNOWN for
+// states that do not exist. The ID of the consuming transaction is provided
+// for consumed states when it is known.
+type MissingState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reference  *StateReference `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	Reason     string          `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	ConsumedBy []byte          `protobuf:"bytes,3,opt,name=consumed_by,json=consumedBy,proto3" json:"consumed_by,omitempty"`
+}
+
+func (x *MissingState) Reset() {
+	*x = MissingState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_v1_submit_api_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MissingState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MissingState) ProtoMessage() {}
+
+func (x *MissingState) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_v1_submit_api_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MissingState.ProtoReflect.Descriptor instead.
+func (*MissingState) Descriptor() ([]byte, []int) {
+	return file_tx_v1_submit_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MissingState) GetReference() *StateReference {
+	if x != nil {
+		return x.Reference
+	}
+	return nil
+}
+
+func (x *MissingState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MissingState) GetConsumedBy() []byte {
+	if x != nil {
+		return x.ConsumedBy
+	}
+	return nil
+}
+
 var File_tx_v1_submit_api_proto protoreflect.FileDescriptor
 
 var file_tx_v1_submit_api_proto_rawDesc = []byte{
@@ -152,16 +469,66 @@ var file_tx_v1_submit_api_proto_rawDesc = []byte{
 	0x6e, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x24,
 	0x0a, 0x0e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
 	0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
-	0x74, 0x78, 0x69, 0x64, 0x32, 0x76, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x50,
+	0x74, 0x78, 0x69, 0x64, 0x22, 0x78, 0x0a, 0x0f, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x12, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xf7,
+	0x01, 0x0a, 0x10, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x74, 0x78, 0x69, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x78,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75,
+	0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75,
+	0x74, 0x73, 0x12, 0x35, 0x0a, 0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x78, 0x2e,
+	0x76, 0x31, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22, 0x50, 0x0a, 0x13, 0x53, 0x69, 0x6d, 0x75,
+	0x6c, 0x61, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x69, 0x0a, 0x0e, 0x53, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x33, 0x0a, 0x09,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x7c, 0x0a, 0x0c, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52,
+	0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x5f, 0x62,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65,
+	0x64, 0x42, 0x79, 0x32, 0xe9, 0x01, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x50,
 	0x49, 0x12, 0x69, 0x0a, 0x06, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x2e, 0x74, 0x78,
 	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x1a, 0x15, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x32, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2c,
 	0x22, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x2f, 0x7b, 0x6e, 0x61,
 	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x7d, 0x3a, 0x12, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64,
-	0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x2e, 0x5a, 0x2c,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x69, 0x62, 0x6d, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62,
-	0x61, 0x74, 0x69, 0x6b, 0x2f, 0x62, 0x61, 0x74, 0x69, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70,
+	0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x71, 0x0a, 0x08,
+	0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x17, 0x2e, 0x74, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x34, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x2e, 0x22, 0x18, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x2f,
+	0x7b, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x7d, 0x3a, 0x12, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x79,
+	0x6b, 0x65, 0x73, 0x6d, 0x2f, 0x62, 0x61, 0x74, 0x69, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70,
 	0x62, 0x2f, 0x74, 0x78, 0x2f, 0x76, 0x31, 0x3b, 0x74, 0x78, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x33,
 }
@@ -178,21 +545,38 @@ func file_tx_v1_submit_api_proto_rawDescGZIP() []byte {
 	return file_tx_v1_submit_api_proto_rawDescData
 }
 
-var file_tx_v1_submit_api_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_tx_v1_submit_api_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_tx_v1_submit_api_proto_goTypes = []interface{}{
-	(*SubmitRequest)(nil),     // 0: tx.v1.SubmitRequest
-	(*SubmitResponse)(nil),    // 1: tx.v1.SubmitResponse
-	(*SignedTransaction)(nil), // 2: tx.v1.SignedTransaction
+	(*SubmitRequest)(nil),       // 0: tx.v1.SubmitRequest
+	(*SubmitResponse)(nil),      // 1: tx.v1.SubmitResponse
+	(*SimulateRequest)(nil),     // 2: tx.v1.SimulateRequest
+	(*SimulateResponse)(nil),    // 3: tx.v1.SimulateResponse
+	(*SimulatedValidation)(nil), // 4: tx.v1.SimulatedValidation
+	(*SimulatedState)(nil),      // 5: tx.v1.SimulatedState
+	(*MissingState)(nil),        // 6: tx.v1.MissingState
+	(*SignedTransaction)(nil),   // 7: tx.v1.SignedTransaction
+	(*StateReference)(nil),      // 8: tx.v1.StateReference
+	(*State)(nil),               // 9: tx.v1.State
 }
 var file_tx_v1_submit_api_proto_depIdxs = []int32{
-	2, // 0: tx.v1.SubmitRequest.signed_transaction:type_name -> tx.v1.SignedTransaction
-	0, // 1: tx.v1.SubmitAPI.Submit:input_type -> tx.v1.SubmitRequest
-	1, // 2: tx.v1.SubmitAPI.Submit:output_type -> tx.v1.SubmitResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	7,  // 0: tx.v1.SubmitRequest.signed_transaction:type_name -> tx.v1.SignedTransaction
+	7,  // 1: tx.v1.SimulateRequest.signed_transaction:type_name -> tx.v1.SignedTransaction
+	4,  // 2: tx.v1.SimulateResponse.validation:type_name -> tx.v1.SimulatedValidation
+	5,  // 3: tx.v1.SimulateResponse.inputs:type_name -> tx.v1.SimulatedState
+	5,  // 4: tx.v1.SimulateResponse.references:type_name -> tx.v1.SimulatedState
+	6,  // 5: tx.v1.SimulateResponse.missing:type_name -> tx.v1.MissingState
+	8,  // 6: tx.v1.SimulatedState.reference:type_name -> tx.v1.StateReference
+	9,  // 7: tx.v1.SimulatedState.state:type_name -> tx.v1.State
+	8,  // 8: tx.v1.MissingState.reference:type_name -> tx.v1.StateReference
+	0,  // 9: tx.v1.SubmitAPI.Submit:input_type -> tx.v1.SubmitRequest
+	2,  // 10: tx.v1.SubmitAPI.Simulate:input_type -> tx.v1.SimulateRequest
+	1,  // 11: tx.v1.SubmitAPI.Submit:output_type -> tx.v1.SubmitResponse
+	3,  // 12: tx.v1.SubmitAPI.Simulate:output_type -> tx.v1.SimulateResponse
+	11, // [11:13] is the sub-list for method output_type
+	9,  // [9:11] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_tx_v1_submit_api_proto_init() }
@@ -226,6 +610,66 @@ func file_tx_v1_submit_api_proto_init() {
 				return nil
 			}
 		}
+		file_tx_v1_submit_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimulateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_submit_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimulateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_submit_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimulatedValidation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_submit_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimulatedState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tx_v1_submit_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MissingState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -233,7 +677,7 @@ func file_tx_v1_submit_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tx_v1_submit_api_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
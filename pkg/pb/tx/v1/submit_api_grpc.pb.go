@@ -20,6 +20,9 @@ type SubmitAPIClient interface {
 	// Submit submits a transaction for validation and commit processing.
 	// NOTE: This is an implementation for prototyping.
 	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// Simulate resolves and validates a transaction against the current state
+	// without committing it. The transaction is neither stored nor ordered.
+	Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (*SimulateResponse, error)
 }
 
 type submitAPIClient struct {
@@ -39,6 +42,15 @@ func (c *submitAPIClient) Submit(ctx context.Context, in *SubmitRequest, opts ..
 	return out, nil
 }
 
+func (c *submitAPIClient) Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (*SimulateResponse, error) {
+	out := new(SimulateResponse)
+	err := c.cc.Invoke(ctx, "/tx.v1.SubmitAPI/Simulate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubmitAPIServer is the server API for SubmitAPI service.
 // All implementations must embed UnimplementedSubmitAPIServer
 // for forward compatibility
@@ -46,6 +58,9 @@ type SubmitAPIServer interface {
 	// Submit submits a transaction for validation and commit processing.
 	// NOTE: This is an implementation for prototyping.
 	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	// Simulate resolves and validates a transaction against the current state
+	// without committing it. The transaction is neither stored nor ordered.
+	Simulate(context.Context, *SimulateRequest) (*SimulateResponse, error)
 	mustEmbedUnimplementedSubmitAPIServer()
 }
 
@@ -56,6 +71,9 @@ type UnimplementedSubmitAPIServer struct {
 func (UnimplementedSubmitAPIServer) Submit(context.Context, *SubmitRequest) (*SubmitResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
 }
+func (UnimplementedSubmitAPIServer) Simulate(context.Context, *SimulateRequest) (*SimulateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Simulate not implemented")
+}
 func (UnimplementedSubmitAPIServer) mustEmbedUnimplementedSubmitAPIServer() {}
 
 // UnsafeSubmitAPIServer may be embedded to opt out of forward compatibility for this service.
@@ -87,6 +105,24 @@ func _SubmitAPI_Submit_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SubmitAPI_Simulate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubmitAPIServer).Simulate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tx.v1.SubmitAPI/Simulate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubmitAPIServer).Simulate(ctx, req.(*SimulateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SubmitAPI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tx.v1.SubmitAPI",
 	HandlerType: (*SubmitAPIServer)(nil),
@@ -95,6 +131,10 @@ var _SubmitAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Submit",
 			Handler:    _SubmitAPI_Submit_Handler,
 		},
+		{
+			MethodName: "Simulate",
+			Handler:    _SubmitAPI_Simulate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "tx/v1/submit_api.proto",
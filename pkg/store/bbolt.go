@@ -0,0 +1,401 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	errBoltNotFound = errors.New("bbolt: not found")
+	errBoltReleased = errors.New("bbolt: snapshot released")
+
+	// boltBucket is the bucket that holds all of the keys in the database.
+	boltBucket = []byte("batik")
+)
+
+const (
+	// boltFile is the name of the database file in the data directory.
+	boltFile = "batik.db"
+
+	// boltChunkSize is the maximum number of pairs an iterator reads in a
+	// single read transaction.
+	boltChunkSize = 256
+
+	// boltInitialMmapSize is the size of the initial memory map of the
+	// database. The map can only grow when no read transactions are open so
+	// it is made large enough that writes rarely wait on open snapshots.
+	boltInitialMmapSize = 1 << 30
+)
+
+var _ Iterator = (*boltIterator)(nil)
+
+type boltEntry struct {
+	key   []byte
+	value []byte
+}
+
+// boltIterator reads pairs in chunks, each in its own read transaction, so
+// that an open iterator does not prevent the database from growing. Pairs
+// written after a chunk has been read may or may not be visited. Iterators
+// created from a snapshot read every chunk from the snapshot's transaction.
+type boltIterator struct {
+	view       func(func(*bolt.Tx) error) error
+	start, end []byte
+	reverse    bool
+	limit      int
+	count      int
+
+	chunk []boltEntry
+	pos   int
+	last  []byte // last is the key of the last pair read
+	done  bool   // done is set when there are no pairs left to read
+	err   error
+}
+
+func (i *boltIterator) Next() bool {
+	if i.err != nil || (i.limit > 0 && i.count >= i.limit) {
+		return false
+	}
+
+	i.pos++
+	if i.pos >= len(i.chunk) {
+		if i.done {
+			return false
+		}
+		i.load()
+		if i.err != nil || len(i.chunk) == 0 {
+			return false
+		}
+	}
+	i.count++
+
+	return true
+}
+
+// load reads the next chunk of pairs that follow the last pair read.
+func (i *boltIterator) load() {
+	size := boltChunkSize
+	if i.limit > 0 && i.limit-i.count < size {
+		size = i.limit - i.count
+	}
+
+	i.chunk, i.pos = i.chunk[:0], 0
+	i.err = i.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+
+		var k, v []byte
+		switch {
+		case i.reverse:
+			k, v = i.seekBefore(c)
+		case i.last != nil:
+			k, v = c.Seek(i.last)
+			if bytes.Equal(k, i.last) {
+				k, v = c.Next()
+			}
+		case len(i.start) == 0:
+			k, v = c.First()
+		default:
+			k, v = c.Seek(i.start)
+		}
+
+		for ; k != nil && len(i.chunk) < size; k, v = i.advance(c) {
+			if !i.reverse && i.end != nil && bytes.Compare(k, i.end) >= 0 {
+				break
+			}
+			if i.reverse && bytes.Compare(k, i.start) < 0 {
+				break
+			}
+			i.chunk = append(i.chunk, boltEntry{key: clone(k), value: clone(v)})
+		}
+		if len(i.chunk) < size {
+			i.done = true
+		}
+
+		return nil
+	})
+	if len(i.chunk) != 0 {
+		i.last = i.chunk[len(i.chunk)-1].key
+	}
+}
+
+// seekBefore positions a reverse iterator's cursor at the largest key that
+// is less than the last key read or, before any key has been read, less than
+// the upper bound.
+func (i *boltIterator) seekBefore(c *bolt.Cursor) ([]byte, []byte) {
+	bound := i.end
+	if i.last != nil {
+		bound = i.last
+	}
+	if bound == nil {
+		return c.Last()
+	}
+	if k, _ := c.Seek(bound); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+func (i *boltIterator) advance(c *bolt.Cursor) ([]byte, []byte) {
+	if i.reverse {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+func (i *boltIterator) Key() []byte {
+	if i.pos < 0 || i.pos >= len(i.chunk) {
+		return nil
+	}
+	return i.chunk[i.pos].key
+}
+
+func (i *boltIterator) Value() []byte {
+	if i.pos < 0 || i.pos >= len(i.chunk) {
+		return nil
+	}
+	return i.chunk[i.pos].value
+}
+
+func (i *boltIterator) Error() error { return i.err }
+
+func (i *boltIterator) Release() {
+	i.chunk, i.done = nil, true
+}
+
+var _ WriteBatch = (*boltWriteBatch)(nil)
+
+type boltWriteBatch struct {
+	ops []writeOp
+	kv  *BoltKV
+}
+
+func (b *boltWriteBatch) Put(key, value []byte) error {
+	b.ops = append(b.ops, writeOp{key: clone(key), value: clone(value)})
+	return nil
+}
+
+func (b *boltWriteBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, writeOp{key: clone(key), delete: true})
+	return nil
+}
+
+func (b *boltWriteBatch) Commit() error {
+	return b.kv.commitWriteBatch(b)
+}
+
+func (b *boltWriteBatch) Clear() {
+	b.ops = nil
+}
+
+func (b *boltWriteBatch) Count() int {
+	return len(b.ops)
+}
+
+var _ Snapshot = (*boltSnapshot)(nil)
+
+// boltSnapshot is a view of the database at the start of a read transaction.
+// A bbolt transaction must not be used concurrently so access to it is
+// serialized.
+type boltSnapshot struct {
+	mutex sync.Mutex
+	tx    *bolt.Tx
+}
+
+// view calls fn with the snapshot's transaction.
+func (s *boltSnapshot) view(fn func(*bolt.Tx) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.tx == nil {
+		return errBoltReleased
+	}
+	return fn(s.tx)
+}
+
+func (s *boltSnapshot) Get(key []byte) ([]byte, error) {
+	return boltGet(s.view, key)
+}
+
+func (s *boltSnapshot) MultiGet(keys ...[]byte) ([][]byte, error) {
+	return boltMultiGet(s.view, keys...)
+}
+
+func (s *boltSnapshot) NewIterator(opts *IterateOptions) Iterator {
+	return newBoltIterator(s.view, opts)
+}
+
+func (s *boltSnapshot) Release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.tx != nil {
+		s.tx.Rollback()
+		s.tx = nil
+	}
+}
+
+var (
+	_ KV          = (*BoltKV)(nil)
+	_ MultiGetter = (*BoltKV)(nil)
+)
+
+// BoltKV is a key value store backed by a bbolt database file. Every write
+// is performed in its own transaction and write batches are committed
+// atomically.
+type BoltKV struct {
+	dir string
+	db  *bolt.DB
+}
+
+func (b *BoltKV) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltKV) Get(key []byte) ([]byte, error) {
+	return boltGet(b.db.View, key)
+}
+
+func (b *BoltKV) MultiGet(keys ...[]byte) ([][]byte, error) {
+	return boltMultiGet(b.db.View, keys...)
+}
+
+func (b *BoltKV) Put(key, value []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(key, value)
+	})
+}
+
+func (b *BoltKV) Delete(key []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(key)
+	})
+}
+
+func (b *BoltKV) NewWriteBatch() WriteBatch {
+	return &boltWriteBatch{kv: b}
+}
+
+// NewIterator returns an iterator over the key/value pairs within the bounds
+// of opts. A nil opts iterates over all pairs in the DB.
+func (b *BoltKV) NewIterator(opts *IterateOptions) Iterator {
+	return newBoltIterator(b.db.View, opts)
+}
+
+// NewSnapshot returns a view of the database backed by a read transaction
+// that is held open until the snapshot is released. A write that grows the
+// database beyond its memory map waits for open read transactions to finish
+// so snapshots should be short lived.
+func (b *BoltKV) NewSnapshot() (Snapshot, error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSnapshot{tx: tx}, nil
+}
+
+func newBoltIterator(view func(func(*bolt.Tx) error) error, opts *IterateOptions) Iterator {
+	start, end := opts.bounds()
+
+	return &boltIterator{
+		view:    view,
+		start:   start,
+		end:     end,
+		reverse: opts.reverse(),
+		limit:   opts.limit(),
+		pos:     -1,
+	}
+}
+
+func (b *BoltKV) commitWriteBatch(wb *boltWriteBatch) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, op := range wb.ops {
+			if op.delete {
+				if err := bucket.Delete(op.key); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put(op.key, op.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func boltGet(view func(func(*bolt.Tx) error) error, key []byte) ([]byte, error) {
+	var value []byte
+	err := view(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltBucket).Get(key)
+		if v == nil {
+			return notFound(errBoltNotFound)
+		}
+		value = clone(v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func boltMultiGet(view func(func(*bolt.Tx) error) error, keys ...[]byte) ([][]byte, error) {
+	bufs := make([][]byte, len(keys))
+	err := view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for i := range keys {
+			v := bucket.Get(keys[i])
+			if v == nil {
+				return notFound(errBoltNotFound)
+			}
+			bufs[i] = clone(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bufs, nil
+}
+
+// NewBoltDB opens the bbolt database in dir, creating the directory and the
+// database when they do not exist.
+func NewBoltDB(dir string) (*BoltKV, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("failed to init bbolt: a data directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to init bbolt")
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, boltFile), 0o600, &bolt.Options{Timeout: time.Second, InitialMmapSize: boltInitialMmapSize})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init bbolt")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to init bbolt")
+	}
+
+	return &BoltKV{
+		dir: dir,
+		db:  db,
+	}, nil
+}
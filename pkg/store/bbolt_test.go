@@ -0,0 +1,56 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func TestBoltDB(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	path, cleanup := tested.TempDir(t, "", "bolt")
+	defer cleanup()
+
+	dir := filepath.Join(path, "nested", "dir")
+	db, err := NewBoltDB(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(filepath.Join(dir, "batik.db")).To(BeARegularFile())
+
+	gt.Expect(db.Put([]byte("exist"), []byte("value"))).To(Succeed())
+	wb := db.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("key_batch1"), []byte("val_batch1"))).To(Succeed())
+	gt.Expect(wb.Put([]byte("key_batch2"), []byte("val_batch2"))).To(Succeed())
+	gt.Expect(wb.Commit()).To(Succeed())
+	uncommitted := db.NewWriteBatch()
+	gt.Expect(uncommitted.Put([]byte("key_batch3"), []byte("val_batch3"))).To(Succeed())
+	gt.Expect(db.Close()).To(Succeed())
+
+	db, err = NewBoltDB(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	v, err := db.Get([]byte("exist"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("value")))
+
+	mv, err := db.MultiGet([]byte("key_batch1"), []byte("key_batch2"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mv).To(Equal([][]byte{[]byte("val_batch1"), []byte("val_batch2")}))
+
+	_, err = db.Get([]byte("key_batch3"))
+	gt.Expect(err).To(MatchError("bbolt: not found"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func TestBoltDBRequiresDir(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	_, err := NewBoltDB("")
+	gt.Expect(err).To(MatchError("failed to init bbolt: a data directory is required"))
+}
@@ -0,0 +1,389 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+// conformingKV is implemented by every KV backend.
+type conformingKV interface {
+	KV
+	MultiGetter
+}
+
+// backends creates an empty instance of each KV backend. The instances are
+// closed when the test completes.
+var backends = map[string]func(t *testing.T) conformingKV{
+	"LevelDB": func(t *testing.T) conformingKV {
+		path, cleanup := tested.TempDir(t, "", "level")
+		t.Cleanup(cleanup)
+
+		db, err := NewLevelDB(path)
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		t.Cleanup(func() { tested.Close(t, db) })
+		return db
+	},
+	"Bolt": func(t *testing.T) conformingKV {
+		path, cleanup := tested.TempDir(t, "", "bolt")
+		t.Cleanup(cleanup)
+
+		db, err := NewBoltDB(path)
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		t.Cleanup(func() { tested.Close(t, db) })
+		return db
+	},
+	"Memory": func(t *testing.T) conformingKV {
+		db := NewMemoryKV()
+		t.Cleanup(func() { tested.Close(t, db) })
+		return db
+	},
+}
+
+func TestKVConformance(t *testing.T) {
+	tests := map[string]func(*testing.T, conformingKV){
+		"GetPutDelete": testKVGetPutDelete,
+		"MultiGet":     testKVMultiGet,
+		"WriteBatch":   testKVWriteBatch,
+		"Iterator":     testKVIterator,
+		"IterateRange": testKVIterateRange,
+		"IterateLarge": testKVIterateLarge,
+		"Concurrency":  testKVConcurrency,
+		"Snapshot":     testKVSnapshot,
+	}
+
+	for backend, newKV := range backends {
+		for name, tt := range tests {
+			backend, newKV, tt := backend, newKV, tt
+			t.Run(backend+"/"+name, func(t *testing.T) {
+				tt(t, newKV(t))
+			})
+		}
+	}
+}
+
+func TestRepositoryConformance(t *testing.T) {
+	tests := map[string]func(*testing.T, *TransactionRepository){
+		"Receipts":          testStoreReceipts,
+		"Commits":           testStoreCommits,
+		"Rejections":        testStoreRejections,
+		"Transaction":       testStoreTransaction,
+		"State":             testStoreState,
+		"CommitTransaction": testStoreCommitTransaction,
+		"View":              testStoreView,
+		"Indexes":           testStoreIndexes,
+	}
+
+	for backend, newKV := range backends {
+		for name, tt := range tests {
+			backend, newKV, tt := backend, newKV, tt
+			t.Run(backend+"/"+name, func(t *testing.T) {
+				tt(t, NewRepository(newKV(t)))
+			})
+		}
+	}
+}
+
+func testKVGetPutDelete(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	_, err := kv.Get([]byte("missing"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	gt.Expect(kv.Put([]byte("empty"), []byte{})).To(Succeed())
+	v, err := kv.Get([]byte("empty"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte{}))
+
+	value := []byte("value")
+	gt.Expect(kv.Put([]byte("key"), value)).To(Succeed())
+	value[0] = 'V'
+	v, err = kv.Get([]byte("key"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("value")))
+
+	gt.Expect(kv.Put([]byte("key"), []byte("replaced"))).To(Succeed())
+	v, err = kv.Get([]byte("key"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("replaced")))
+
+	gt.Expect(kv.Delete([]byte("key"))).To(Succeed())
+	_, err = kv.Get([]byte("key"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	gt.Expect(kv.Delete([]byte("missing"))).To(Succeed())
+}
+
+func testKVMultiGet(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(kv.Put([]byte("key1"), []byte("val1"))).To(Succeed())
+	gt.Expect(kv.Put([]byte("key2"), []byte("val2"))).To(Succeed())
+
+	vals, err := kv.MultiGet([]byte("key2"), []byte("key1"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(vals).To(Equal([][]byte{[]byte("val2"), []byte("val1")}))
+
+	_, err = kv.MultiGet([]byte("key1"), []byte("missing"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func testKVWriteBatch(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(kv.Put([]byte("deleted"), []byte("value"))).To(Succeed())
+
+	wb := kv.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("key1"), []byte("val1"))).To(Succeed())
+	gt.Expect(wb.Put([]byte("key2"), []byte("val2"))).To(Succeed())
+	gt.Expect(wb.Delete([]byte("deleted"))).To(Succeed())
+	gt.Expect(wb.Count()).To(Equal(3))
+
+	// Nothing is visible until the batch is committed.
+	_, err := kv.Get([]byte("key1"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = kv.Get([]byte("deleted"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(wb.Commit()).To(Succeed())
+	vals, err := kv.MultiGet([]byte("key1"), []byte("key2"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(vals).To(Equal([][]byte{[]byte("val1"), []byte("val2")}))
+	_, err = kv.Get([]byte("deleted"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	// Operations are applied in order.
+	wb = kv.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("key3"), []byte("first"))).To(Succeed())
+	gt.Expect(wb.Delete([]byte("key3"))).To(Succeed())
+	gt.Expect(wb.Put([]byte("key4"), []byte("first"))).To(Succeed())
+	gt.Expect(wb.Put([]byte("key4"), []byte("second"))).To(Succeed())
+	gt.Expect(wb.Commit()).To(Succeed())
+	_, err = kv.Get([]byte("key3"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	v, err := kv.Get([]byte("key4"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("second")))
+
+	// Cleared operations are not committed.
+	wb = kv.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("cleared"), []byte("value"))).To(Succeed())
+	wb.Clear()
+	gt.Expect(wb.Count()).To(Equal(0))
+	gt.Expect(wb.Commit()).To(Succeed())
+	_, err = kv.Get([]byte("cleared"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func testKVIterator(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	keys, err := Keys(kv.NewIterator(nil))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(BeEmpty())
+
+	for _, k := range []string{"b.b", "a.b", "c", "a.a", "a", "b.a"} {
+		gt.Expect(kv.Put([]byte(k), []byte("value-"+k))).To(Succeed())
+	}
+
+	keys, err = Keys(kv.NewIterator(nil))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(Equal([]Key{Key("a"), Key("a.a"), Key("a.b"), Key("b.a"), Key("b.b"), Key("c")}))
+
+	iter := kv.NewIterator(&IterateOptions{Prefix: []byte("b.")})
+	var pairs []string
+	for iter.Next() {
+		pairs = append(pairs, string(iter.Key())+"="+string(iter.Value()))
+	}
+	gt.Expect(iter.Error()).NotTo(HaveOccurred())
+	iter.Release()
+	gt.Expect(pairs).To(Equal([]string{"b.a=value-b.a", "b.b=value-b.b"}))
+
+	keys, err = Keys(kv.NewIterator(&IterateOptions{Prefix: []byte("d")}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(BeEmpty())
+
+	// Returned keys can be modified without affecting the store.
+	keys, err = Keys(kv.NewIterator(&IterateOptions{Prefix: []byte("c")}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	keys[0][0] = 'd'
+	_, err = kv.Get([]byte("c"))
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func testKVIterateRange(t *testing.T, kv conformingKV) {
+	for _, k := range []string{"a", "a.a", "a.b", "a.c", "b", "b.a", "c", "\xff", "\xff\xff"} {
+		NewGomegaWithT(t).Expect(kv.Put([]byte(k), []byte{})).To(Succeed())
+	}
+
+	tests := map[string]struct {
+		opts     *IterateOptions
+		expected []string
+	}{
+		"all":                {opts: &IterateOptions{}, expected: []string{"a", "a.a", "a.b", "a.c", "b", "b.a", "c", "\xff", "\xff\xff"}},
+		"start":              {opts: &IterateOptions{Start: []byte("a.b")}, expected: []string{"a.b", "a.c", "b", "b.a", "c", "\xff", "\xff\xff"}},
+		"end":                {opts: &IterateOptions{End: []byte("a.c")}, expected: []string{"a", "a.a", "a.b"}},
+		"start and end":      {opts: &IterateOptions{Start: []byte("a.a"), End: []byte("b.a")}, expected: []string{"a.a", "a.b", "a.c", "b"}},
+		"empty range":        {opts: &IterateOptions{Start: []byte("b"), End: []byte("a")}, expected: nil},
+		"prefix and start":   {opts: &IterateOptions{Prefix: []byte("a."), Start: []byte("a.b")}, expected: []string{"a.b", "a.c"}},
+		"prefix and end":     {opts: &IterateOptions{Prefix: []byte("a."), End: []byte("a.c")}, expected: []string{"a.a", "a.b"}},
+		"prefix outside":     {opts: &IterateOptions{Prefix: []byte("a."), Start: []byte("b")}, expected: nil},
+		"prefix 0xff":        {opts: &IterateOptions{Prefix: []byte("\xff")}, expected: []string{"\xff", "\xff\xff"}},
+		"limit":              {opts: &IterateOptions{Limit: 2}, expected: []string{"a", "a.a"}},
+		"limit beyond":       {opts: &IterateOptions{Prefix: []byte("b"), Limit: 5}, expected: []string{"b", "b.a"}},
+		"reverse":            {opts: &IterateOptions{Reverse: true}, expected: []string{"\xff\xff", "\xff", "c", "b.a", "b", "a.c", "a.b", "a.a", "a"}},
+		"reverse range":      {opts: &IterateOptions{Start: []byte("a.a"), End: []byte("b.a"), Reverse: true}, expected: []string{"b", "a.c", "a.b", "a.a"}},
+		"reverse prefix":     {opts: &IterateOptions{Prefix: []byte("a."), Reverse: true}, expected: []string{"a.c", "a.b", "a.a"}},
+		"reverse limit":      {opts: &IterateOptions{Prefix: []byte("a"), Limit: 2, Reverse: true}, expected: []string{"a.c", "a.b"}},
+		"reverse end absent": {opts: &IterateOptions{End: []byte("a.bb"), Reverse: true}, expected: []string{"a.b", "a.a", "a"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			keys, err := Keys(kv.NewIterator(tt.opts))
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			var actual []string
+			for _, k := range keys {
+				actual = append(actual, string(k))
+			}
+			gt.Expect(actual).To(Equal(tt.expected))
+		})
+	}
+}
+
+func testKVIterateLarge(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	var expected []Key
+	wb := kv.NewWriteBatch()
+	for i := 0; i < 1000; i++ {
+		k := []byte(fmt.Sprintf("key/%04d", i))
+		gt.Expect(wb.Put(k, []byte(fmt.Sprintf("value/%04d", i)))).To(Succeed())
+		expected = append(expected, k)
+	}
+	gt.Expect(wb.Commit()).To(Succeed())
+
+	iter := kv.NewIterator(&IterateOptions{Prefix: []byte("key/")})
+	count := 0
+	for ; iter.Next(); count++ {
+		gt.Expect(iter.Key()).To(Equal([]byte(expected[count])))
+		gt.Expect(iter.Value()).To(Equal([]byte(fmt.Sprintf("value/%04d", count))))
+	}
+	gt.Expect(iter.Error()).NotTo(HaveOccurred())
+	iter.Release()
+	gt.Expect(count).To(Equal(1000))
+
+	keys, err := Keys(kv.NewIterator(&IterateOptions{Reverse: true, Limit: 600}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(HaveLen(600))
+	for i, k := range keys {
+		gt.Expect(k).To(Equal(expected[999-i]))
+	}
+
+	// Pages can be read by starting after the last key of the previous page.
+	var paged []Key
+	opts := &IterateOptions{Limit: 300}
+	for {
+		keys, err := Keys(kv.NewIterator(opts))
+		gt.Expect(err).NotTo(HaveOccurred())
+		if len(keys) == 0 {
+			break
+		}
+		paged = append(paged, keys...)
+		opts.Start = append(keys[len(keys)-1], 0)
+	}
+	gt.Expect(paged).To(Equal(expected))
+}
+
+func testKVConcurrency(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				wb := kv.NewWriteBatch()
+				wb.Put([]byte(fmt.Sprintf("key/%d/%02d/a", i, j)), []byte("a"))
+				wb.Put([]byte(fmt.Sprintf("key/%d/%02d/b", i, j)), []byte("b"))
+				if err := wb.Commit(); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := kv.Get([]byte(fmt.Sprintf("key/%d/%02d/a", i, j))); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := Keys(kv.NewIterator(&IterateOptions{Prefix: []byte(fmt.Sprintf("key/%d/", i))})); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	keys, err := Keys(kv.NewIterator(&IterateOptions{Prefix: []byte("key/")}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(HaveLen(8 * 50 * 2))
+}
+
+func testKVSnapshot(t *testing.T, kv conformingKV) {
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(kv.Put([]byte("a"), []byte("a1"))).To(Succeed())
+	gt.Expect(kv.Put([]byte("b"), []byte("b1"))).To(Succeed())
+
+	snap, err := kv.NewSnapshot()
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer snap.Release()
+
+	wb := kv.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("a"), []byte("a2"))).To(Succeed())
+	gt.Expect(wb.Delete([]byte("b"))).To(Succeed())
+	gt.Expect(wb.Put([]byte("c"), []byte("c2"))).To(Succeed())
+	gt.Expect(wb.Commit()).To(Succeed())
+	gt.Expect(kv.Put([]byte("d"), []byte("d2"))).To(Succeed())
+
+	// Writes made after the snapshot are not visible through it.
+	v, err := snap.Get([]byte("a"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("a1")))
+	v, err = snap.Get([]byte("b"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("b1")))
+	_, err = snap.Get([]byte("c"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	vals, err := snap.MultiGet([]byte("b"), []byte("a"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(vals).To(Equal([][]byte{[]byte("b1"), []byte("a1")}))
+	_, err = snap.MultiGet([]byte("a"), []byte("d"))
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	keys, err := Keys(snap.NewIterator(nil))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(Equal([]Key{Key("a"), Key("b")}))
+	keys, err = Keys(snap.NewIterator(&IterateOptions{Reverse: true, Limit: 1}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(Equal([]Key{Key("b")}))
+
+	// The store itself reflects the writes.
+	keys, err = Keys(kv.NewIterator(nil))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(Equal([]Key{Key("a"), Key("c"), Key("d")}))
+	v, err = kv.Get([]byte("a"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("a2")))
+}
@@ -0,0 +1,57 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/transaction"
+)
+
+// A StateUnknownError indicates that a state has never been stored. Err is
+// the NotFoundError returned by the store.
+type StateUnknownError struct {
+	StateID transaction.StateID
+	Err     error
+}
+
+func (s *StateUnknownError) Error() string {
+	return fmt.Sprintf("state %s is unknown: %s", s.StateID, s.Err)
+}
+
+func (s *StateUnknownError) Unwrap() error { return s.Err }
+
+// IsStateUnknown returns a boolean indicating whether the error indicates
+// that a state has never been stored.
+func IsStateUnknown(err error) bool {
+	var sue *StateUnknownError
+	return errors.As(err, &sue)
+}
+
+// A StateConsumedError indicates that a state has already been consumed.
+// ConsumedBy holds the ID of the transaction that consumed the state when it
+// is known and Err is the NotFoundError returned by the store.
+type StateConsumedError struct {
+	StateID    transaction.StateID
+	ConsumedBy transaction.ID
+	Err        error
+}
+
+func (s *StateConsumedError) Error() string {
+	if s.ConsumedBy == nil {
+		return fmt.Sprintf("state %s has been consumed: %s", s.StateID, s.Err)
+	}
+	return fmt.Sprintf("state %s has been consumed by transaction %s: %s", s.StateID, s.ConsumedBy, s.Err)
+}
+
+func (s *StateConsumedError) Unwrap() error { return s.Err }
+
+// IsStateConsumed returns a boolean indicating whether the error indicates
+// that a state has already been consumed.
+func IsStateConsumed(err error) bool {
+	var sce *StateConsumedError
+	return errors.As(err, &sce)
+}
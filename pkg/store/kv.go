@@ -20,6 +20,7 @@
 package store
 
 import (
+	"bytes"
 	"errors"
 	"io"
 )
@@ -54,12 +55,33 @@ type Key []byte
 
 type KV interface {
 	io.Closer
+	Reader
 
-	Get(key []byte) ([]byte, error)
 	Put(key, value []byte) error
 	Delete(key []byte) error
 
 	NewWriteBatch() WriteBatch
+	NewSnapshot() (Snapshot, error)
+}
+
+// A Reader reads the keys of a KV or of a Snapshot of a KV.
+type Reader interface {
+	Get(key []byte) ([]byte, error)
+	NewIterator(opts *IterateOptions) Iterator
+}
+
+// A Snapshot is a read-only view of a KV at the time the snapshot was
+// created. Writes to the KV after the snapshot was created are not visible
+// through it, so multiple keys read from a snapshot are always consistent
+// with each other.
+//
+// A Snapshot is safe for concurrent use. It must be released when it is no
+// longer used; iterators created from a snapshot must be released first.
+type Snapshot interface {
+	Reader
+	MultiGetter
+
+	Release()
 }
 
 type MultiGetter interface {
@@ -84,14 +106,109 @@ type WriteBatch interface {
 	Count() int
 }
 
-// Iterator iterates over a DB.
+// writeOp is an operation recorded by a WriteBatch that is applied when the
+// batch is committed.
+type writeOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// IterateOptions restricts the keys visited by an Iterator. The zero value
+// visits every key in ascending order.
+type IterateOptions struct {
+	// Prefix restricts iteration to the keys that start with the prefix.
+	Prefix []byte
+
+	// Start is the inclusive lower bound of the keys to visit.
+	Start []byte
+
+	// End is the exclusive upper bound of the keys to visit.
+	End []byte
+
+	// Limit is the maximum number of keys to visit. When it is 0, the
+	// number of keys is not limited.
+	Limit int
+
+	// Reverse visits the keys in descending order. The Limit applies to the
+	// keys visited, so a reverse iterator with a limit visits the largest
+	// keys within the bounds.
+	Reverse bool
+}
+
+// bounds returns the inclusive lower and exclusive upper bounds of the keys
+// to visit. A nil upper bound is unbounded.
+func (o *IterateOptions) bounds() (start, end []byte) {
+	if o == nil {
+		return nil, nil
+	}
+
+	start, end = o.Start, o.End
+	if len(o.Prefix) != 0 {
+		if bytes.Compare(o.Prefix, start) > 0 {
+			start = o.Prefix
+		}
+		if limit := prefixEnd(o.Prefix); limit != nil && (end == nil || bytes.Compare(limit, end) < 0) {
+			end = limit
+		}
+	}
+
+	return start, end
+}
+
+func (o *IterateOptions) limit() int {
+	if o == nil {
+		return 0
+	}
+	return o.Limit
+}
+
+func (o *IterateOptions) reverse() bool {
+	return o != nil && o.Reverse
+}
+
+// prefixEnd returns the smallest key that is greater than every key that
+// starts with prefix. It returns nil when there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// Iterator iterates over the key/value pairs of a DB in key order. An
+// Iterator is positioned before the first pair when it is created, so Next
+// must be called before the first Key or Value.
+//
 // The Iterator is not safe for concurrent use, but it is safe to use
 // multiple iterators concurrently, with each in a dedicated goroutine.
 // It is also safe to use an iterator concurrently with modifying its
-// underlying DB. The resultant key/value pairs are guaranteed to be
-// consistent.
+// underlying DB.
 //
-// Also read Iterator documentation of the leveldb/iterator package.
+// The slices returned by Key and Value must not be modified and are only
+// valid until the next call to Next. The Iterator must be released when
+// it is no longer used.
 type Iterator interface {
-	Keys() ([]Key, error)
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Keys returns the remaining keys of the iterator and releases it. The
+// returned keys can safely be modified.
+func Keys(iter Iterator) ([]Key, error) {
+	defer iter.Release()
+
+	var keys []Key
+	for iter.Next() {
+		keys = append(keys, clone(iter.Key()))
+	}
+
+	return keys, iter.Error()
 }
@@ -32,21 +32,62 @@ import (
 var _ Iterator = (*leveldbIterator)(nil)
 
 type leveldbIterator struct {
-	iterator.Iterator
+	iter    iterator.Iterator
+	reverse bool
+	limit   int
+	count   int
+	started bool
 }
 
-// Keys returns all keys that the iterator can iterate over.
-// The underlying returned value can safely be modified and the
-// iterator is released on return.
-func (i *leveldbIterator) Keys() ([]Key, error) {
-	defer i.Iterator.Release()
+func (i *leveldbIterator) Next() bool {
+	if i.limit > 0 && i.count >= i.limit {
+		return false
+	}
 
-	var keys []Key
-	for i.Iterator.Next() {
-		keys = append(keys, append([]byte(nil), i.Iterator.Key()...))
+	var ok bool
+	switch {
+	case !i.started && i.reverse:
+		ok = i.iter.Last()
+	case !i.started:
+		ok = i.iter.First()
+	case i.reverse:
+		ok = i.iter.Prev()
+	default:
+		ok = i.iter.Next()
+	}
+	i.started = true
+	if ok {
+		i.count++
 	}
 
-	return keys, i.Iterator.Error()
+	return ok
+}
+
+func (i *leveldbIterator) Key() []byte   { return i.iter.Key() }
+func (i *leveldbIterator) Value() []byte { return i.iter.Value() }
+func (i *leveldbIterator) Error() error  { return i.iter.Error() }
+func (i *leveldbIterator) Release()      { i.iter.Release() }
+
+var _ Snapshot = (*leveldbSnapshot)(nil)
+
+type leveldbSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+func (s *leveldbSnapshot) Get(key []byte) ([]byte, error) {
+	return leveldbGet(s.snap, key)
+}
+
+func (s *leveldbSnapshot) MultiGet(keys ...[]byte) ([][]byte, error) {
+	return leveldbMultiGet(s.snap, keys...)
+}
+
+func (s *leveldbSnapshot) NewIterator(opts *IterateOptions) Iterator {
+	return leveldbNewIterator(s.snap, opts)
+}
+
+func (s *leveldbSnapshot) Release() {
+	s.snap.Release()
 }
 
 var _ WriteBatch = (*leveldbWriteBatch)(nil)
@@ -78,7 +119,10 @@ func (b *leveldbWriteBatch) Count() int {
 	return b.batch.Len()
 }
 
-var _ KV = (*LevelDBKV)(nil)
+var (
+	_ KV          = (*LevelDBKV)(nil)
+	_ MultiGetter = (*LevelDBKV)(nil)
+)
 
 type LevelDBKV struct {
 	dir string
@@ -90,30 +134,11 @@ func (l *LevelDBKV) Close() error {
 }
 
 func (l *LevelDBKV) Get(key []byte) ([]byte, error) {
-	v, err := l.db.Get(key, nil)
-	if errors.Is(err, leveldb.ErrNotFound) {
-		return nil, notFound(err)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return v, nil
+	return leveldbGet(l.db, key)
 }
 
 func (l *LevelDBKV) MultiGet(keys ...[]byte) ([][]byte, error) {
-	bufs := make([][]byte, len(keys))
-
-	for i := range keys {
-		b, err := l.Get(keys[i])
-		if err != nil {
-			return nil, err
-		}
-
-		bufs[i] = b
-	}
-
-	return bufs, nil
+	return leveldbMultiGet(l.db, keys...)
 }
 
 func (l *LevelDBKV) Put(key, value []byte) error {
@@ -127,20 +152,19 @@ func (l *LevelDBKV) NewWriteBatch() WriteBatch {
 	}
 }
 
-// NewIterator returns an iterator that can be used to fetch Keys over a range
-// from the DB. Prefix allows slicing the iterator to only contains keys in the given
-// range. An empty prefix iterates over all keys in the DB.
-//
-// Values returned by the Iterator are cloned and can be safely modified.
-func (l *LevelDBKV) NewIterator(prefix []byte, ro *opt.ReadOptions) Iterator {
-	var bytesPrefix *util.Range
-	if len(prefix) > 0 {
-		bytesPrefix = util.BytesPrefix(prefix)
-	}
+// NewIterator returns an iterator over the key/value pairs within the bounds
+// of opts. A nil opts iterates over all pairs in the DB.
+func (l *LevelDBKV) NewIterator(opts *IterateOptions) Iterator {
+	return leveldbNewIterator(l.db, opts)
+}
 
-	return &leveldbIterator{
-		Iterator: l.db.NewIterator(bytesPrefix, ro),
+// NewSnapshot returns a LevelDB snapshot of the current state of the DB.
+func (l *LevelDBKV) NewSnapshot() (Snapshot, error) {
+	snap, err := l.db.GetSnapshot()
+	if err != nil {
+		return nil, err
 	}
+	return &leveldbSnapshot{snap: snap}, nil
 }
 
 func (l *LevelDBKV) commitWriteBatch(wb *leveldbWriteBatch) error {
@@ -180,6 +204,49 @@ func NewLevelDB(dir string) (*LevelDBKV, error) { // nolint:golint
 	}, nil
 }
 
+// leveldbReader is implemented by leveldb.DB and leveldb.Snapshot.
+type leveldbReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+func leveldbGet(r leveldbReader, key []byte) ([]byte, error) {
+	v, err := r.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, notFound(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func leveldbMultiGet(r leveldbReader, keys ...[]byte) ([][]byte, error) {
+	bufs := make([][]byte, len(keys))
+
+	for i := range keys {
+		b, err := leveldbGet(r, keys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		bufs[i] = b
+	}
+
+	return bufs, nil
+}
+
+func leveldbNewIterator(r leveldbReader, opts *IterateOptions) Iterator {
+	start, end := opts.bounds()
+
+	return &leveldbIterator{
+		iter:    r.NewIterator(&util.Range{Start: start, Limit: end}, nil),
+		reverse: opts.reverse(),
+		limit:   opts.limit(),
+	}
+}
+
 func notFound(err error) error {
 	return &NotFoundError{Err: err}
 }
@@ -252,8 +252,7 @@ func TestLevelDBIterator(t *testing.T) {
 		db.Put([]byte("c"), []byte{})
 		gt.Expect(err).NotTo(HaveOccurred())
 
-		iter := db.NewIterator(nil, nil)
-		keys, err := iter.Keys()
+		keys, err := Keys(db.NewIterator(nil))
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		gt.Expect(keys).To(ConsistOf(
@@ -276,8 +275,7 @@ func TestLevelDBIterator(t *testing.T) {
 		db.Put([]byte("b.c"), []byte{})
 		gt.Expect(err).NotTo(HaveOccurred())
 
-		iter := db.NewIterator([]byte("a."), nil)
-		keys, err := iter.Keys()
+		keys, err := Keys(db.NewIterator(&IterateOptions{Prefix: []byte("a.")}))
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		gt.Expect(keys).To(ConsistOf(
@@ -0,0 +1,483 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errMemoryNotFound = errors.New("memory: not found")
+	errMemoryClosed   = errors.New("memory: closed")
+	errMemoryReleased = errors.New("memory: snapshot released")
+)
+
+var _ Iterator = (*memoryIterator)(nil)
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+type memoryIterator struct {
+	entries []memoryEntry
+	pos     int
+	err     error
+}
+
+func (i *memoryIterator) Next() bool {
+	if i.err != nil || i.pos+1 >= len(i.entries) {
+		i.pos = len(i.entries)
+		return false
+	}
+	i.pos++
+	return true
+}
+
+func (i *memoryIterator) Key() []byte {
+	if i.pos < 0 || i.pos >= len(i.entries) {
+		return nil
+	}
+	return []byte(i.entries[i.pos].key)
+}
+
+func (i *memoryIterator) Value() []byte {
+	if i.pos < 0 || i.pos >= len(i.entries) {
+		return nil
+	}
+	return clone(i.entries[i.pos].value)
+}
+
+func (i *memoryIterator) Error() error { return i.err }
+func (i *memoryIterator) Release()     { i.entries = nil }
+
+var _ WriteBatch = (*memoryWriteBatch)(nil)
+
+type memoryWriteBatch struct {
+	ops []writeOp
+	kv  *MemoryKV
+}
+
+func (b *memoryWriteBatch) Put(key, value []byte) error {
+	b.ops = append(b.ops, writeOp{key: clone(key), value: clone(value)})
+	return nil
+}
+
+func (b *memoryWriteBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, writeOp{key: clone(key), delete: true})
+	return nil
+}
+
+func (b *memoryWriteBatch) Commit() error {
+	return b.kv.commitWriteBatch(b)
+}
+
+func (b *memoryWriteBatch) Clear() {
+	b.ops = nil
+}
+
+func (b *memoryWriteBatch) Count() int {
+	return len(b.ops)
+}
+
+var _ Snapshot = (*memorySnapshot)(nil)
+
+// memorySnapshot is a read-only view of the contents of a MemoryKV.
+type memorySnapshot struct {
+	mutex    sync.RWMutex
+	data     *memoryData
+	released bool
+}
+
+func (s *memorySnapshot) Get(key []byte) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.released {
+		return nil, errMemoryReleased
+	}
+	return s.data.get(key)
+}
+
+func (s *memorySnapshot) MultiGet(keys ...[]byte) ([][]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.released {
+		return nil, errMemoryReleased
+	}
+	return s.data.multiGet(keys...)
+}
+
+func (s *memorySnapshot) NewIterator(opts *IterateOptions) Iterator {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.released {
+		return &memoryIterator{err: errMemoryReleased}
+	}
+	return s.data.newIterator(opts)
+}
+
+func (s *memorySnapshot) Release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.released = true
+	s.data = nil
+}
+
+// memoryData is the ordered contents of a MemoryKV at a point in time.
+type memoryData struct {
+	root *memoryNode
+}
+
+func (d *memoryData) get(key []byte) ([]byte, error) {
+	n := d.root.find(string(key))
+	if n == nil {
+		return nil, notFound(errMemoryNotFound)
+	}
+
+	return clone(n.value), nil
+}
+
+func (d *memoryData) multiGet(keys ...[]byte) ([][]byte, error) {
+	bufs := make([][]byte, len(keys))
+	for i := range keys {
+		n := d.root.find(string(keys[i]))
+		if n == nil {
+			return nil, notFound(errMemoryNotFound)
+		}
+		bufs[i] = clone(n.value)
+	}
+
+	return bufs, nil
+}
+
+func (d *memoryData) newIterator(opts *IterateOptions) Iterator {
+	start, end := opts.bounds()
+	r := memoryRange{lo: string(start), hi: string(end), bounded: end != nil}
+
+	var entries []memoryEntry
+	limit := opts.limit()
+	visit := func(n *memoryNode) bool {
+		entries = append(entries, memoryEntry{key: n.key, value: n.value})
+		return limit <= 0 || len(entries) < limit
+	}
+	if opts.reverse() {
+		d.root.descend(r, visit)
+	} else {
+		d.root.ascend(r, visit)
+	}
+
+	return &memoryIterator{entries: entries, pos: -1}
+}
+
+// memoryRange is the range of keys visited by an iterator. The range
+// includes lo and excludes hi; it is unbounded above when bounded is false.
+type memoryRange struct {
+	lo, hi  string
+	bounded bool
+}
+
+func (r memoryRange) above(key string) bool { return key >= r.lo }
+func (r memoryRange) below(key string) bool { return !r.bounded || key < r.hi }
+
+// memoryNode is a node of a persistent treap ordered by key. The priority of
+// a node is derived from its key so the shape of the tree only depends on the
+// keys it holds.
+//
+// Nodes are copied on write. A node may only be modified by writes of the
+// generation that created it; the generation is advanced when a snapshot is
+// taken so the nodes reachable from a snapshot are never modified. A write
+// therefore copies at most the nodes on the path to its key.
+type memoryNode struct {
+	key         string
+	value       []byte
+	priority    uint32
+	gen         uint64
+	left, right *memoryNode
+}
+
+func newMemoryNode(key string, value []byte, gen uint64) *memoryNode {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &memoryNode{key: key, value: value, priority: h.Sum32(), gen: gen}
+}
+
+// mutable returns a node that may be modified by writes of the generation.
+func (n *memoryNode) mutable(gen uint64) *memoryNode {
+	if n.gen == gen {
+		return n
+	}
+	c := *n
+	c.gen = gen
+	return &c
+}
+
+func (n *memoryNode) find(key string) *memoryNode {
+	for n != nil {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// put returns the root of a tree that holds the value at key.
+func (n *memoryNode) put(key string, value []byte, gen uint64) *memoryNode {
+	if n == nil {
+		return newMemoryNode(key, value, gen)
+	}
+
+	n = n.mutable(gen)
+	switch {
+	case key < n.key:
+		n.left = n.left.put(key, value, gen)
+		if n.left.priority > n.priority {
+			// rotate right; the left child is mutable after the put
+			l := n.left
+			n.left, l.right = l.right, n
+			return l
+		}
+	case key > n.key:
+		n.right = n.right.put(key, value, gen)
+		if n.right.priority > n.priority {
+			// rotate left; the right child is mutable after the put
+			r := n.right
+			n.right, r.left = r.left, n
+			return r
+		}
+	default:
+		n.value = value
+	}
+	return n
+}
+
+// delete returns the root of a tree that does not hold key. Nodes are not
+// copied when the key is not found.
+func (n *memoryNode) delete(key string, gen uint64) *memoryNode {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case key < n.key:
+		left := n.left.delete(key, gen)
+		if left == n.left {
+			return n
+		}
+		n = n.mutable(gen)
+		n.left = left
+	case key > n.key:
+		right := n.right.delete(key, gen)
+		if right == n.right {
+			return n
+		}
+		n = n.mutable(gen)
+		n.right = right
+	default:
+		return mergeMemoryNodes(n.left, n.right, gen)
+	}
+	return n
+}
+
+// mergeMemoryNodes joins two trees where every key of the left tree is less
+// than every key of the right tree.
+func mergeMemoryNodes(left, right *memoryNode, gen uint64) *memoryNode {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left = left.mutable(gen)
+		left.right = mergeMemoryNodes(left.right, right, gen)
+		return left
+	default:
+		right = right.mutable(gen)
+		right.left = mergeMemoryNodes(left, right.left, gen)
+		return right
+	}
+}
+
+// ascend visits the nodes within the range in key order until visit returns
+// false. The return value reports whether the visit should continue.
+func (n *memoryNode) ascend(r memoryRange, visit func(*memoryNode) bool) bool {
+	if n == nil {
+		return true
+	}
+	if n.key > r.lo && !n.left.ascend(r, visit) {
+		return false
+	}
+	if r.above(n.key) && r.below(n.key) && !visit(n) {
+		return false
+	}
+	if r.below(n.key) {
+		return n.right.ascend(r, visit)
+	}
+	return true
+}
+
+// descend visits the nodes within the range in reverse key order until visit
+// returns false. The return value reports whether the visit should continue.
+func (n *memoryNode) descend(r memoryRange, visit func(*memoryNode) bool) bool {
+	if n == nil {
+		return true
+	}
+	if r.below(n.key) && !n.right.descend(r, visit) {
+		return false
+	}
+	if r.above(n.key) && r.below(n.key) && !visit(n) {
+		return false
+	}
+	if n.key > r.lo {
+		return n.left.descend(r, visit)
+	}
+	return true
+}
+
+var (
+	_ KV          = (*MemoryKV)(nil)
+	_ MultiGetter = (*MemoryKV)(nil)
+)
+
+// MemoryKV is an ordered key value store that is held in memory. It is safe
+// for concurrent use and write batches are applied atomically. The contents
+// of the store are lost when it is closed.
+type MemoryKV struct {
+	mutex  sync.RWMutex
+	data   *memoryData
+	gen    uint64 // gen is the generation of nodes that writes may modify
+	closed bool
+}
+
+// NewMemoryKV creates an empty in-memory key value store.
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{
+		data: &memoryData{},
+	}
+}
+
+func (m *MemoryKV) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return errMemoryClosed
+	}
+	m.closed = true
+	m.data = nil
+	return nil
+}
+
+func (m *MemoryKV) Get(key []byte) ([]byte, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.closed {
+		return nil, errMemoryClosed
+	}
+	return m.data.get(key)
+}
+
+func (m *MemoryKV) MultiGet(keys ...[]byte) ([][]byte, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.closed {
+		return nil, errMemoryClosed
+	}
+	return m.data.multiGet(keys...)
+}
+
+func (m *MemoryKV) Put(key, value []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return errMemoryClosed
+	}
+	m.data.root = m.data.root.put(string(key), clone(value), m.gen)
+	return nil
+}
+
+func (m *MemoryKV) Delete(key []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return errMemoryClosed
+	}
+	m.data.root = m.data.root.delete(string(key), m.gen)
+	return nil
+}
+
+func (m *MemoryKV) NewWriteBatch() WriteBatch {
+	return &memoryWriteBatch{kv: m}
+}
+
+// NewIterator returns an iterator over the key/value pairs within the bounds
+// of opts. A nil opts iterates over all pairs in the store.
+//
+// The pairs are captured when the iterator is created so later writes to the
+// store are not visited.
+func (m *MemoryKV) NewIterator(opts *IterateOptions) Iterator {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.closed {
+		return &memoryIterator{err: errMemoryClosed}
+	}
+	return m.data.newIterator(opts)
+}
+
+// NewSnapshot returns a read-only view of the current contents of the store.
+// The contents are shared with the store; later writes copy the nodes they
+// modify instead of the whole store.
+func (m *MemoryKV) NewSnapshot() (Snapshot, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, errMemoryClosed
+	}
+	m.gen++
+	return &memorySnapshot{data: &memoryData{root: m.data.root}}, nil
+}
+
+func (m *MemoryKV) commitWriteBatch(wb *memoryWriteBatch) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return errMemoryClosed
+	}
+	root := m.data.root
+	for _, op := range wb.ops {
+		if op.delete {
+			root = root.delete(string(op.key), m.gen)
+			continue
+		}
+		root = root.put(string(op.key), op.value, m.gen)
+	}
+	m.data.root = root
+
+	return nil
+}
+
+func clone(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return append([]byte{}, b...)
+}
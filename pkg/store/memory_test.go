@@ -0,0 +1,177 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMemoryKVCopiesValues(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db := NewMemoryKV()
+	gt.Expect(db.Put([]byte("key"), []byte("value"))).To(Succeed())
+
+	v, err := db.Get([]byte("key"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	v[0] = 'V'
+
+	v, err = db.Get([]byte("key"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("value")))
+
+	value := []byte("batched")
+	wb := db.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("key"), value)).To(Succeed())
+	value[0] = 'B'
+	gt.Expect(wb.Commit()).To(Succeed())
+
+	v, err = db.Get([]byte("key"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(v).To(Equal([]byte("batched")))
+}
+
+func TestMemoryKVClose(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db := NewMemoryKV()
+	gt.Expect(db.Put([]byte("key"), []byte("value"))).To(Succeed())
+	wb := db.NewWriteBatch()
+	gt.Expect(wb.Put([]byte("batched"), []byte("value"))).To(Succeed())
+
+	gt.Expect(db.Close()).To(Succeed())
+	gt.Expect(db.Close()).To(MatchError("memory: closed"))
+
+	_, err := db.Get([]byte("key"))
+	gt.Expect(err).To(MatchError("memory: closed"))
+	gt.Expect(IsNotFound(err)).To(BeFalse())
+	_, err = db.MultiGet([]byte("key"))
+	gt.Expect(err).To(MatchError("memory: closed"))
+	gt.Expect(db.Put([]byte("key"), nil)).To(MatchError("memory: closed"))
+	gt.Expect(db.Delete([]byte("key"))).To(MatchError("memory: closed"))
+	gt.Expect(wb.Commit()).To(MatchError("memory: closed"))
+	_, err = Keys(db.NewIterator(nil))
+	gt.Expect(err).To(MatchError("memory: closed"))
+}
+
+func TestMemoryKVSnapshotIsolation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	db := NewMemoryKV()
+	defer db.Close()
+
+	type snapshot struct {
+		snap     Reader
+		expected map[string]string
+	}
+	var snapshots []snapshot
+
+	model := map[string]string{}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key-%03d", rng.Intn(300))
+		switch {
+		case rng.Intn(4) == 0:
+			gt.Expect(db.Delete([]byte(key))).To(Succeed())
+			delete(model, key)
+		case rng.Intn(2) == 0:
+			value := fmt.Sprintf("value-%d", i)
+			wb := db.NewWriteBatch()
+			gt.Expect(wb.Put([]byte(key), []byte(value))).To(Succeed())
+			gt.Expect(wb.Delete([]byte(key + "-missing"))).To(Succeed())
+			gt.Expect(wb.Commit()).To(Succeed())
+			model[key] = value
+		default:
+			value := fmt.Sprintf("value-%d", i)
+			gt.Expect(db.Put([]byte(key), []byte(value))).To(Succeed())
+			model[key] = value
+		}
+
+		if i%100 == 0 {
+			snap, err := db.NewSnapshot()
+			gt.Expect(err).NotTo(HaveOccurred())
+			expected := map[string]string{}
+			for k, v := range model {
+				expected[k] = v
+			}
+			defer snap.Release()
+			snapshots = append(snapshots, snapshot{snap: snap, expected: expected})
+		}
+	}
+	snapshots = append(snapshots, snapshot{snap: db, expected: model})
+
+	for _, s := range snapshots {
+		var keys []string
+		for k := range s.expected {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		it := s.snap.NewIterator(nil)
+		var actual []string
+		for it.Next() {
+			gt.Expect(string(it.Value())).To(Equal(s.expected[string(it.Key())]))
+			actual = append(actual, string(it.Key()))
+		}
+		it.Release()
+		gt.Expect(actual).To(Equal(keys))
+
+		reversed, err := Keys(s.snap.NewIterator(&IterateOptions{Start: []byte("key-100"), End: []byte("key-200"), Reverse: true, Limit: 10}))
+		gt.Expect(err).NotTo(HaveOccurred())
+		var expected []Key
+		for i := len(keys) - 1; i >= 0 && len(expected) < 10; i-- {
+			if keys[i] >= "key-100" && keys[i] < "key-200" {
+				expected = append(expected, Key(keys[i]))
+			}
+		}
+		gt.Expect(reversed).To(Equal(expected))
+	}
+}
+
+// BenchmarkMemoryKVCommitWithSnapshots commits small batches to a large
+// store while snapshots are taken and held open.
+func BenchmarkMemoryKVCommitWithSnapshots(b *testing.B) {
+	gt := NewGomegaWithT(b)
+
+	db := NewMemoryKV()
+	defer db.Close()
+
+	wb := db.NewWriteBatch()
+	for i := 0; i < 100000; i++ {
+		gt.Expect(wb.Put([]byte(fmt.Sprintf("key-%08d", i)), []byte("value"))).To(Succeed())
+	}
+	gt.Expect(wb.Commit()).To(Succeed())
+
+	var snapshots []Snapshot
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		snap, err := db.NewSnapshot()
+		if err != nil {
+			b.Fatal(err)
+		}
+		snapshots = append(snapshots, snap)
+		if len(snapshots) > 8 {
+			snapshots[0].Release()
+			snapshots = snapshots[1:]
+		}
+
+		wb := db.NewWriteBatch()
+		for j := 0; j < 4; j++ {
+			wb.Put([]byte(fmt.Sprintf("key-%08d", (i*4+j)%200000)), []byte("updated"))
+		}
+		if err := wb.Commit(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	for _, snap := range snapshots {
+		snap.Release()
+	}
+}
@@ -18,18 +18,83 @@ import (
 
 // TODO: Determine how to model the hasher required to restore a transaction.
 // TODO: Standarize on binary mashaling and unmarshaling to remove proto
-// TODO: Unit of work / atomicity / snapshot isolation
 
 type TransactionRepository struct {
-	kv KV
+	kv     KV
+	reader Reader // reader is the kv or, for a view, a snapshot of the kv
 }
 
 func NewRepository(kv KV) *TransactionRepository {
 	return &TransactionRepository{
-		kv: kv,
+		kv:     kv,
+		reader: kv,
 	}
 }
 
+// A View reads transactions and states from a consistent snapshot of a
+// TransactionRepository. Writes made after the view was created are not
+// visible through it. A View must be released when it is no longer needed.
+type View interface {
+	GetReceipt([]byte) (*transaction.Receipt, error)
+	GetCommitted(transaction.ID) (*transaction.Committed, error)
+	GetRejected(transaction.ID) (*transaction.Rejected, error)
+	GetTransaction(transaction.ID) (*transaction.Transaction, error)
+	GetState(transaction.StateID, bool) (*transaction.State, error)
+	GetStatesByOwner([]byte) ([]*transaction.State, error)
+	GetStatesByKind(string) ([]*transaction.State, error)
+	Release()
+}
+
+// NewView returns a View of the current contents of the repository.
+func (t *TransactionRepository) NewView() (View, error) {
+	snap, err := t.kv.NewSnapshot()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create snapshot")
+	}
+
+	return &repositoryView{
+		repo: &TransactionRepository{reader: snap},
+		snap: snap,
+	}, nil
+}
+
+type repositoryView struct {
+	repo *TransactionRepository
+	snap Snapshot
+}
+
+func (v *repositoryView) GetReceipt(id []byte) (*transaction.Receipt, error) {
+	return v.repo.GetReceipt(id)
+}
+
+func (v *repositoryView) GetCommitted(id transaction.ID) (*transaction.Committed, error) {
+	return v.repo.GetCommitted(id)
+}
+
+func (v *repositoryView) GetRejected(id transaction.ID) (*transaction.Rejected, error) {
+	return v.repo.GetRejected(id)
+}
+
+func (v *repositoryView) GetTransaction(id transaction.ID) (*transaction.Transaction, error) {
+	return v.repo.GetTransaction(id)
+}
+
+func (v *repositoryView) GetState(stateID transaction.StateID, consumed bool) (*transaction.State, error) {
+	return v.repo.getState(stateID, consumed)
+}
+
+func (v *repositoryView) GetStatesByOwner(publicKey []byte) ([]*transaction.State, error) {
+	return v.repo.getIndexedStates(ownerIndexPrefix(publicKey))
+}
+
+func (v *repositoryView) GetStatesByKind(kind string) ([]*transaction.State, error) {
+	return v.repo.getIndexedStates(kindIndexPrefix(kind))
+}
+
+func (v *repositoryView) Release() {
+	v.snap.Release()
+}
+
 func (t *TransactionRepository) PutReceipt(receipt *transaction.Receipt) error {
 	serialized, err := json.Marshal(receipt)
 	if err != nil {
@@ -45,7 +110,7 @@ func (t *TransactionRepository) PutReceipt(receipt *transaction.Receipt) error {
 }
 
 func (t *TransactionRepository) GetReceipt(id []byte) (*transaction.Receipt, error) {
-	data, err := t.kv.Get(receiptKey(id))
+	data, err := t.reader.Get(receiptKey(id))
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to get receipt from db")
 	}
@@ -76,7 +141,7 @@ func (t *TransactionRepository) PutCommitted(id transaction.ID, commit *transact
 }
 
 func (t *TransactionRepository) GetCommitted(id transaction.ID) (*transaction.Committed, error) {
-	data, err := t.kv.Get(commitKey(id))
+	data, err := t.reader.Get(commitKey(id))
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to get tx commitment from db")
 	}
@@ -90,6 +155,38 @@ func (t *TransactionRepository) GetCommitted(id transaction.ID) (*transaction.Co
 	return &r, nil
 }
 
+// PutRejected records the reason a transaction was rejected.
+func (t *TransactionRepository) PutRejected(id transaction.ID, rejected *transaction.Rejected) error {
+	serialized, err := json.Marshal(rejected)
+	if err != nil {
+		return errors.WithMessage(err, "could not serialize rejection to JSON")
+	}
+
+	err = t.kv.Put(rejectionKey(id), serialized)
+	if err != nil {
+		return errors.WithMessage(err, "failed to store rejection")
+	}
+
+	return nil
+}
+
+// GetRejected retrieves the reason a transaction was rejected. A
+// NotFoundError is returned when the transaction has not been rejected.
+func (t *TransactionRepository) GetRejected(id transaction.ID) (*transaction.Rejected, error) {
+	data, err := t.reader.Get(rejectionKey(id))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get tx rejection from db")
+	}
+
+	var r transaction.Rejected
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal retreived rejection")
+	}
+
+	return &r, nil
+}
+
 func (t *TransactionRepository) PutTransaction(tx *transaction.Transaction) error {
 	err := t.kv.Put(transactionKey(tx.ID), tx.Encoded)
 	if err != nil {
@@ -99,7 +196,7 @@ func (t *TransactionRepository) PutTransaction(tx *transaction.Transaction) erro
 }
 
 func (t *TransactionRepository) GetTransaction(id transaction.ID) (*transaction.Transaction, error) {
-	payload, err := t.kv.Get(transactionKey(id))
+	payload, err := t.reader.Get(transactionKey(id))
 	if err != nil {
 		return nil, errors.WithMessagef(err, "error getting tx %x from db", id)
 	}
@@ -114,6 +211,15 @@ func (t *TransactionRepository) GetTransaction(id transaction.ID) (*transaction.
 }
 
 func (t *TransactionRepository) PutState(state *transaction.State) error {
+	batch := t.kv.NewWriteBatch()
+	if err := t.putState(batch, state); err != nil {
+		return err
+	}
+
+	return errors.WithMessage(batch.Commit(), "error committing resolved states batch")
+}
+
+func (t *TransactionRepository) putState(batch WriteBatch, state *transaction.State) error {
 	var owners []*txv1.Party
 	si := state.StateInfo
 	for i := range si.Owners {
@@ -129,19 +235,39 @@ func (t *TransactionRepository) PutState(state *transaction.State) error {
 		return errors.WithMessage(err, "error marshalling state info")
 	}
 
-	batch := t.kv.NewWriteBatch()
 	if err := batch.Put(stateKey(state.ID), state.Data); err != nil {
 		return err
 	}
 	if err := batch.Put(stateInfoKey(state.ID), info); err != nil {
 		return err
 	}
-
-	return errors.WithMessage(batch.Commit(), "error committing resolved states batch")
+	return putIndexes(batch, state.ID, stateInfo)
 }
 
+// GetState retrieves a state from the store. When consumed is false, only
+// states that have not been consumed are returned.
+//
+// A StateUnknownError is returned when the state was never stored and a
+// StateConsumedError is returned when an unconsumed state is requested but
+// the state has already been consumed. Both errors are also NotFoundErrors.
+//
+// The state info and data are read from a single snapshot so a concurrent
+// commit can not be observed part way through.
 func (t *TransactionRepository) GetState(stateID transaction.StateID, consumed bool) (*transaction.State, error) {
-	infoPayload, err := t.kv.Get(stateInfoKey(stateID))
+	view, err := t.NewView()
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	return view.GetState(stateID, consumed)
+}
+
+func (t *TransactionRepository) getState(stateID transaction.StateID, consumed bool) (*transaction.State, error) {
+	infoPayload, err := t.reader.Get(stateInfoKey(stateID))
+	if IsNotFound(err) {
+		err = &StateUnknownError{StateID: stateID, Err: err}
+	}
 	if err != nil {
 		return nil, errors.WithMessagef(err, "error getting state info for %s from db", stateID)
 	}
@@ -153,9 +279,12 @@ func (t *TransactionRepository) GetState(stateID transaction.StateID, consumed b
 	var payload []byte
 	switch {
 	case consumed:
-		payload, err = t.kv.Get(consumedStateKey(stateID))
+		payload, err = t.reader.Get(consumedStateKey(stateID))
 	default:
-		payload, err = t.kv.Get(stateKey(stateID))
+		payload, err = t.reader.Get(stateKey(stateID))
+		if IsNotFound(err) {
+			err = t.consumedError(stateID, err)
+		}
 	}
 	if err != nil {
 		return nil, errors.WithMessagef(err, "error getting state %s from db", stateID)
@@ -178,27 +307,326 @@ func (t *TransactionRepository) GetState(stateID transaction.StateID, consumed b
 	return state, nil
 }
 
-func (t *TransactionRepository) ConsumeState(stateID transaction.StateID) error {
-	return t.consumeStates(stateID)
+// missingStateError returns a StateUnknownError or a StateConsumedError for a
+// state that is not available as unconsumed.
+func (t *TransactionRepository) missingStateError(stateID transaction.StateID, err error) error {
+	_, ierr := t.reader.Get(stateInfoKey(stateID))
+	if IsNotFound(ierr) {
+		return &StateUnknownError{StateID: stateID, Err: err}
+	}
+	if ierr != nil {
+		return ierr
+	}
+	return t.consumedError(stateID, err)
+}
+
+// consumedError returns a StateConsumedError for a state that is no longer
+// available as unconsumed. The transaction that consumed the state is
+// included when it is known.
+func (t *TransactionRepository) consumedError(stateID transaction.StateID, err error) error {
+	consumedBy, cerr := t.reader.Get(consumerKey(stateID))
+	if cerr != nil && !IsNotFound(cerr) {
+		return cerr
+	}
+	return &StateConsumedError{StateID: stateID, ConsumedBy: consumedBy, Err: err}
 }
 
-func (t *TransactionRepository) consumeStates(stateIDs ...transaction.StateID) error {
+// ConsumeState marks a state as consumed. The transaction that consumed the
+// state is not recorded; use CommitTransaction to consume the inputs of a
+// transaction.
+func (t *TransactionRepository) ConsumeState(stateID transaction.StateID) error {
 	batch := t.kv.NewWriteBatch()
+	if err := t.consumeStates(batch, nil, stateID); err != nil {
+		return err
+	}
+	return errors.WithMessage(batch.Commit(), "error consuming states batch")
+}
+
+func (t *TransactionRepository) consumeStates(batch WriteBatch, consumedBy transaction.ID, stateIDs ...transaction.StateID) error {
 	for _, id := range stateIDs {
 		state, err := t.kv.Get(stateKey(id))
+		if IsNotFound(err) {
+			return t.missingStateError(id, err)
+		}
 		if err != nil {
 			return err
 		}
+		if consumedBy != nil {
+			if err := batch.Put(consumerKey(id), consumedBy); err != nil {
+				return err
+			}
+		}
 		err = batch.Put(consumedStateKey(id), state)
 		if err != nil {
 			return err
 		}
-		err = t.kv.Delete(stateKey(id))
+		err = batch.Delete(stateKey(id))
+		if err != nil {
+			return err
+		}
+
+		infoPayload, err := t.kv.Get(stateInfoKey(id))
 		if err != nil {
+			return errors.WithMessagef(err, "error getting state info for %s from db", id)
+		}
+		var stateInfo txv1.StateInfo
+		if err := proto.Unmarshal(infoPayload, &stateInfo); err != nil {
+			return errors.WithMessagef(err, "error unmarshaling state info for ref %s", id)
+		}
+		if err := deleteIndexes(batch, id, &stateInfo); err != nil {
 			return err
 		}
 	}
-	return errors.WithMessage(batch.Commit(), "error consuming states batch")
+	return nil
+}
+
+// GetStatesByOwner returns the unconsumed states owned by the party with the
+// provided public key. The states are ordered by state ID.
+func (t *TransactionRepository) GetStatesByOwner(publicKey []byte) ([]*transaction.State, error) {
+	view, err := t.NewView()
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	return view.GetStatesByOwner(publicKey)
+}
+
+// GetStatesByKind returns the unconsumed states of the provided kind. The
+// states are ordered by state ID.
+func (t *TransactionRepository) GetStatesByKind(kind string) ([]*transaction.State, error) {
+	view, err := t.NewView()
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	return view.GetStatesByKind(kind)
+}
+
+// getIndexedStates returns the states referenced by the index entries that
+// start with prefix.
+func (t *TransactionRepository) getIndexedStates(prefix []byte) ([]*transaction.State, error) {
+	keys, err := Keys(t.reader.NewIterator(&IterateOptions{Prefix: prefix}))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading index from db")
+	}
+
+	var states []*transaction.State
+	for _, key := range keys {
+		id := indexedStateID(prefix, key)
+		state, err := t.getState(id, false)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error getting indexed state %s", id)
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// RebuildIndexes replaces the owner and kind index entries with entries for
+// every unconsumed state and returns the number of states that were indexed.
+//
+// The entries are built from a snapshot and replaced with a single write
+// batch. States committed while the indexes are rebuilt may be indexed
+// incorrectly so the rebuild should not run while transactions are being
+// committed.
+func (t *TransactionRepository) RebuildIndexes() (int, error) {
+	batch := t.kv.NewWriteBatch()
+	count, err := t.rebuildIndexes(batch)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, errors.WithMessage(batch.Commit(), "error committing index batch")
+}
+
+func (t *TransactionRepository) rebuildIndexes(batch WriteBatch) (int, error) {
+	snap, err := t.kv.NewSnapshot()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to create snapshot")
+	}
+	defer snap.Release()
+
+	for _, prefix := range [][]byte{keyOwnerIndex[:], keyKindIndex[:]} {
+		keys, err := Keys(snap.NewIterator(&IterateOptions{Prefix: prefix}))
+		if err != nil {
+			return 0, errors.WithMessage(err, "error reading index from db")
+		}
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	keys, err := Keys(snap.NewIterator(&IterateOptions{Prefix: keyStates[:]}))
+	if err != nil {
+		return 0, errors.WithMessage(err, "error reading states from db")
+	}
+	for _, key := range keys {
+		id := indexedStateID(keyStates[:], key)
+		infoPayload, err := snap.Get(stateInfoKey(id))
+		if err != nil {
+			return 0, errors.WithMessagef(err, "error getting state info for %s from db", id)
+		}
+		var stateInfo txv1.StateInfo
+		if err := proto.Unmarshal(infoPayload, &stateInfo); err != nil {
+			return 0, errors.WithMessagef(err, "error unmarshaling state info for ref %s", id)
+		}
+		if err := putIndexes(batch, id, &stateInfo); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(keys), nil
+}
+
+// putIndexes adds the owner and kind index entries of an unconsumed state to
+// the batch.
+func putIndexes(batch WriteBatch, id transaction.StateID, info *txv1.StateInfo) error {
+	for _, key := range indexKeys(id, info) {
+		if err := batch.Put(key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIndexes removes the owner and kind index entries of a state.
+func deleteIndexes(batch WriteBatch, id transaction.StateID, info *txv1.StateInfo) error {
+	for _, key := range indexKeys(id, info) {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexKeys(id transaction.StateID, info *txv1.StateInfo) [][]byte {
+	var keys [][]byte
+	for _, owner := range info.Owners {
+		keys = append(keys, indexKey(ownerIndexPrefix(owner.PublicKey), id))
+	}
+	return append(keys, indexKey(kindIndexPrefix(info.Kind), id))
+}
+
+// CommitTransaction records the commit of a transaction. The commit record,
+// the transaction outputs, and the consumption of the transaction inputs are
+// applied with a single write batch so a failure can not leave a partially
+// committed transaction behind.
+func (t *TransactionRepository) CommitTransaction(id transaction.ID, commit *transaction.Committed, outputs []*transaction.State, inputs []transaction.StateID) error {
+	serialized, err := json.Marshal(commit)
+	if err != nil {
+		return errors.WithMessage(err, "could not serialize commit to JSON")
+	}
+
+	batch := t.kv.NewWriteBatch()
+	if err := batch.Put(commitKey(id), serialized); err != nil {
+		return err
+	}
+	if err := batch.Put(commitSeqKey(commit.SeqNo), id); err != nil {
+		return err
+	}
+	for _, output := range outputs {
+		if err := t.putState(batch, output); err != nil {
+			return errors.WithMessagef(err, "failed to store output %s", output.ID)
+		}
+	}
+	if err := t.consumeStates(batch, id, inputs...); err != nil {
+		return errors.WithMessage(err, "failed to consume inputs")
+	}
+
+	if err := batch.Put(keyLastCommittedSeq[:], uint64ToBytes(commit.SeqNo)); err != nil {
+		return err
+	}
+
+	return errors.WithMessagef(batch.Commit(), "error committing transaction %s", id)
+}
+
+// RejectTransaction records the reason a transaction was rejected. The
+// rejection and the sequence number of the rejected entry are applied with a
+// single write batch so the entry is not processed again after a restart.
+func (t *TransactionRepository) RejectTransaction(id transaction.ID, rejected *transaction.Rejected) error {
+	serialized, err := json.Marshal(rejected)
+	if err != nil {
+		return errors.WithMessage(err, "could not serialize rejection to JSON")
+	}
+
+	batch := t.kv.NewWriteBatch()
+	if err := batch.Put(rejectionKey(id), serialized); err != nil {
+		return err
+	}
+	if err := batch.Put(keyLastCommittedSeq[:], uint64ToBytes(rejected.SeqNo)); err != nil {
+		return err
+	}
+
+	return errors.WithMessagef(batch.Commit(), "error rejecting transaction %s", id)
+}
+
+// GetLastCommittedSeq returns the sequence number of the last ordered
+// transaction that was committed or rejected. A NotFoundError is returned
+// when no transaction has been processed.
+func (t *TransactionRepository) GetLastCommittedSeq() (uint64, error) {
+	data, err := t.kv.Get(keyLastCommittedSeq[:])
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get last committed sequence from db")
+	}
+	if len(data) != 8 {
+		return 0, errors.Errorf("last committed sequence has invalid length %d", len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// PutAttestation adds an attestation to the commit record of a transaction.
+// The commit record and the sequence number of the attested commit are
+// updated with a single write batch.
+func (t *TransactionRepository) PutAttestation(id transaction.ID, attestation *transaction.Attestation) error {
+	committed, err := t.GetCommitted(id)
+	if err != nil {
+		return err
+	}
+	committed.Attestation = attestation
+
+	serialized, err := json.Marshal(committed)
+	if err != nil {
+		return errors.WithMessage(err, "could not serialize commit to JSON")
+	}
+
+	batch := t.kv.NewWriteBatch()
+	if err := batch.Put(commitKey(id), serialized); err != nil {
+		return err
+	}
+	if err := batch.Put(keyLastAttestedSeq[:], uint64ToBytes(committed.SeqNo)); err != nil {
+		return err
+	}
+
+	return errors.WithMessagef(batch.Commit(), "error attesting transaction %s", id)
+}
+
+// GetLastAttestedSeq returns the sequence number of the last committed
+// transaction that was attested. A NotFoundError is returned when no
+// transaction has been attested.
+func (t *TransactionRepository) GetLastAttestedSeq() (uint64, error) {
+	data, err := t.kv.Get(keyLastAttestedSeq[:])
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get last attested sequence from db")
+	}
+	if len(data) != 8 {
+		return 0, errors.Errorf("last attested sequence has invalid length %d", len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// GetCommittedTxID returns the ID of the transaction that was committed at
+// the provided sequence number. A NotFoundError is returned when no
+// transaction was committed at the sequence.
+func (t *TransactionRepository) GetCommittedTxID(seq uint64) (transaction.ID, error) {
+	txid, err := t.kv.Get(commitSeqKey(seq))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get transaction committed at sequence %d from db", seq)
+	}
+	return txid, nil
 }
 
 var (
@@ -209,6 +637,15 @@ var (
 	keyConsumedStates = [...]byte{0x4}
 	keyReceipts       = [...]byte{0x5}
 	keyCommits        = [...]byte{0x6}
+	keyConsumers      = [...]byte{0x8}
+	keyRejections     = [...]byte{0x9}
+	keyCommitSeqs     = [...]byte{0xa}
+	keyOwnerIndex     = [...]byte{0xb}
+	keyKindIndex      = [...]byte{0xc}
+
+	// Statically defined keys.
+	keyLastCommittedSeq = [...]byte{0x7, 0x1}
+	keyLastAttestedSeq  = [...]byte{0x7, 0x2}
 )
 
 // transactionKey returns a db key for a transaction
@@ -244,6 +681,12 @@ func consumedStateKey(id transaction.StateID) []byte {
 	return buildKey(keyConsumedStates[:], id.TxID, id.OutputIndex)
 }
 
+// consumerKey returns a db key for the ID of the transaction that consumed
+// a state
+func consumerKey(id transaction.StateID) []byte {
+	return buildKey(keyConsumers[:], id.TxID, id.OutputIndex)
+}
+
 func receiptKey(receiptID []byte) []byte {
 	key := make([]byte, len(keyReceipts)+len(receiptID))
 	copy(key, keyReceipts[:])
@@ -251,9 +694,70 @@ func receiptKey(receiptID []byte) []byte {
 	return key
 }
 
+func uint64ToBytes(val uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, val)
+	return b
+}
+
 func commitKey(txid []byte) []byte {
 	key := make([]byte, len(keyCommits)+len(txid))
 	copy(key, keyCommits[:])
 	copy(key[len(keyCommits):], txid)
 	return key
 }
+
+func rejectionKey(txid []byte) []byte {
+	key := make([]byte, len(keyRejections)+len(txid))
+	copy(key, keyRejections[:])
+	copy(key[len(keyRejections):], txid)
+	return key
+}
+
+// commitSeqKey returns a db key for the ID of the transaction committed at a
+// sequence number
+func commitSeqKey(seq uint64) []byte {
+	key := make([]byte, len(keyCommitSeqs)+8)
+	copy(key, keyCommitSeqs[:])
+	binary.BigEndian.PutUint64(key[len(keyCommitSeqs):], seq)
+	return key
+}
+
+// ownerIndexPrefix returns the prefix of the owner index entries for the
+// states owned by a public key.
+func ownerIndexPrefix(publicKey []byte) []byte {
+	return indexPrefix(keyOwnerIndex[:], publicKey)
+}
+
+// kindIndexPrefix returns the prefix of the kind index entries for the states
+// of a kind.
+func kindIndexPrefix(kind string) []byte {
+	return indexPrefix(keyKindIndex[:], []byte(kind))
+}
+
+// indexPrefix builds an index prefix of the form:
+//  <prefix><uvarint-length><value>
+// The length ensures that the entries of one value are not matched by the
+// prefix of another.
+func indexPrefix(prefix, value []byte) []byte {
+	key := make([]byte, len(prefix)+binary.MaxVarintLen64+len(value))
+	n := copy(key, prefix)
+	n += binary.PutUvarint(key[n:], uint64(len(value)))
+	n += copy(key[n:], value)
+	return key[:n]
+}
+
+// indexKey returns a db key for the index entry of a state of the form:
+//  <index-prefix><txid><big-endian-uint64>
+func indexKey(prefix []byte, id transaction.StateID) []byte {
+	return buildKey(prefix, id.TxID, id.OutputIndex)
+}
+
+// indexedStateID returns the ID of the state referenced by a key built with
+// buildKey.
+func indexedStateID(prefix, key []byte) transaction.StateID {
+	return transaction.StateID{
+		TxID:        transaction.ID(clone(key[len(prefix) : len(key)-8])),
+		OutputIndex: binary.BigEndian.Uint64(key[len(key)-8:]),
+	}
+}
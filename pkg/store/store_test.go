@@ -6,22 +6,19 @@ package store
 import (
 	"crypto"
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"google.golang.org/protobuf/proto"
 
 	txv1 "github.com/sykesm/batik/pkg/pb/tx/v1"
-	"github.com/sykesm/batik/pkg/tested"
 	"github.com/sykesm/batik/pkg/transaction"
 )
 
-func TestStoreReceipts(t *testing.T) {
+func testStoreReceipts(t *testing.T, store *TransactionRepository) {
 	gt := NewGomegaWithT(t)
 
-	store, cleanup := setupTestStore(t)
-	defer cleanup()
-
 	r := &transaction.Receipt{
 		TxID: []byte("txid"),
 		Signatures: []*transaction.Signature{
@@ -49,12 +46,9 @@ func TestStoreReceipts(t *testing.T) {
 	gt.Expect(nr).To(Equal(r))
 }
 
-func TestStoreCommits(t *testing.T) {
+func testStoreCommits(t *testing.T, store *TransactionRepository) {
 	gt := NewGomegaWithT(t)
 
-	store, cleanup := setupTestStore(t)
-	defer cleanup()
-
 	txid := transaction.ID([]byte("tx-id"))
 
 	c := &transaction.Committed{
@@ -74,11 +68,49 @@ func TestStoreCommits(t *testing.T) {
 	gt.Expect(nc).To(Equal(c))
 }
 
-func TestStoreTransaction(t *testing.T) {
+func testStoreRejections(t *testing.T, store *TransactionRepository) {
 	gt := NewGomegaWithT(t)
 
-	store, cleanup := setupTestStore(t)
-	defer cleanup()
+	txid := transaction.ID([]byte("tx-id"))
+
+	r := &transaction.Rejected{
+		SeqNo:     7,
+		ReceiptID: []byte("receiptid"),
+		Reason:    "validation failed",
+	}
+
+	_, err := store.GetRejected(txid)
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	err = store.PutRejected(txid, r)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	nr, err := store.GetRejected(txid)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(nr).To(Equal(r))
+
+	// Rejecting an ordered transaction records its sequence number.
+	_, err = store.GetLastCommittedSeq()
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	rejectedID := transaction.ID([]byte("rejected-tx-id"))
+	r = &transaction.Rejected{SeqNo: 9, ReceiptID: []byte("rejected-receiptid"), Reason: "state consumed"}
+	err = store.RejectTransaction(rejectedID, r)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	nr, err = store.GetRejected(rejectedID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(nr).To(Equal(r))
+	lastSeq, err := store.GetLastCommittedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(lastSeq).To(Equal(uint64(9)))
+	_, err = store.GetCommittedTxID(9)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func testStoreTransaction(t *testing.T, store *TransactionRepository) {
+	gt := NewGomegaWithT(t)
 
 	tx, err := transaction.New(crypto.SHA256, newTestTransaction())
 	gt.Expect(err).NotTo(HaveOccurred())
@@ -97,12 +129,9 @@ func TestStoreTransaction(t *testing.T) {
 	gt.Expect(tx).To(Equal(ntx))
 }
 
-func TestStoreState(t *testing.T) {
+func testStoreState(t *testing.T, store *TransactionRepository) {
 	gt := NewGomegaWithT(t)
 
-	store, cleanup := setupTestStore(t)
-	defer cleanup()
-
 	tx, err := transaction.New(crypto.SHA256, newTestTransaction())
 	gt.Expect(err).NotTo(HaveOccurred())
 	gt.Expect(tx.Outputs).To(HaveLen(2))
@@ -112,6 +141,8 @@ func TestStoreState(t *testing.T) {
 	_, err = store.GetState(state.ID, false)
 	gt.Expect(err).To(HaveOccurred())
 	gt.Expect(IsNotFound(err)).To(BeTrue())
+	gt.Expect(IsStateUnknown(err)).To(BeTrue())
+	gt.Expect(IsStateConsumed(err)).To(BeFalse())
 
 	_, err = store.GetState(state.ID, true)
 	gt.Expect(err).To(HaveOccurred())
@@ -120,6 +151,7 @@ func TestStoreState(t *testing.T) {
 	err = store.ConsumeState(state.ID)
 	gt.Expect(err).To(HaveOccurred())
 	gt.Expect(IsNotFound(err)).To(BeTrue())
+	gt.Expect(IsStateUnknown(err)).To(BeTrue())
 
 	// Put the state
 	err = store.PutState(state)
@@ -143,6 +175,15 @@ func TestStoreState(t *testing.T) {
 	_, err = store.GetState(state.ID, false)
 	gt.Expect(err).To(HaveOccurred())
 	gt.Expect(IsNotFound(err)).To(BeTrue())
+	gt.Expect(IsStateUnknown(err)).To(BeFalse())
+	var sce *StateConsumedError
+	gt.Expect(errors.As(err, &sce)).To(BeTrue())
+	gt.Expect(sce.StateID).To(Equal(state.ID))
+	gt.Expect(sce.ConsumedBy).To(BeNil())
+
+	// Verify it can not be consumed again
+	err = store.ConsumeState(state.ID)
+	gt.Expect(IsStateConsumed(err)).To(BeTrue())
 
 	// Verify it is reported consumed
 	nstate, err = store.GetState(state.ID, true)
@@ -150,18 +191,207 @@ func TestStoreState(t *testing.T) {
 	gt.Expect(nstate).To(Equal(state))
 }
 
-func setupTestStore(t *testing.T) (*TransactionRepository, func()) {
-	path, cleanup := tested.TempDir(t, "", "store")
-	db, err := NewLevelDB(path)
-	if err != nil {
-		cleanup()
-		t.Fatalf("could not create db: %s", err)
+func testStoreCommitTransaction(t *testing.T, store *TransactionRepository) {
+	gt := NewGomegaWithT(t)
+
+	tx, err := transaction.New(crypto.SHA256, newTestTransaction())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	input := &transaction.State{
+		ID:        *tx.Inputs[0],
+		StateInfo: &transaction.StateInfo{Kind: "input-kind"},
+		Data:      []byte("input-state"),
 	}
+	err = store.PutState(input)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	commit := &transaction.Committed{SeqNo: 3, ReceiptID: []byte("receipt-id")}
+
+	_, err = store.GetLastCommittedSeq()
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = store.GetCommittedTxID(3)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	// Nothing is written when an input can not be consumed.
+	err = store.CommitTransaction(tx.ID, commit, tx.Outputs, []transaction.StateID{*tx.Inputs[0], *tx.Inputs[1]})
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(IsStateUnknown(err)).To(BeTrue())
+
+	_, err = store.GetCommitted(tx.ID)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = store.GetState(tx.Outputs[0].ID, false)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = store.GetState(input.ID, false)
+	gt.Expect(err).NotTo(HaveOccurred())
 
-	return NewRepository(db), func() {
-		tested.Close(t, db)
-		cleanup()
+	err = store.CommitTransaction(tx.ID, commit, tx.Outputs, []transaction.StateID{input.ID})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	committed, err := store.GetCommitted(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed).To(Equal(commit))
+	for _, output := range tx.Outputs {
+		state, err := store.GetState(output.ID, false)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(state).To(Equal(output))
 	}
+	_, err = store.GetState(input.ID, false)
+	var sce *StateConsumedError
+	gt.Expect(errors.As(err, &sce)).To(BeTrue())
+	gt.Expect(sce.ConsumedBy).To(Equal(tx.ID))
+	gt.Expect(err).To(MatchError(ContainSubstring("state %s has been consumed by transaction %s", input.ID, tx.ID)))
+	consumed, err := store.GetState(input.ID, true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consumed).To(Equal(input))
+
+	lastSeq, err := store.GetLastCommittedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(lastSeq).To(Equal(uint64(3)))
+
+	committedID, err := store.GetCommittedTxID(3)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committedID).To(Equal(tx.ID))
+
+	// Attestations are added to the commit record after the commit.
+	_, err = store.GetLastAttestedSeq()
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	attestation := &transaction.Attestation{Namespace: "ns", SeqNo: 3, TxID: tx.ID, Signature: []byte("signature")}
+	err = store.PutAttestation(tx.ID, attestation)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	committed, err = store.GetCommitted(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed.Attestation).To(Equal(attestation))
+	lastAttested, err := store.GetLastAttestedSeq()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(lastAttested).To(Equal(uint64(3)))
+
+	err = store.PutAttestation(transaction.ID("missing"), attestation)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func testStoreView(t *testing.T, store *TransactionRepository) {
+	gt := NewGomegaWithT(t)
+
+	tx, err := transaction.New(crypto.SHA256, newTestTransaction())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	input := &transaction.State{
+		ID:        *tx.Inputs[0],
+		StateInfo: &transaction.StateInfo{Kind: "input-kind"},
+		Data:      []byte("input-state"),
+	}
+	err = store.PutState(input)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	view, err := store.NewView()
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer view.Release()
+
+	commit := &transaction.Committed{SeqNo: 1, ReceiptID: []byte("receipt-id")}
+	err = store.PutTransaction(tx)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = store.CommitTransaction(tx.ID, commit, tx.Outputs, []transaction.StateID{input.ID})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// The view does not observe the commit.
+	state, err := view.GetState(input.ID, false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(state).To(Equal(input))
+	_, err = view.GetState(input.ID, true)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = view.GetState(tx.Outputs[0].ID, false)
+	gt.Expect(IsStateUnknown(err)).To(BeTrue())
+	_, err = view.GetCommitted(tx.ID)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+	_, err = view.GetTransaction(tx.ID)
+	gt.Expect(IsNotFound(err)).To(BeTrue())
+
+	// A new view does.
+	latest, err := store.NewView()
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer latest.Release()
+
+	_, err = latest.GetState(input.ID, false)
+	gt.Expect(IsStateConsumed(err)).To(BeTrue())
+	state, err = latest.GetState(tx.Outputs[0].ID, false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(state).To(Equal(tx.Outputs[0]))
+	committed, err := latest.GetCommitted(tx.ID)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(committed).To(Equal(commit))
+}
+
+func testStoreIndexes(t *testing.T, store *TransactionRepository) {
+	gt := NewGomegaWithT(t)
+
+	tx, err := transaction.New(crypto.SHA256, newTestTransaction())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	input := &transaction.State{
+		ID: *tx.Inputs[0],
+		StateInfo: &transaction.StateInfo{
+			Kind:   "state-kind-0",
+			Owners: []*transaction.Party{{PublicKey: []byte("owner")}},
+		},
+		Data: []byte("input-state"),
+	}
+	err = store.PutState(input)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	states, err := store.GetStatesByOwner([]byte("owner"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal([]*transaction.State{input}))
+	states, err = store.GetStatesByKind("state-kind-0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal([]*transaction.State{input}))
+
+	// Owners that share a prefix are not matched.
+	states, err = store.GetStatesByOwner([]byte("owner-1"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(BeEmpty())
+	states, err = store.GetStatesByKind("state-kind")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(BeEmpty())
+
+	// Committing the transaction indexes the outputs and removes the input.
+	commit := &transaction.Committed{SeqNo: 1, ReceiptID: []byte("receipt-id")}
+	err = store.CommitTransaction(tx.ID, commit, tx.Outputs, []transaction.StateID{input.ID})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	states, err = store.GetStatesByOwner([]byte("owner"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(BeEmpty())
+	states, err = store.GetStatesByOwner([]byte("owner-2"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal(tx.Outputs))
+	states, err = store.GetStatesByKind("state-kind-0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal([]*transaction.State{tx.Outputs[0]}))
+
+	// Rebuilding replaces missing and stale entries.
+	keys, err := Keys(store.kv.NewIterator(&IterateOptions{Prefix: keyOwnerIndex[:]}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(keys).To(HaveLen(4))
+	for _, key := range keys {
+		gt.Expect(store.kv.Delete(key)).To(Succeed())
+	}
+	gt.Expect(store.kv.Put(indexKey(ownerIndexPrefix([]byte("owner")), input.ID), nil)).To(Succeed())
+
+	count, err := store.RebuildIndexes()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(count).To(Equal(2))
+
+	states, err = store.GetStatesByOwner([]byte("owner"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(BeEmpty())
+	states, err = store.GetStatesByOwner([]byte("owner-1"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal(tx.Outputs))
+	states, err = store.GetStatesByKind("state-kind-1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(states).To(Equal([]*transaction.State{tx.Outputs[1]}))
 }
 
 func newTestTransaction() *txv1.Transaction {
@@ -229,4 +459,20 @@ func TestKeyEncodings(t *testing.T) {
 
 	scKey := consumedStateKey(stateID)
 	gt.Expect(scKey).To(Equal(fromHex(t, "04deadbeef0000000000000001")))
+
+	cKey := consumerKey(stateID)
+	gt.Expect(cKey).To(Equal(fromHex(t, "08deadbeef0000000000000001")))
+
+	rKey := rejectionKey(txID)
+	gt.Expect(rKey).To(Equal(fromHex(t, "09deadbeef")))
+
+	csKey := commitSeqKey(258)
+	gt.Expect(csKey).To(Equal(fromHex(t, "0a0000000000000102")))
+
+	oiKey := indexKey(ownerIndexPrefix(fromHex(t, "cafe")), stateID)
+	gt.Expect(oiKey).To(Equal(fromHex(t, "0b02cafedeadbeef0000000000000001")))
+	gt.Expect(indexedStateID(ownerIndexPrefix(fromHex(t, "cafe")), oiKey)).To(Equal(stateID))
+
+	kiKey := indexKey(kindIndexPrefix("kind"), stateID)
+	gt.Expect(kiKey).To(Equal(fromHex(t, "0c046b696e64deadbeef0000000000000001")))
 }
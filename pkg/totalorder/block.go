@@ -0,0 +1,198 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/merkle"
+	"github.com/sykesm/batik/pkg/store"
+)
+
+// A Block is a batch of consecutive log entries that were appended to the
+// log together.
+type Block struct {
+	// Number is the position of the block among all blocks in the log.
+	Number uint64
+	// FirstSeq is the sequence number of the first entry in the block.
+	FirstSeq uint64
+	// Count is the number of entries in the block.
+	Count uint64
+	// MerkleRoot is the merkle root of the serialized entries in the block.
+	MerkleRoot []byte
+}
+
+func (b *Block) serialize() []byte {
+	return append(append(uint64ToBytes(b.FirstSeq), uint64ToBytes(b.Count)...), b.MerkleRoot...)
+}
+
+func blockFromBytes(number uint64, b []byte) (Block, error) {
+	if len(b) < 16 {
+		return Block{}, errors.WithMessagef(ErrInconsistent, "block %d has invalid length %d", number, len(b))
+	}
+
+	return Block{
+		Number:     number,
+		FirstSeq:   bytesToUint64(b[:8]),
+		Count:      bytesToUint64(b[8:16]),
+		MerkleRoot: b[16:],
+	}, nil
+}
+
+// AppendBlock appends entries to the log as a single block. The entries, the
+// block, and the updated metadata are persisted with one write batch.
+func (s *Store) AppendBlock(entries []TXIDAndHMAC) (Block, error) {
+	if len(entries) == 0 {
+		return Block{}, errors.New("block must contain at least one entry")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	batch := s.kv.NewWriteBatch()
+	leaves := make([][]byte, 0, len(entries))
+	accumulator := s.accumulator
+	for i, t := range entries {
+		tahBytes := t.serialize()
+		leaves = append(leaves, tahBytes)
+
+		h := s.hasher.New()
+		h.Write(accumulator)
+		h.Write(tahBytes)
+		accumulator = h.Sum(nil)
+
+		seq := s.nextSequence + uint64(i)
+		batch.Put(txKey(seq), tahBytes)
+		batch.Put(accumulatorKey(seq), accumulator)
+	}
+
+	block := Block{
+		Number:     s.nextBlock,
+		FirstSeq:   s.nextSequence,
+		Count:      uint64(len(entries)),
+		MerkleRoot: merkle.Root(s.hasher, leaves...),
+	}
+	lastSeq := s.nextSequence + block.Count - 1
+
+	batch.Put(blockKey(block.Number), block.serialize())
+	batch.Put(keyMetadataLastBlock, uint64ToBytes(block.Number))
+	batch.Put(keyMetadataLastCommitted, uint64ToBytes(lastSeq))
+	batch.Put(keyMetadataAccumulator, accumulator)
+	if err := batch.Commit(); err != nil {
+		return Block{}, errors.WithMessagef(err, "could not persist block %d", block.Number)
+	}
+
+	for seq := s.nextSequence; seq <= lastSeq; seq++ {
+		if waitC, ok := s.waitCs[seq]; ok {
+			close(waitC)
+			delete(s.waitCs, seq)
+		}
+	}
+	s.nextSequence = lastSeq + 1
+	s.nextBlock++
+	s.accumulator = accumulator
+	return block, nil
+}
+
+// Block returns the block with the provided number.
+func (s *Store) Block(number uint64) (Block, error) {
+	value, err := s.kv.Get(blockKey(number))
+	if err != nil {
+		return Block{}, errors.WithMessagef(err, "could not get block %d", number)
+	}
+	return blockFromBytes(number, value)
+}
+
+// recoverBlocks restores the next block number from the metadata persisted by
+// AppendBlock. The merkle root of the last block is checked against its
+// entries.
+func (s *Store) recoverBlocks(lastSeq uint64) error {
+	lastBlock, err := s.kv.Get(keyMetadataLastBlock)
+	if store.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not read last block")
+	}
+	if len(lastBlock) != 8 {
+		return errors.WithMessagef(ErrInconsistent, "last block has invalid length %d", len(lastBlock))
+	}
+
+	block, err := s.Block(bytesToUint64(lastBlock))
+	if store.IsNotFound(err) {
+		return errors.WithMessagef(ErrInconsistent, "block missing for last block %d", bytesToUint64(lastBlock))
+	}
+	if err != nil {
+		return err
+	}
+	if block.FirstSeq+block.Count-1 > lastSeq {
+		return errors.WithMessagef(ErrInconsistent, "block %d extends beyond last committed sequence %d", block.Number, lastSeq)
+	}
+	if err := s.verifyBlock(block); err != nil {
+		return err
+	}
+
+	s.nextBlock = block.Number + 1
+	return nil
+}
+
+// verifyBlocks checks the merkle root of every block through the last block
+// persisted by AppendBlock against the entries it contains.
+func (s *Store) verifyBlocks() error {
+	lastBlock, err := s.kv.Get(keyMetadataLastBlock)
+	if store.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not read last block")
+	}
+	if len(lastBlock) != 8 {
+		return errors.WithMessagef(ErrInconsistent, "last block has invalid length %d", len(lastBlock))
+	}
+
+	for number := uint64(0); number <= bytesToUint64(lastBlock); number++ {
+		block, err := s.Block(number)
+		if store.IsNotFound(err) {
+			return errors.WithMessagef(ErrInconsistent, "block %d missing", number)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.verifyBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBlock recomputes the merkle root of the entries in a block and
+// compares it with the persisted root.
+func (s *Store) verifyBlock(block Block) error {
+	if block.Count == 0 {
+		return errors.WithMessagef(ErrInconsistent, "block %d is empty", block.Number)
+	}
+
+	leaves := make([][]byte, 0, block.Count)
+	for seq := block.FirstSeq; seq < block.FirstSeq+block.Count; seq++ {
+		value, err := s.kv.Get(txKey(seq))
+		if store.IsNotFound(err) {
+			return errors.WithMessagef(ErrInconsistent, "entry missing for seq %d in block %d", seq, block.Number)
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "could not get key for seq %d", seq)
+		}
+		leaves = append(leaves, value)
+	}
+
+	if !bytes.Equal(merkle.Root(s.hasher, leaves...), block.MerkleRoot) {
+		return errors.WithMessagef(ErrInconsistent, "merkle root mismatch for block %d", block.Number)
+	}
+	return nil
+}
+
+func blockKey(number uint64) []byte {
+	return append(keyBlocks[:], uint64ToBytes(number)...)
+}
@@ -0,0 +1,267 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/merkle"
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func TestStoreAppendBlock(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorder-block")
+	defer cleanup()
+
+	db, err := store.NewLevelDB(path)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orderStore, err := NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orderStore.Append(testEntry(0))).To(Succeed())
+
+	_, err = orderStore.AppendBlock(nil)
+	gt.Expect(err).To(MatchError("block must contain at least one entry"))
+
+	entries := []TXIDAndHMAC{testEntry(1), testEntry(2), testEntry(3)}
+	block, err := orderStore.AppendBlock(entries)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(block).To(Equal(Block{
+		Number:     0,
+		FirstSeq:   1,
+		Count:      3,
+		MerkleRoot: merkle.Root(crypto.SHA256, entries[0].serialize(), entries[1].serialize(), entries[2].serialize()),
+	}))
+
+	for seq := uint64(0); seq < 4; seq++ {
+		tah, err := orderStore.Get(context.Background(), seq)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(tah).To(Equal(testEntry(int(seq))))
+	}
+
+	// Blocks and entries appended on their own extend the same hash chain.
+	checkpoint, err := orderStore.Verify()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(checkpoint.Seq).To(Equal(uint64(3)))
+
+	stored, err := orderStore.Block(0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stored).To(Equal(block))
+
+	_, err = orderStore.Block(1)
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	tested.Close(t, db)
+
+	db, err = store.NewLevelDB(path)
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	orderStore, err = NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orderStore.nextSequence).To(Equal(uint64(4)))
+	gt.Expect(orderStore.nextBlock).To(Equal(uint64(1)))
+
+	block, err = orderStore.AppendBlock([]TXIDAndHMAC{testEntry(4)})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(block.Number).To(Equal(uint64(1)))
+	gt.Expect(block.FirstSeq).To(Equal(uint64(4)))
+}
+
+func TestStoreRecoverBlocksInconsistent(t *testing.T) {
+	tests := map[string]struct {
+		corrupt     func(kv store.KV)
+		errContains string
+	}{
+		"invalid last block": {
+			corrupt:     func(kv store.KV) { kv.Put(keyMetadataLastBlock, []byte("short")) },
+			errContains: "last block has invalid length 5",
+		},
+		"missing block": {
+			corrupt:     func(kv store.KV) { kv.Delete(blockKey(0)) },
+			errContains: "block missing for last block 0",
+		},
+		"truncated block": {
+			corrupt:     func(kv store.KV) { kv.Put(blockKey(0), []byte("short")) },
+			errContains: "block 0 has invalid length 5",
+		},
+		"forged merkle root": {
+			corrupt: func(kv store.KV) {
+				block := Block{FirstSeq: 0, Count: 2, MerkleRoot: sHash("forged")}
+				kv.Put(blockKey(0), block.serialize())
+			},
+			errContains: "merkle root mismatch for block 0",
+		},
+		"forged entry with consistent hash chain": {
+			corrupt:     func(kv store.KV) { forgeLog(kv, testEntry(0), testEntry(9)) },
+			errContains: "merkle root mismatch for block 0",
+		},
+		"block beyond last committed": {
+			corrupt: func(kv store.KV) {
+				block := Block{FirstSeq: 1, Count: 2, MerkleRoot: sHash("root")}
+				kv.Put(blockKey(0), block.serialize())
+			},
+			errContains: "block 0 extends beyond last committed sequence 1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			db, err := store.NewLevelDB("")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer tested.Close(t, db)
+
+			orderStore, err := NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+			_, err = orderStore.AppendBlock([]TXIDAndHMAC{testEntry(0), testEntry(1)})
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			tt.corrupt(db)
+
+			_, err = NewStore(crypto.SHA256, db)
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+		})
+	}
+}
+
+func TestStoreVerifyBlocks(t *testing.T) {
+	tests := map[string]struct {
+		tamper      func(kv store.KV)
+		errContains string
+	}{
+		"untouched": {
+			tamper: func(kv store.KV) {},
+		},
+		"forged merkle root": {
+			tamper: func(kv store.KV) {
+				block := Block{FirstSeq: 3, Count: 1, MerkleRoot: sHash("forged")}
+				kv.Put(blockKey(1), block.serialize())
+			},
+			errContains: "merkle root mismatch for block 1",
+		},
+		"forged entry with consistent hash chain": {
+			tamper: func(kv store.KV) {
+				forgeLog(kv, testEntry(0), testEntry(1), testEntry(9), testEntry(3))
+			},
+			errContains: "merkle root mismatch for block 0",
+		},
+		"missing block": {
+			tamper:      func(kv store.KV) { kv.Delete(blockKey(0)) },
+			errContains: "block 0 missing",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			s, db := newCheckpointStore(t, testEntry(0))
+			_, err := s.AppendBlock([]TXIDAndHMAC{testEntry(1), testEntry(2)})
+			gt.Expect(err).NotTo(HaveOccurred())
+			_, err = s.AppendBlock([]TXIDAndHMAC{testEntry(3)})
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			tt.tamper(db)
+
+			checkpoint, err := s.Verify()
+			if tt.errContains != "" {
+				gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+				gt.Expect(errors.Is(err, ErrInconsistent)).To(BeTrue())
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(checkpoint.Seq).To(Equal(uint64(3)))
+		})
+	}
+}
+
+// forgeLog replaces the entries of the log and rewrites the hash chain and
+// the persisted accumulator to match them.
+func forgeLog(kv store.KV, entries ...TXIDAndHMAC) {
+	var accumulator []byte
+	for seq, e := range entries {
+		h := crypto.SHA256.New()
+		h.Write(accumulator)
+		h.Write(e.serialize())
+		accumulator = h.Sum(nil)
+
+		kv.Put(txKey(uint64(seq)), e.serialize())
+		kv.Put(accumulatorKey(uint64(seq)), accumulator)
+	}
+	kv.Put(keyMetadataAccumulator, accumulator)
+}
+
+func TestInProcessBatching(t *testing.T) {
+	tests := map[string]struct {
+		batch  BatchConfig
+		count  int
+		blocks []uint64
+	}{
+		"max entries": {
+			batch:  BatchConfig{MaxEntries: 3, Timeout: time.Minute},
+			count:  6,
+			blocks: []uint64{3, 3},
+		},
+		"max bytes": {
+			batch:  BatchConfig{MaxEntries: 10, MaxBytes: 128, Timeout: time.Minute},
+			count:  4,
+			blocks: []uint64{2, 2},
+		},
+		"timeout": {
+			batch:  BatchConfig{MaxEntries: 10, Timeout: 100 * time.Millisecond},
+			count:  4,
+			blocks: []uint64{4},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			db, err := store.NewLevelDB("")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer tested.Close(t, db)
+
+			orderStore, err := NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			ip := NewBatchingInProcess(orderStore, tt.batch)
+			defer ip.Stop()
+
+			for i := 0; i < tt.count; i++ {
+				err := ip.Broadcast(context.Background(), testEntry(i))
+				gt.Expect(err).NotTo(HaveOccurred())
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for i := 0; i < tt.count; i++ {
+				tah, err := ip.Deliver(ctx, uint64(i))
+				gt.Expect(err).NotTo(HaveOccurred())
+				gt.Expect(tah).To(Equal(testEntry(i)))
+			}
+
+			var firstSeq uint64
+			for number, count := range tt.blocks {
+				block, err := orderStore.Block(uint64(number))
+				gt.Expect(err).NotTo(HaveOccurred())
+				gt.Expect(block.FirstSeq).To(Equal(firstSeq))
+				gt.Expect(block.Count).To(Equal(count))
+				firstSeq += count
+			}
+			_, err = orderStore.Block(uint64(len(tt.blocks)))
+			gt.Expect(store.IsNotFound(err)).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/sykesm/batik/pkg/store"
+)
+
+var (
+	// ErrUncommitted indicates that a sequence number has not been ordered.
+	ErrUncommitted = errors.New("sequence has not been committed")
+
+	// ErrDiverged indicates that two logs contain different entries.
+	ErrDiverged = errors.New("total order logs have diverged")
+)
+
+// A Checkpoint identifies the contents of a log through a sequence number.
+// The accumulator is the hash chain over every entry up to and including the
+// entry at Seq; two logs with the same checkpoint contain the same entries.
+type Checkpoint struct {
+	Seq         uint64
+	Accumulator []byte
+}
+
+// A Checkpointer provides checkpoints for a total order log.
+type Checkpointer interface {
+	// Checkpoint returns the checkpoint for the entry at seq.
+	Checkpoint(ctx context.Context, seq uint64) (Checkpoint, error)
+	// LatestCheckpoint returns the checkpoint for the last committed entry.
+	LatestCheckpoint(ctx context.Context) (Checkpoint, error)
+}
+
+// Checkpoint returns the checkpoint for the entry at seq. An error wrapping
+// ErrUncommitted is returned when the entry does not exist.
+func (s *Store) Checkpoint(seq uint64) (Checkpoint, error) {
+	if seq >= s.length() {
+		return Checkpoint{}, errors.WithMessagef(ErrUncommitted, "no checkpoint for seq %d", seq)
+	}
+
+	accumulator, err := s.kv.Get(accumulatorKey(seq))
+	if store.IsNotFound(err) {
+		// Logs written before checkpoints were persisted must be replayed.
+		return s.replay(seq, nil)
+	}
+	if err != nil {
+		return Checkpoint{}, errors.WithMessagef(err, "could not read checkpoint for seq %d", seq)
+	}
+
+	return Checkpoint{Seq: seq, Accumulator: accumulator}, nil
+}
+
+// LatestCheckpoint returns the checkpoint for the last entry in the log. An
+// error wrapping ErrUncommitted is returned when the log is empty.
+func (s *Store) LatestCheckpoint() (Checkpoint, error) {
+	s.mutex.Lock()
+	next, accumulator := s.nextSequence, s.accumulator
+	s.mutex.Unlock()
+
+	if next == 0 {
+		return Checkpoint{}, errors.WithMessage(ErrUncommitted, "log is empty")
+	}
+	return Checkpoint{Seq: next - 1, Accumulator: accumulator}, nil
+}
+
+// Verify replays the log from the first entry and confirms that the hash
+// chain agrees with every persisted checkpoint and the persisted accumulator.
+// The merkle root of every block is recomputed from its entries and compared
+// with the persisted root. The checkpoint of the last verified entry is
+// returned, or nil when the log is empty. An error wrapping ErrInconsistent
+// is returned when the log has been altered.
+func (s *Store) Verify() (*Checkpoint, error) {
+	length := s.length()
+	if length == 0 {
+		return nil, nil
+	}
+
+	checkpoint, err := s.replay(length-1, func(c Checkpoint) error {
+		persisted, err := s.kv.Get(accumulatorKey(c.Seq))
+		if store.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "could not read checkpoint for seq %d", c.Seq)
+		}
+		if !bytes.Equal(persisted, c.Accumulator) {
+			return errors.WithMessagef(ErrInconsistent, "checkpoint mismatch at seq %d", c.Seq)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries may have been appended during the replay; the persisted
+	// accumulator can only be compared when they have not.
+	lastCommitted, err := s.kv.Get(keyMetadataLastCommitted)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read last committed sequence")
+	}
+	if bytesToUint64(lastCommitted) == checkpoint.Seq {
+		accumulator, err := s.kv.Get(keyMetadataAccumulator)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not read accumulator")
+		}
+		if !bytes.Equal(accumulator, checkpoint.Accumulator) {
+			return nil, errors.WithMessagef(ErrInconsistent, "accumulator mismatch at seq %d", checkpoint.Seq)
+		}
+	}
+
+	if err := s.verifyBlocks(); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// replay computes the hash chain from the first entry through seq. The
+// optional check is called with the checkpoint for each entry.
+func (s *Store) replay(seq uint64, check func(Checkpoint) error) (Checkpoint, error) {
+	var accumulator []byte
+	for i := uint64(0); i <= seq; i++ {
+		value, err := s.kv.Get(txKey(i))
+		if store.IsNotFound(err) {
+			return Checkpoint{}, errors.WithMessagef(ErrInconsistent, "entry missing for seq %d", i)
+		}
+		if err != nil {
+			return Checkpoint{}, errors.WithMessagef(err, "could not get key for seq %d", i)
+		}
+		if len(value) != 64 {
+			return Checkpoint{}, errors.WithMessagef(ErrInconsistent, "entry for seq %d has invalid length %d", i, len(value))
+		}
+
+		h := s.hasher.New()
+		h.Write(accumulator)
+		h.Write(value)
+		accumulator = h.Sum(nil)
+
+		if check != nil {
+			if err := check(Checkpoint{Seq: i, Accumulator: accumulator}); err != nil {
+				return Checkpoint{}, err
+			}
+		}
+	}
+
+	return Checkpoint{Seq: seq, Accumulator: accumulator}, nil
+}
+
+// Compare determines whether two logs agree by comparing checkpoints for the
+// last entry they have in common. The common checkpoint is returned when they
+// agree. When they do not, the first sequence number with a different entry
+// is located and returned in an error wrapping ErrDiverged.
+func Compare(ctx context.Context, a, b Checkpointer) (Checkpoint, error) {
+	latestA, err := a.LatestCheckpoint(ctx)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	latestB, err := b.LatestCheckpoint(ctx)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	seq := latestA.Seq
+	if latestB.Seq < seq {
+		seq = latestB.Seq
+	}
+	agree, common, err := agreeAt(ctx, a, b, seq)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if agree {
+		return common, nil
+	}
+
+	// The logs agree up to some point and disagree from there on.
+	low, high := uint64(0), seq
+	for low < high {
+		mid := low + (high-low)/2
+		agree, _, err := agreeAt(ctx, a, b, mid)
+		if err != nil {
+			return Checkpoint{}, err
+		}
+		if agree {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return Checkpoint{}, errors.WithMessagef(ErrDiverged, "first difference at seq %d", low)
+}
+
+func agreeAt(ctx context.Context, a, b Checkpointer, seq uint64) (bool, Checkpoint, error) {
+	checkpointA, err := a.Checkpoint(ctx, seq)
+	if err != nil {
+		return false, Checkpoint{}, err
+	}
+	checkpointB, err := b.Checkpoint(ctx, seq)
+	if err != nil {
+		return false, Checkpoint{}, err
+	}
+	return bytes.Equal(checkpointA.Accumulator, checkpointB.Accumulator), checkpointA, nil
+}
@@ -0,0 +1,224 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+func newCheckpointStore(t *testing.T, entries ...TXIDAndHMAC) (*Store, *store.LevelDBKV) {
+	gt := NewGomegaWithT(t)
+
+	db, err := store.NewLevelDB("")
+	gt.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { tested.Close(t, db) })
+
+	s, err := NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	for _, e := range entries {
+		gt.Expect(s.Append(e)).To(Succeed())
+	}
+	return s, db
+}
+
+func TestStoreCheckpoint(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	s, db := newCheckpointStore(t, testEntry(0), testEntry(1), testEntry(2))
+
+	var accumulator []byte
+	for seq := 0; seq < 3; seq++ {
+		e := testEntry(seq)
+		accumulator = bHash(append(accumulator, e.serialize()...))
+
+		checkpoint, err := s.Checkpoint(uint64(seq))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(checkpoint).To(Equal(Checkpoint{Seq: uint64(seq), Accumulator: accumulator}))
+	}
+
+	latest, err := s.LatestCheckpoint()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(latest).To(Equal(Checkpoint{Seq: 2, Accumulator: accumulator}))
+
+	_, err = s.Checkpoint(3)
+	gt.Expect(err).To(MatchError("no checkpoint for seq 3: sequence has not been committed"))
+	gt.Expect(errors.Is(err, ErrUncommitted)).To(BeTrue())
+
+	// Logs without persisted checkpoints are replayed.
+	expected, err := s.Checkpoint(1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(db.Delete(accumulatorKey(1))).To(Succeed())
+	checkpoint, err := s.Checkpoint(1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(checkpoint).To(Equal(expected))
+
+	empty, _ := newCheckpointStore(t)
+	_, err = empty.LatestCheckpoint()
+	gt.Expect(errors.Is(err, ErrUncommitted)).To(BeTrue())
+}
+
+func TestStoreVerify(t *testing.T) {
+	tests := map[string]struct {
+		tamper      func(kv store.KV)
+		errContains string
+	}{
+		"untouched": {
+			tamper: func(kv store.KV) {},
+		},
+		"legacy log without checkpoints": {
+			tamper: func(kv store.KV) {
+				for seq := uint64(0); seq < 4; seq++ {
+					kv.Delete(accumulatorKey(seq))
+				}
+			},
+		},
+		"replaced entry": {
+			tamper: func(kv store.KV) {
+				forged := testEntry(9)
+				kv.Put(txKey(1), forged.serialize())
+			},
+			errContains: "checkpoint mismatch at seq 1",
+		},
+		"replaced checkpoint": {
+			tamper:      func(kv store.KV) { kv.Put(accumulatorKey(2), sHash("forged")) },
+			errContains: "checkpoint mismatch at seq 2",
+		},
+		"replaced accumulator": {
+			tamper: func(kv store.KV) {
+				kv.Delete(accumulatorKey(3))
+				kv.Put(keyMetadataAccumulator, sHash("forged"))
+			},
+			errContains: "accumulator mismatch at seq 3",
+		},
+		"missing entry": {
+			tamper:      func(kv store.KV) { kv.Delete(txKey(2)) },
+			errContains: "entry missing for seq 2",
+		},
+		"truncated entry": {
+			tamper:      func(kv store.KV) { kv.Put(txKey(0), []byte("short")) },
+			errContains: "entry for seq 0 has invalid length 5",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			s, db := newCheckpointStore(t, testEntry(0), testEntry(1), testEntry(2), testEntry(3))
+			expected, err := s.LatestCheckpoint()
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			tt.tamper(db)
+
+			checkpoint, err := s.Verify()
+			if tt.errContains != "" {
+				gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+				gt.Expect(errors.Is(err, ErrInconsistent)).To(BeTrue())
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(checkpoint).To(Equal(&expected))
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+		s, _ := newCheckpointStore(t)
+		checkpoint, err := s.Verify()
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(checkpoint).To(BeNil())
+	})
+}
+
+type storeCheckpointer struct{ *Store }
+
+func (s storeCheckpointer) Checkpoint(_ context.Context, seq uint64) (Checkpoint, error) {
+	return s.Store.Checkpoint(seq)
+}
+
+func (s storeCheckpointer) LatestCheckpoint(context.Context) (Checkpoint, error) {
+	return s.Store.LatestCheckpoint()
+}
+
+func TestCompare(t *testing.T) {
+	entries := func(count int, replace map[int]int) []TXIDAndHMAC {
+		var result []TXIDAndHMAC
+		for i := 0; i < count; i++ {
+			if r, ok := replace[i]; ok {
+				result = append(result, testEntry(r))
+				continue
+			}
+			result = append(result, testEntry(i))
+		}
+		return result
+	}
+
+	tests := map[string]struct {
+		a, b        []TXIDAndHMAC
+		expectedSeq uint64
+		errContains string
+	}{
+		"identical": {
+			a:           entries(10, nil),
+			b:           entries(10, nil),
+			expectedSeq: 9,
+		},
+		"behind": {
+			a:           entries(10, nil),
+			b:           entries(4, nil),
+			expectedSeq: 3,
+		},
+		"diverged": {
+			a:           entries(10, nil),
+			b:           entries(10, map[int]int{6: 100}),
+			errContains: "first difference at seq 6",
+		},
+		"diverged at start": {
+			a:           entries(7, map[int]int{0: 100}),
+			b:           entries(10, nil),
+			errContains: "first difference at seq 0",
+		},
+		"diverged at end": {
+			a:           entries(10, nil),
+			b:           entries(5, map[int]int{4: 100}),
+			errContains: "first difference at seq 4",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+			a, _ := newCheckpointStore(t, tt.a...)
+			b, _ := newCheckpointStore(t, tt.b...)
+
+			common, err := Compare(context.Background(), storeCheckpointer{a}, storeCheckpointer{b})
+			if tt.errContains != "" {
+				gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+				gt.Expect(errors.Is(err, ErrDiverged)).To(BeTrue())
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(common.Seq).To(Equal(tt.expectedSeq))
+
+			expected, err := a.Checkpoint(tt.expectedSeq)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(common).To(Equal(expected))
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+		a, _ := newCheckpointStore(t, testEntry(0))
+		b, _ := newCheckpointStore(t)
+
+		_, err := Compare(context.Background(), storeCheckpointer{a}, storeCheckpointer{b})
+		gt.Expect(errors.Is(err, ErrUncommitted)).To(BeTrue())
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+)
+
+// ErrUnavailable indicates that a remote total order could not be reached.
+// Operations that fail with ErrUnavailable may be retried.
+var ErrUnavailable = errors.New("total order is unavailable")
+
+// GRPC is a total order client for a total order that is hosted by another
+// node and exposed through the OrderingAPI.
+type GRPC struct {
+	client orderv1.OrderingAPIClient
+	name   string
+
+	mutex   sync.Mutex
+	streams map[uint64][]*deliverStream
+}
+
+// A deliverStream is an open Deliver stream and the function that cancels
+// it.
+type deliverStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	stream orderv1.OrderingAPI_DeliverClient
+}
+
+// NewGRPC creates a total order client for the total order with the provided
+// name on the remote node.
+func NewGRPC(cc grpc.ClientConnInterface, name string) *GRPC {
+	return &GRPC{
+		client:  orderv1.NewOrderingAPIClient(cc),
+		name:    name,
+		streams: map[uint64][]*deliverStream{},
+	}
+}
+
+// Broadcast submits an entry to the remote total order.
+func (g *GRPC) Broadcast(ctx context.Context, t TXIDAndHMAC) error {
+	_, err := g.client.Broadcast(ctx, &orderv1.BroadcastRequest{
+		TotalOrder: g.name,
+		Entry: &orderv1.Entry{
+			Txid: t.ID,
+			Hmac: t.HMAC,
+		},
+	})
+	return err
+}
+
+// Deliver blocks until the entry at seq has been ordered by the remote total
+// order and returns it.
+//
+// Entries are received over Deliver streams that are bound to the context of
+// the call that opened them. A stream is used by one caller at a time and is
+// kept for reuse when Deliver is next called with the following sequence
+// number, as is done when processing the order. Callers delivering different
+// parts of the order use separate streams and do not block each other. When
+// the remote node can not be reached, the error wraps ErrUnavailable.
+func (g *GRPC) Deliver(ctx context.Context, seq uint64) (TXIDAndHMAC, error) {
+	s := g.takeStream(seq)
+	if s == nil {
+		var err error
+		if s, err = g.openStream(ctx, seq); err != nil {
+			return TXIDAndHMAC{}, err
+		}
+	}
+
+	resp, err := s.recv(ctx)
+	if err != nil {
+		s.cancel()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return TXIDAndHMAC{}, errors.WithMessagef(unavailable(err), "failed to receive seq %d", seq)
+	}
+	if resp.Seq != seq || resp.Entry == nil {
+		s.cancel()
+		return TXIDAndHMAC{}, errors.Errorf("received unexpected entry for seq %d, expected %d", resp.Seq, seq)
+	}
+
+	g.putStream(seq+1, s)
+	return TXIDAndHMAC{
+		ID:   resp.Entry.Txid,
+		HMAC: resp.Entry.Hmac,
+	}, nil
+}
+
+// Checkpoint returns the checkpoint of the remote total order for the entry
+// at seq.
+func (g *GRPC) Checkpoint(ctx context.Context, seq uint64) (Checkpoint, error) {
+	return g.checkpoint(ctx, &orderv1.CheckpointRequest{TotalOrder: g.name, Seq: seq})
+}
+
+// LatestCheckpoint returns the checkpoint of the remote total order for the
+// last ordered entry.
+func (g *GRPC) LatestCheckpoint(ctx context.Context) (Checkpoint, error) {
+	return g.checkpoint(ctx, &orderv1.CheckpointRequest{TotalOrder: g.name, Latest: true})
+}
+
+func (g *GRPC) checkpoint(ctx context.Context, req *orderv1.CheckpointRequest) (Checkpoint, error) {
+	resp, err := g.client.Checkpoint(ctx, req)
+	if status.Code(err) == codes.OutOfRange {
+		return Checkpoint{}, errors.WithMessage(ErrUncommitted, status.Convert(err).Message())
+	}
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	return Checkpoint{Seq: resp.Seq, Accumulator: resp.Accumulator}, nil
+}
+
+func (g *GRPC) openStream(ctx context.Context, seq uint64) (*deliverStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := g.client.Deliver(ctx, &orderv1.DeliverRequest{
+		TotalOrder: g.name,
+		FromSeq:    seq,
+	})
+	if err != nil {
+		cancel()
+		return nil, errors.WithMessagef(unavailable(err), "failed to open deliver stream from seq %d", seq)
+	}
+	return &deliverStream{ctx: ctx, cancel: cancel, stream: stream}, nil
+}
+
+// takeStream removes an idle stream positioned at seq from the pool. Streams
+// whose context has ended are discarded.
+func (g *GRPC) takeStream(seq uint64) *deliverStream {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for streams := g.streams[seq]; len(streams) > 0; streams = g.streams[seq] {
+		s := streams[len(streams)-1]
+		if len(streams) == 1 {
+			delete(g.streams, seq)
+		} else {
+			g.streams[seq] = streams[:len(streams)-1]
+		}
+		if s.ctx.Err() == nil {
+			return s
+		}
+		s.cancel()
+	}
+	return nil
+}
+
+// putStream returns a stream positioned at seq to the pool and releases
+// idle streams whose context has ended.
+func (g *GRPC) putStream(seq uint64, s *deliverStream) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for next, streams := range g.streams {
+		live := streams[:0]
+		for _, idle := range streams {
+			if idle.ctx.Err() == nil {
+				live = append(live, idle)
+			} else {
+				idle.cancel()
+			}
+		}
+		if len(live) == 0 {
+			delete(g.streams, next)
+		} else {
+			g.streams[next] = live
+		}
+	}
+	g.streams[seq] = append(g.streams[seq], s)
+}
+
+// unavailable converts an error reporting that the remote node could not be
+// reached to ErrUnavailable.
+func unavailable(err error) error {
+	if status.Code(err) == codes.Unavailable {
+		return errors.WithMessage(ErrUnavailable, status.Convert(err).Message())
+	}
+	return err
+}
+
+// recv receives the next response from the stream. The stream is cancelled
+// if ctx ends before a response is received.
+func (s *deliverStream) recv(ctx context.Context) (*orderv1.DeliverResponse, error) {
+	doneC := make(chan struct{})
+	defer close(doneC)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.cancel()
+		case <-doneC:
+		}
+	}()
+	return s.stream.Recv()
+}
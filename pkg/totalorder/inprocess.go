@@ -5,52 +5,215 @@ package totalorder
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// DefaultBatchTimeout is the batch timeout used when a BatchConfig does not
+// specify one.
+const DefaultBatchTimeout = 50 * time.Millisecond
+
+// BatchConfig is the policy used to cut blocks from broadcast entries. A
+// block is cut when it holds MaxEntries entries, when adding another entry
+// would exceed MaxBytes, or when Timeout has passed since the first entry of
+// the block was received.
+type BatchConfig struct {
+	MaxEntries int
+	MaxBytes   int
+	Timeout    time.Duration
+}
+
+// InProcess is a total order that appends broadcast entries to a local
+// store. When an entry can not be appended, the total order halts: the
+// failure is returned from Broadcast and from Deliver for the entries that
+// were not appended.
 type InProcess struct {
 	queue chan TXIDAndHMAC
 	store *Store
 	doneC chan struct{}
+	exitC chan struct{}
+	batch BatchConfig
+
+	stopOnce sync.Once
+
+	mutex   sync.Mutex
+	err     error
+	failedC chan struct{}
 }
 
 func NewInProcess(store *Store) *InProcess {
+	return NewBatchingInProcess(store, BatchConfig{})
+}
+
+// NewBatchingInProcess creates an in-process total order that appends
+// broadcast entries to the store in blocks that are cut according to the
+// batch configuration. When MaxEntries is less than two, every entry is
+// appended on its own.
+func NewBatchingInProcess(store *Store, batch BatchConfig) *InProcess {
+	if batch.Timeout <= 0 {
+		batch.Timeout = DefaultBatchTimeout
+	}
+
 	ip := &InProcess{
-		doneC: make(chan struct{}),
-		queue: make(chan TXIDAndHMAC),
-		store: store,
+		doneC:   make(chan struct{}),
+		exitC:   make(chan struct{}),
+		failedC: make(chan struct{}),
+		queue:   make(chan TXIDAndHMAC),
+		store:   store,
+		batch:   batch,
 	}
 	go ip.run()
 	return ip
 }
 
+// Stop appends the entries that are waiting for their block to be cut and
+// terminates processing. Stop may be called more than once.
 func (ip *InProcess) Stop() {
-	close(ip.doneC)
+	ip.stopOnce.Do(func() { close(ip.doneC) })
+	<-ip.exitC
 }
 
 func (ip *InProcess) run() {
+	defer close(ip.exitC)
+
+	if ip.batch.MaxEntries > 1 {
+		ip.runBatches()
+		return
+	}
+
 	for {
 		select {
 		case t := <-ip.queue:
-			ip.store.Append(t)
+			if err := ip.store.Append(t); err != nil {
+				ip.fail(errors.WithMessage(err, "failed to append entry"))
+				return
+			}
 		case <-ip.doneC:
 			return
 		}
 	}
 }
 
+// runBatches collects broadcast entries into blocks and appends each block
+// to the store when it is cut.
+func (ip *InProcess) runBatches() {
+	var (
+		pending  []TXIDAndHMAC
+		size     int
+		timer    *time.Timer
+		timeoutC <-chan time.Time
+	)
+
+	cut := func() error {
+		var err error
+		if len(pending) != 0 {
+			_, err = ip.store.AppendBlock(pending)
+		}
+		pending, size = nil, 0
+		if timer != nil {
+			timer.Stop()
+		}
+		timeoutC = nil
+		if err != nil {
+			ip.fail(errors.WithMessage(err, "failed to append block"))
+		}
+		return err
+	}
+
+	for {
+		select {
+		case t := <-ip.queue:
+			entrySize := len(t.ID) + len(t.HMAC)
+			if ip.batch.MaxBytes > 0 && size+entrySize > ip.batch.MaxBytes {
+				if cut() != nil {
+					return
+				}
+			}
+			if len(pending) == 0 {
+				timer = time.NewTimer(ip.batch.Timeout)
+				timeoutC = timer.C
+			}
+			pending = append(pending, t)
+			size += entrySize
+			if len(pending) >= ip.batch.MaxEntries || (ip.batch.MaxBytes > 0 && size >= ip.batch.MaxBytes) {
+				if cut() != nil {
+					return
+				}
+			}
+		case <-timeoutC:
+			if cut() != nil {
+				return
+			}
+		case <-ip.doneC:
+			cut()
+			return
+		}
+	}
+}
+
+// fail records the error that halted the total order.
+func (ip *InProcess) fail(err error) {
+	ip.mutex.Lock()
+	defer ip.mutex.Unlock()
+
+	ip.err = err
+	close(ip.failedC)
+}
+
+// failure returns the error that halted the total order or nil.
+func (ip *InProcess) failure() error {
+	ip.mutex.Lock()
+	defer ip.mutex.Unlock()
+	return ip.err
+}
+
 func (ip *InProcess) Broadcast(ctx context.Context, t TXIDAndHMAC) error {
 	select {
 	case ip.queue <- t:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-ip.failedC:
+		return ip.failure()
 	case <-ip.doneC:
 		return errors.Errorf("told to exit") // TODO, turn into a sentinal error
 	}
 }
 
+// Deliver returns the entry at seq, waiting for it to be ordered. When the
+// total order halts before the entry is appended, the failure is returned.
 func (ip *InProcess) Deliver(ctx context.Context, seq uint64) (TXIDAndHMAC, error) {
-	return ip.store.Get(ctx, seq)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ip.failedC:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	tah, err := ip.store.Get(ctx, seq)
+	if err != nil {
+		if ferr := ip.failure(); ferr != nil {
+			return TXIDAndHMAC{}, ferr
+		}
+	}
+	return tah, err
+}
+
+func (ip *InProcess) Checkpoint(ctx context.Context, seq uint64) (Checkpoint, error) {
+	return ip.store.Checkpoint(seq)
+}
+
+// LatestCheckpoint returns the checkpoint for the last ordered entry.
+func (ip *InProcess) LatestCheckpoint(ctx context.Context) (Checkpoint, error) {
+	return ip.store.LatestCheckpoint()
+}
+
+// Verify replays the ordered entries to confirm the hash chain.
+func (ip *InProcess) Verify() (*Checkpoint, error) {
+	return ip.store.Verify()
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -18,17 +19,18 @@ import (
 func TestInProcess(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
-	path, cleanup := tested.TempDir(t, "", "totalorder-inprocess")
-	defer cleanup()
+	db := store.NewMemoryKV()
+	defer tested.Close(t, db)
 
-	db, err := store.NewLevelDB(path)
+	orderStore, err := NewStore(crypto.SHA256, db)
 	gt.Expect(err).NotTo(HaveOccurred())
-	defer tested.Close(t, db)
 
 	ip := &InProcess{
-		store: NewStore(crypto.SHA256, db),
-		doneC: make(chan struct{}),
-		queue: make(chan TXIDAndHMAC),
+		store:   orderStore,
+		doneC:   make(chan struct{}),
+		exitC:   make(chan struct{}),
+		failedC: make(chan struct{}),
+		queue:   make(chan TXIDAndHMAC),
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -42,12 +44,7 @@ func TestInProcess(t *testing.T) {
 	_, err = ip.Deliver(ctx, 1)
 	gt.Expect(err).To(MatchError(context.Canceled))
 
-	exitC := make(chan struct{})
-
-	go func() {
-		ip.run()
-		close(exitC)
-	}()
+	go ip.run()
 
 	err = ip.Broadcast(context.Background(), TXIDAndHMAC{
 		ID:   transaction.ID(sHash("tx1")),
@@ -65,13 +62,75 @@ func TestInProcess(t *testing.T) {
 	))
 
 	ip.Stop()
-
-	gt.Eventually(exitC).Should(BeClosed())
+	gt.Expect(ip.exitC).To(BeClosed())
+	ip.Stop() // stopping again has no effect
 
 	err = ip.Broadcast(context.Background(), TXIDAndHMAC{
 		ID:   transaction.ID(sHash("tx1")),
 		HMAC: sHash("tx1" + "secret"),
 	})
 	gt.Expect(err).To(MatchError("told to exit"))
+}
+
+func TestInProcessAppendFails(t *testing.T) {
+	for name, batch := range map[string]BatchConfig{
+		"entries": {},
+		"blocks":  {MaxEntries: 2, Timeout: time.Millisecond},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			db := store.NewMemoryKV()
+			orderStore, err := NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			ip := NewBatchingInProcess(orderStore, batch)
+			defer ip.Stop()
+
+			// Writes fail once the database is closed.
+			gt.Expect(db.Close()).To(Succeed())
+			err = ip.Broadcast(context.Background(), testEntry(0))
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			// The total order halts and the failure is reported to
+			// consumers and later submitters.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = ip.Deliver(ctx, 0)
+			gt.Expect(err).To(MatchError(ContainSubstring("failed to append")))
+			gt.Expect(err).To(MatchError(ContainSubstring("memory: closed")))
+
+			err = ip.Broadcast(context.Background(), testEntry(1))
+			gt.Expect(err).To(MatchError(ContainSubstring("failed to append")))
+		})
+	}
+}
+
+func TestInProcessStopFlushesBatch(t *testing.T) {
+	gt := NewGomegaWithT(t)
 
+	db := store.NewMemoryKV()
+	defer tested.Close(t, db)
+
+	orderStore, err := NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ip := NewBatchingInProcess(orderStore, BatchConfig{MaxEntries: 10, Timeout: time.Minute})
+	for i := 0; i < 3; i++ {
+		err := ip.Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	ip.Stop()
+
+	// The entries that were waiting for the block to fill were appended.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		tah, err := ip.Deliver(ctx, uint64(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(tah).To(Equal(testEntry(i)))
+	}
+	block, err := orderStore.Block(0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(block.Count).To(Equal(uint64(3)))
 }
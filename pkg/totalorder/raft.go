@@ -0,0 +1,951 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/sykesm/batik/pkg/store"
+)
+
+var (
+	// Raft prefix, disjoint from the Store prefixes.
+	keyRaft = [...]byte{0x3}
+
+	// Raft keys
+	keyRaftHardState = append(keyRaft[:], 0x1)
+	keyRaftEntries   = append(keyRaft[:], 0x2)
+	keyRaftSnapshot  = append(keyRaft[:], 0x3)
+	keyRaftRuns      = append(keyRaft[:], 0x4)
+)
+
+const (
+	// maxAppendEntries bounds the number of entries sent in a single append
+	// message while a follower is catching up.
+	maxAppendEntries = 64
+	// compactEntries is the number of applied entries that are held in the
+	// raft log before they are compacted.
+	compactEntries = 1024
+)
+
+// RaftMessageType identifies the purpose of a RaftMessage.
+type RaftMessageType int
+
+const (
+	// MsgVote requests a vote from a peer for a candidate.
+	MsgVote RaftMessageType = iota
+	// MsgVoteResp grants or rejects a vote request.
+	MsgVoteResp
+	// MsgApp replicates log entries and the commit index from the leader.
+	MsgApp
+	// MsgAppResp acknowledges or rejects a MsgApp.
+	MsgAppResp
+	// MsgProp forwards a proposal from a follower to the leader.
+	MsgProp
+)
+
+// RaftEntry is an entry in the replicated raft log. Entries without an ID are
+// appended by new leaders to commit entries from prior terms and are not
+// delivered.
+type RaftEntry struct {
+	Term uint64
+	Data TXIDAndHMAC
+}
+
+// RaftMessage is exchanged between raft nodes over a RaftTransport.
+type RaftMessage struct {
+	Type RaftMessageType
+	From uint64
+	To   uint64
+	Term uint64
+
+	// Index is the index preceding Entries for MsgApp, the last log index of
+	// the candidate for MsgVote, and the last matching index (or the hint
+	// for where to resume on rejection) for MsgAppResp.
+	Index uint64
+	// LogTerm is the term of the entry at Index.
+	LogTerm uint64
+	Entries []RaftEntry
+	Commit  uint64
+	Reject  bool
+}
+
+// A RaftTransport sends messages to other members of the raft cluster.
+// Delivery is best effort; messages may be dropped or reordered.
+type RaftTransport interface {
+	Send(RaftMessage)
+}
+
+// RaftConfig is the configuration for a Raft node.
+type RaftConfig struct {
+	// ID uniquely identifies this node within the cluster. It must not be 0.
+	ID uint64
+	// Peers contains the IDs of all cluster members, including this node.
+	Peers []uint64
+	// TickInterval is the duration of a single logical clock tick.
+	TickInterval time.Duration
+	// ElectionTicks is the minimum number of ticks a follower waits without
+	// hearing from a leader before campaigning.
+	ElectionTicks int
+	// HeartbeatTicks is the number of ticks between leader heartbeats.
+	HeartbeatTicks int
+}
+
+// A raftRun describes consecutive compacted entries from the same term. The
+// first entry of a run may be an empty leader entry; the remaining entries
+// are in the store.
+type raftRun struct {
+	index uint64 // index is the raft index of the first entry
+	term  uint64
+	seq   uint64 // seq is the store sequence number of the first stored entry
+	empty bool   // empty is set when the first entry is an empty leader entry
+}
+
+type raftState int
+
+const (
+	follower raftState = iota
+	candidate
+	leader
+)
+
+func (s raftState) String() string {
+	switch s {
+	case follower:
+		return "follower"
+	case candidate:
+		return "candidate"
+	default:
+		return "leader"
+	}
+}
+
+type proposal struct {
+	data    TXIDAndHMAC
+	resultC chan error
+}
+
+// Raft is a total order that replicates entries to a cluster of nodes using
+// the raft consensus algorithm. Committed entries are appended to the Store
+// and delivered from it.
+type Raft struct {
+	id             uint64
+	peers          []uint64
+	tickInterval   time.Duration
+	electionTicks  int
+	heartbeatTicks int
+	compactEntries uint64
+
+	logger    *zap.Logger
+	store     *Store
+	kv        store.KV
+	transport RaftTransport
+	rand      *rand.Rand
+
+	// State owned by the run loop.
+	state            raftState
+	term             uint64
+	votedFor         uint64
+	leader           uint64
+	log              []RaftEntry // log holds the entries that follow snapIndex
+	snapIndex        uint64      // snapIndex is the index of the last compacted entry
+	snapSeq          uint64      // snapSeq is the number of stored entries through snapIndex
+	runs             []raftRun   // runs describe the compacted entries
+	commitIndex      uint64
+	appliedIndex     uint64
+	votes            map[uint64]bool
+	nextIndex        map[uint64]uint64
+	matchIndex       map[uint64]uint64
+	electionElapsed  int
+	electionTimeout  int
+	heartbeatElapsed int
+	pending          []TXIDAndHMAC
+
+	// knownLeader mirrors leader for access outside of the run loop.
+	knownLeader uint64
+
+	ticker    *time.Ticker
+	recvC     chan RaftMessage
+	propC     chan proposal
+	startOnce sync.Once
+	stopOnce  sync.Once
+	doneC     chan struct{}
+	exitC     chan struct{}
+}
+
+// NewRaft creates a raft node. Processing begins when the node is started.
+// The raft log and the vote state are persisted in kv, which should be the
+// KV backing the store. Entries that were committed before a restart are not
+// delivered again. Applied entries are compacted from the raft log and are
+// read from the store when a follower needs them.
+func NewRaft(config RaftConfig, logger *zap.Logger, orderStore *Store, kv store.KV, transport RaftTransport) (*Raft, error) {
+	if config.ID == 0 {
+		return nil, errors.New("raft node id must not be 0")
+	}
+	if !containsID(config.Peers, config.ID) {
+		return nil, errors.Errorf("raft node %d is not a member of peers %v", config.ID, config.Peers)
+	}
+	if config.TickInterval <= 0 || config.ElectionTicks <= 0 || config.HeartbeatTicks <= 0 {
+		return nil, errors.New("raft tick interval, election ticks, and heartbeat ticks must be positive")
+	}
+	if config.HeartbeatTicks >= config.ElectionTicks {
+		return nil, errors.Errorf("raft heartbeat ticks (%d) must be less than election ticks (%d)", config.HeartbeatTicks, config.ElectionTicks)
+	}
+
+	r := &Raft{
+		id:             config.ID,
+		peers:          append([]uint64{}, config.Peers...),
+		tickInterval:   config.TickInterval,
+		electionTicks:  config.ElectionTicks,
+		heartbeatTicks: config.HeartbeatTicks,
+		compactEntries: compactEntries,
+		logger:         logger,
+		store:          orderStore,
+		kv:             kv,
+		transport:      transport,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano() + int64(config.ID))),
+		recvC:          make(chan RaftMessage, 256),
+		propC:          make(chan proposal),
+		doneC:          make(chan struct{}),
+		exitC:          make(chan struct{}),
+	}
+
+	if err := r.restore(); err != nil {
+		return nil, err
+	}
+	r.becomeFollower(r.term, 0)
+
+	return r, nil
+}
+
+// Start begins participating in the cluster. Start has no effect after the
+// node has been started or stopped.
+func (r *Raft) Start() {
+	r.startOnce.Do(func() {
+		r.ticker = time.NewTicker(r.tickInterval)
+		go r.run()
+	})
+}
+
+// Stop terminates the raft node and waits for processing to exit. Stop may
+// be called more than once.
+func (r *Raft) Stop() {
+	r.startOnce.Do(func() { close(r.exitC) })
+	r.stopOnce.Do(func() { close(r.doneC) })
+	<-r.exitC
+}
+
+// Leader returns the ID of the node this node believes is the leader or 0
+// when the leader is not known.
+func (r *Raft) Leader() uint64 {
+	return atomic.LoadUint64(&r.knownLeader)
+}
+
+// Step delivers a message from another member of the cluster. Messages are
+// dropped if they are addressed to another node or if the node is not
+// keeping up.
+func (r *Raft) Step(msg RaftMessage) {
+	if msg.To != r.id {
+		r.logger.Debug("dropping message addressed to another node", zap.Uint64("from", msg.From), zap.Uint64("to", msg.To))
+		return
+	}
+	select {
+	case r.recvC <- msg:
+	default:
+	}
+}
+
+// Broadcast submits an entry for ordering. It returns once the entry has
+// been accepted by this node for replication; entries that are accepted
+// while a leader change is in progress may be lost.
+func (r *Raft) Broadcast(ctx context.Context, t TXIDAndHMAC) error {
+	p := proposal{data: t, resultC: make(chan error, 1)}
+	select {
+	case r.propC <- p:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.doneC:
+		return errors.Errorf("told to exit")
+	}
+
+	select {
+	case err := <-p.resultC:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliver blocks until the entry at seq has been committed and returns it.
+func (r *Raft) Deliver(ctx context.Context, seq uint64) (TXIDAndHMAC, error) {
+	return r.store.Get(ctx, seq)
+}
+
+// Checkpoint returns the checkpoint for the committed entry at seq.
+func (r *Raft) Checkpoint(ctx context.Context, seq uint64) (Checkpoint, error) {
+	return r.store.Checkpoint(seq)
+}
+
+// LatestCheckpoint returns the checkpoint for the last committed entry.
+func (r *Raft) LatestCheckpoint(ctx context.Context) (Checkpoint, error) {
+	return r.store.LatestCheckpoint()
+}
+
+// Verify replays the committed entries to confirm the hash chain.
+func (r *Raft) Verify() (*Checkpoint, error) {
+	return r.store.Verify()
+}
+
+func (r *Raft) run() {
+	defer close(r.exitC)
+	defer r.ticker.Stop()
+
+	for {
+		select {
+		case <-r.ticker.C:
+			r.tick()
+		case msg := <-r.recvC:
+			r.step(msg)
+		case p := <-r.propC:
+			p.resultC <- r.propose(p.data)
+		case <-r.doneC:
+			return
+		}
+	}
+}
+
+func (r *Raft) tick() {
+	if r.state == leader {
+		r.heartbeatElapsed++
+		if r.heartbeatElapsed >= r.heartbeatTicks {
+			r.heartbeatElapsed = 0
+			r.broadcastAppend()
+		}
+		return
+	}
+
+	r.electionElapsed++
+	if r.electionElapsed >= r.electionTimeout {
+		r.campaign()
+	}
+}
+
+func (r *Raft) propose(t TXIDAndHMAC) error {
+	if len(t.ID) != 32 || len(t.HMAC) != 32 {
+		return errors.Errorf("invalid entry: id and hmac must be 32 bytes")
+	}
+
+	switch {
+	case r.state == leader:
+		if err := r.appendEntries(RaftEntry{Term: r.term, Data: t}); err != nil {
+			return err
+		}
+		r.matchIndex[r.id] = r.lastIndex()
+		r.maybeCommit()
+		r.broadcastAppend()
+	case r.leader != 0:
+		r.send(RaftMessage{Type: MsgProp, To: r.leader, Entries: []RaftEntry{{Data: t}}})
+	default:
+		r.pending = append(r.pending, t)
+	}
+	return nil
+}
+
+func (r *Raft) step(msg RaftMessage) {
+	if msg.Type == MsgProp {
+		for _, e := range msg.Entries {
+			if err := r.propose(e.Data); err != nil {
+				r.logger.Warn("dropping forwarded proposal", zap.Uint64("from", msg.From), zap.Error(err))
+			}
+		}
+		return
+	}
+
+	switch {
+	case msg.Term > r.term:
+		leader := uint64(0)
+		if msg.Type == MsgApp {
+			leader = msg.From
+		}
+		r.becomeFollower(msg.Term, leader)
+	case msg.Term < r.term:
+		// Let a stale leader or candidate learn about the new term.
+		switch msg.Type {
+		case MsgApp:
+			r.send(RaftMessage{Type: MsgAppResp, To: msg.From, Reject: true, Index: r.lastIndex()})
+		case MsgVote:
+			r.send(RaftMessage{Type: MsgVoteResp, To: msg.From, Reject: true})
+		}
+		return
+	}
+
+	switch msg.Type {
+	case MsgVote:
+		r.handleVote(msg)
+	case MsgVoteResp:
+		r.handleVoteResp(msg)
+	case MsgApp:
+		r.handleAppend(msg)
+	case MsgAppResp:
+		r.handleAppendResp(msg)
+	}
+}
+
+func (r *Raft) handleVote(msg RaftMessage) {
+	lastIndex, lastTerm := r.lastIndex(), r.lastTerm()
+	upToDate := msg.LogTerm > lastTerm || (msg.LogTerm == lastTerm && msg.Index >= lastIndex)
+	canVote := r.votedFor == 0 || r.votedFor == msg.From
+
+	if !canVote || !upToDate || r.state == leader {
+		r.send(RaftMessage{Type: MsgVoteResp, To: msg.From, Reject: true})
+		return
+	}
+
+	r.votedFor = msg.From
+	if err := r.persistHardState(); err != nil {
+		r.logger.Error("failed to persist vote", zap.Error(err))
+		return
+	}
+	r.electionElapsed = 0
+	r.send(RaftMessage{Type: MsgVoteResp, To: msg.From})
+}
+
+func (r *Raft) handleVoteResp(msg RaftMessage) {
+	if r.state != candidate {
+		return
+	}
+
+	r.votes[msg.From] = !msg.Reject
+	granted, rejected := 0, 0
+	for _, v := range r.votes {
+		if v {
+			granted++
+		} else {
+			rejected++
+		}
+	}
+
+	switch {
+	case granted >= r.quorum():
+		r.becomeLeader()
+	case rejected >= r.quorum():
+		r.becomeFollower(r.term, 0)
+	}
+}
+
+func (r *Raft) handleAppend(msg RaftMessage) {
+	if r.state != follower || r.leader != msg.From {
+		r.becomeFollower(r.term, msg.From)
+	}
+	r.electionElapsed = 0
+
+	if msg.Index > r.lastIndex() || r.termAt(msg.Index) != msg.LogTerm {
+		hint := msg.Index - 1
+		if hint > r.lastIndex() {
+			hint = r.lastIndex()
+		}
+		r.send(RaftMessage{Type: MsgAppResp, To: msg.From, Reject: true, Index: hint})
+		return
+	}
+
+	var entries []RaftEntry
+	for i, e := range msg.Entries {
+		index := msg.Index + uint64(i) + 1
+		if index > r.lastIndex() {
+			entries = msg.Entries[i:]
+			break
+		}
+		if r.termAt(index) != e.Term {
+			if index <= r.commitIndex {
+				// A leader never replaces committed entries; the message is
+				// invalid and must not alter the log.
+				r.logger.Warn("rejecting append that conflicts with committed entry", zap.Uint64("from", msg.From), zap.Uint64("index", index))
+				r.send(RaftMessage{Type: MsgAppResp, To: msg.From, Reject: true, Index: r.commitIndex})
+				return
+			}
+			if err := r.truncate(index - 1); err != nil {
+				r.logger.Error("failed to truncate log", zap.Error(err))
+				return
+			}
+			entries = msg.Entries[i:]
+			break
+		}
+	}
+	if err := r.appendEntries(entries...); err != nil {
+		r.logger.Error("failed to append entries", zap.Error(err))
+		return
+	}
+
+	matched := msg.Index + uint64(len(msg.Entries))
+	commit := msg.Commit
+	if commit > matched {
+		commit = matched
+	}
+	if commit > r.commitIndex {
+		r.commitIndex = commit
+		r.apply()
+	}
+
+	r.send(RaftMessage{Type: MsgAppResp, To: msg.From, Index: matched})
+}
+
+func (r *Raft) handleAppendResp(msg RaftMessage) {
+	if r.state != leader {
+		return
+	}
+
+	if msg.Reject {
+		next := r.nextIndex[msg.From] - 1
+		if msg.Index+1 < next {
+			next = msg.Index + 1
+		}
+		if next < 1 {
+			next = 1
+		}
+		r.nextIndex[msg.From] = next
+		r.sendAppend(msg.From)
+		return
+	}
+
+	if msg.Index > r.lastIndex() {
+		r.logger.Warn("dropping append response beyond the end of the log", zap.Uint64("from", msg.From), zap.Uint64("index", msg.Index))
+		return
+	}
+	if msg.Index > r.matchIndex[msg.From] {
+		r.matchIndex[msg.From] = msg.Index
+	}
+	if r.nextIndex[msg.From] <= msg.Index {
+		r.nextIndex[msg.From] = msg.Index + 1
+	}
+	r.maybeCommit()
+
+	// Continue catching up a follower that is behind.
+	if r.nextIndex[msg.From] <= r.lastIndex() {
+		r.sendAppend(msg.From)
+	}
+}
+
+func (r *Raft) campaign() {
+	r.state = candidate
+	r.term++
+	r.votedFor = r.id
+	r.setLeader(0)
+	r.votes = map[uint64]bool{r.id: true}
+	r.resetElectionTimeout()
+	if err := r.persistHardState(); err != nil {
+		r.logger.Error("failed to persist campaign", zap.Error(err))
+		return
+	}
+
+	r.logger.Debug("starting election", zap.Uint64("term", r.term))
+	if r.quorum() == 1 {
+		r.becomeLeader()
+		return
+	}
+
+	for _, p := range r.peers {
+		if p == r.id {
+			continue
+		}
+		r.send(RaftMessage{Type: MsgVote, To: p, Index: r.lastIndex(), LogTerm: r.lastTerm()})
+	}
+}
+
+func (r *Raft) becomeFollower(term, leader uint64) {
+	if term != r.term {
+		r.term = term
+		r.votedFor = 0
+		if err := r.persistHardState(); err != nil {
+			r.logger.Error("failed to persist term", zap.Error(err))
+		}
+	}
+	r.state = follower
+	r.setLeader(leader)
+	r.resetElectionTimeout()
+}
+
+func (r *Raft) becomeLeader() {
+	r.state = leader
+	r.heartbeatElapsed = 0
+	r.nextIndex = map[uint64]uint64{}
+	r.matchIndex = map[uint64]uint64{}
+	for _, p := range r.peers {
+		r.nextIndex[p] = r.lastIndex() + 1
+		r.matchIndex[p] = 0
+	}
+	r.logger.Info("became leader", zap.Uint64("term", r.term))
+
+	// An empty entry from the new term allows entries from prior terms to be
+	// committed without waiting for a new proposal.
+	if err := r.appendEntries(RaftEntry{Term: r.term}); err != nil {
+		r.logger.Error("failed to append leader entry", zap.Error(err))
+	}
+	r.matchIndex[r.id] = r.lastIndex()
+	r.setLeader(r.id)
+	r.maybeCommit()
+	r.broadcastAppend()
+}
+
+func (r *Raft) setLeader(leader uint64) {
+	if leader != r.leader && leader != 0 {
+		r.logger.Debug("leader changed", zap.Uint64("term", r.term), zap.Uint64("leader", leader))
+	}
+	r.leader = leader
+	atomic.StoreUint64(&r.knownLeader, leader)
+	if leader == 0 || len(r.pending) == 0 {
+		return
+	}
+
+	pending := r.pending
+	r.pending = nil
+	for _, t := range pending {
+		if err := r.propose(t); err != nil {
+			r.logger.Warn("dropping pending proposal", zap.Error(err))
+		}
+	}
+}
+
+func (r *Raft) resetElectionTimeout() {
+	r.electionElapsed = 0
+	r.electionTimeout = r.electionTicks + r.rand.Intn(r.electionTicks)
+}
+
+func (r *Raft) broadcastAppend() {
+	for _, p := range r.peers {
+		if p != r.id {
+			r.sendAppend(p)
+		}
+	}
+}
+
+func (r *Raft) sendAppend(to uint64) {
+	prev := r.nextIndex[to] - 1
+	end := r.lastIndex()
+	if end-prev > maxAppendEntries {
+		end = prev + maxAppendEntries
+	}
+
+	entries := make([]RaftEntry, 0, end-prev)
+	for index := prev + 1; index <= end; index++ {
+		e, err := r.entryAt(index)
+		if err != nil {
+			r.logger.Error("failed to load entries for append", zap.Uint64("to", to), zap.Error(err))
+			return
+		}
+		entries = append(entries, e)
+	}
+
+	r.send(RaftMessage{
+		Type:    MsgApp,
+		To:      to,
+		Index:   prev,
+		LogTerm: r.termAt(prev),
+		Entries: entries,
+		Commit:  r.commitIndex,
+	})
+}
+
+func (r *Raft) send(msg RaftMessage) {
+	msg.From = r.id
+	if msg.Type != MsgProp {
+		msg.Term = r.term
+	}
+	r.transport.Send(msg)
+}
+
+// maybeCommit advances the commit index to the highest index replicated to a
+// quorum when that entry is from the current term.
+func (r *Raft) maybeCommit() {
+	matches := make([]uint64, 0, len(r.peers))
+	for _, p := range r.peers {
+		matches = append(matches, r.matchIndex[p])
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i] > matches[j] })
+
+	index := matches[r.quorum()-1]
+	if index > r.commitIndex && r.termAt(index) == r.term {
+		r.commitIndex = index
+		r.apply()
+	}
+}
+
+// apply appends committed entries to the store and compacts the log once
+// enough entries have been applied.
+func (r *Raft) apply() {
+	for r.appliedIndex < r.commitIndex {
+		e := r.log[r.appliedIndex-r.snapIndex]
+		if len(e.Data.ID) != 0 {
+			if err := r.store.Append(e.Data); err != nil {
+				r.logger.Error("failed to append committed entry", zap.Uint64("index", r.appliedIndex+1), zap.Error(err))
+				return
+			}
+		}
+		r.appliedIndex++
+	}
+
+	if r.appliedIndex-r.snapIndex >= r.compactEntries {
+		if err := r.compact(r.appliedIndex); err != nil {
+			r.logger.Error("failed to compact raft log", zap.Error(err))
+		}
+	}
+}
+
+// compact removes the applied entries through index from the raft log. The
+// terms of the removed entries are retained as runs and their data is read
+// from the store.
+func (r *Raft) compact(index uint64) error {
+	runs, seq := r.runs, r.snapSeq
+	batch := r.kv.NewWriteBatch()
+	for i := r.snapIndex + 1; i <= index; i++ {
+		e := r.log[i-r.snapIndex-1]
+		empty := len(e.Data.ID) == 0
+		if len(runs) == 0 || runs[len(runs)-1].term != e.Term || empty {
+			run := raftRun{index: i, term: e.Term, seq: seq, empty: empty}
+			batch.Put(raftRunKey(uint64(len(runs))), serializeRaftRun(run))
+			runs = append(runs, run)
+		}
+		if !empty {
+			seq++
+		}
+		batch.Delete(raftEntryKey(i))
+	}
+	batch.Put(keyRaftSnapshot, append(uint64ToBytes(index), uint64ToBytes(seq)...))
+	if err := batch.Commit(); err != nil {
+		return errors.WithMessage(err, "could not persist raft log compaction")
+	}
+
+	r.log = append([]RaftEntry(nil), r.log[index-r.snapIndex:]...)
+	r.runs, r.snapIndex, r.snapSeq = runs, index, seq
+	return nil
+}
+
+func (r *Raft) quorum() int {
+	return len(r.peers)/2 + 1
+}
+
+func (r *Raft) lastIndex() uint64 {
+	return r.snapIndex + uint64(len(r.log))
+}
+
+func (r *Raft) lastTerm() uint64 {
+	return r.termAt(r.lastIndex())
+}
+
+func (r *Raft) termAt(index uint64) uint64 {
+	switch {
+	case index == 0 || index > r.lastIndex():
+		return 0
+	case index > r.snapIndex:
+		return r.log[index-r.snapIndex-1].Term
+	default:
+		return r.runAt(index).term
+	}
+}
+
+// entryAt returns the entry at index, reading the data of compacted entries
+// from the store.
+func (r *Raft) entryAt(index uint64) (RaftEntry, error) {
+	if index > r.snapIndex {
+		return r.log[index-r.snapIndex-1], nil
+	}
+
+	run := r.runAt(index)
+	seq := run.seq + index - run.index
+	if run.empty {
+		if index == run.index {
+			return RaftEntry{Term: run.term}, nil
+		}
+		seq--
+	}
+	data, err := r.store.entry(seq)
+	if err != nil {
+		return RaftEntry{}, errors.WithMessagef(err, "could not read raft entry %d", index)
+	}
+	return RaftEntry{Term: run.term, Data: data}, nil
+}
+
+// runAt returns the run that contains the compacted entry at index.
+func (r *Raft) runAt(index uint64) raftRun {
+	i := sort.Search(len(r.runs), func(i int) bool { return r.runs[i].index > index })
+	return r.runs[i-1]
+}
+
+func (r *Raft) appendEntries(entries ...RaftEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	batch := r.kv.NewWriteBatch()
+	for i, e := range entries {
+		batch.Put(raftEntryKey(r.lastIndex()+uint64(i)+1), serializeRaftEntry(e))
+	}
+	if err := batch.Commit(); err != nil {
+		return errors.WithMessage(err, "could not persist raft entries")
+	}
+	r.log = append(r.log, entries...)
+	return nil
+}
+
+// truncate removes all entries after index from the log.
+func (r *Raft) truncate(index uint64) error {
+	batch := r.kv.NewWriteBatch()
+	for i := index + 1; i <= r.lastIndex(); i++ {
+		batch.Delete(raftEntryKey(i))
+	}
+	if err := batch.Commit(); err != nil {
+		return errors.WithMessage(err, "could not truncate raft entries")
+	}
+	r.log = r.log[:index-r.snapIndex]
+	return nil
+}
+
+func (r *Raft) persistHardState() error {
+	hs := append(uint64ToBytes(r.term), uint64ToBytes(r.votedFor)...)
+	return r.kv.Put(keyRaftHardState, hs)
+}
+
+// restore loads the persisted term, vote, and log and determines which
+// entries have already been appended to the store.
+func (r *Raft) restore() error {
+	hs, err := r.kv.Get(keyRaftHardState)
+	switch {
+	case store.IsNotFound(err):
+	case err != nil:
+		return errors.WithMessage(err, "could not read raft state")
+	case len(hs) != 16:
+		return errors.WithMessagef(ErrInconsistent, "raft state has invalid length %d", len(hs))
+	default:
+		r.term = bytesToUint64(hs[:8])
+		r.votedFor = bytesToUint64(hs[8:])
+	}
+
+	snap, err := r.kv.Get(keyRaftSnapshot)
+	switch {
+	case store.IsNotFound(err):
+	case err != nil:
+		return errors.WithMessage(err, "could not read raft snapshot")
+	case len(snap) != 16:
+		return errors.WithMessagef(ErrInconsistent, "raft snapshot has invalid length %d", len(snap))
+	default:
+		r.snapIndex = bytesToUint64(snap[:8])
+		r.snapSeq = bytesToUint64(snap[8:])
+	}
+
+	for n := uint64(0); ; n++ {
+		value, err := r.kv.Get(raftRunKey(n))
+		if store.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "could not read raft run %d", n)
+		}
+		run, err := raftRunFromBytes(value)
+		if err != nil {
+			return errors.WithMessagef(err, "raft run %d", n)
+		}
+		r.runs = append(r.runs, run)
+	}
+	if r.snapIndex != 0 && (len(r.runs) == 0 || r.runs[0].index != 1) {
+		return errors.WithMessagef(ErrInconsistent, "raft runs do not describe the %d compacted entries", r.snapIndex)
+	}
+
+	for index := r.snapIndex + 1; ; index++ {
+		value, err := r.kv.Get(raftEntryKey(index))
+		if store.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "could not read raft entry %d", index)
+		}
+		e, err := raftEntryFromBytes(value)
+		if err != nil {
+			return errors.WithMessagef(err, "raft entry %d", index)
+		}
+		r.log = append(r.log, e)
+	}
+
+	// Entries in the store were committed; locate the raft index of the last
+	// one so that it is not applied again.
+	stored := r.store.length()
+	if stored < r.snapSeq {
+		return errors.WithMessagef(ErrInconsistent, "store contains %d entries, fewer than the %d compacted from the raft log", stored, r.snapSeq)
+	}
+	r.appliedIndex = r.snapIndex
+	for delivered := r.snapSeq; delivered < stored; r.appliedIndex++ {
+		if r.appliedIndex == r.lastIndex() {
+			return errors.WithMessagef(ErrInconsistent, "raft log contains fewer entries than the %d in the store", stored)
+		}
+		if len(r.log[r.appliedIndex-r.snapIndex].Data.ID) != 0 {
+			delivered++
+		}
+	}
+	r.commitIndex = r.appliedIndex
+
+	return nil
+}
+
+func raftEntryKey(index uint64) []byte {
+	return append(append([]byte{}, keyRaftEntries...), uint64ToBytes(index)...)
+}
+
+func raftRunKey(n uint64) []byte {
+	return append(append([]byte{}, keyRaftRuns...), uint64ToBytes(n)...)
+}
+
+func serializeRaftRun(run raftRun) []byte {
+	b := append(append(uint64ToBytes(run.index), uint64ToBytes(run.term)...), uint64ToBytes(run.seq)...)
+	if run.empty {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func raftRunFromBytes(b []byte) (raftRun, error) {
+	if len(b) != 25 {
+		return raftRun{}, errors.WithMessagef(ErrInconsistent, "invalid length %d", len(b))
+	}
+	return raftRun{
+		index: bytesToUint64(b[:8]),
+		term:  bytesToUint64(b[8:16]),
+		seq:   bytesToUint64(b[16:24]),
+		empty: b[24] == 1,
+	}, nil
+}
+
+func serializeRaftEntry(e RaftEntry) []byte {
+	b := uint64ToBytes(e.Term)
+	if len(e.Data.ID) == 0 {
+		return b
+	}
+	return append(b, e.Data.serialize()...)
+}
+
+func raftEntryFromBytes(b []byte) (RaftEntry, error) {
+	switch len(b) {
+	case 8:
+		return RaftEntry{Term: binary.BigEndian.Uint64(b)}, nil
+	case 8 + 64:
+		return RaftEntry{Term: binary.BigEndian.Uint64(b), Data: txidAndHMACFromBytes(b[8:])}, nil
+	default:
+		return RaftEntry{}, errors.WithMessagef(ErrInconsistent, "invalid length %d", len(b))
+	}
+}
+
+func containsID(ids []uint64, id uint64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+)
+
+const (
+	// raftSendQueueSize bounds the number of messages waiting to be sent to a
+	// peer. Messages sent while the queue is full are dropped.
+	raftSendQueueSize = 256
+	// raftSendTimeout bounds the time spent delivering a single message.
+	raftSendTimeout = time.Second
+)
+
+// GRPCTransport is a RaftTransport that sends messages to the members of a
+// raft cluster through the RaftAPI of the nodes that host them. Messages to
+// each peer are sent in order by a dedicated goroutine so that a slow or
+// unreachable peer does not delay messages to the others.
+type GRPCTransport struct {
+	logger *zap.Logger
+	queues map[uint64]chan RaftMessage
+
+	doneC chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewGRPCTransport creates a transport that sends messages for the total
+// order with the provided name to peers through the connections to the nodes
+// that host them. The transport begins sending immediately and must be closed
+// to release its resources.
+func NewGRPCTransport(logger *zap.Logger, name string, peers map[uint64]grpc.ClientConnInterface) *GRPCTransport {
+	t := &GRPCTransport{
+		logger: logger,
+		queues: map[uint64]chan RaftMessage{},
+		doneC:  make(chan struct{}),
+	}
+	for id, cc := range peers {
+		queue := make(chan RaftMessage, raftSendQueueSize)
+		t.queues[id] = queue
+		t.wg.Add(1)
+		go t.sendLoop(orderv1.NewRaftAPIClient(cc), name, queue)
+	}
+	return t
+}
+
+// Send queues a message for delivery to its recipient. Messages to unknown
+// peers and messages that do not fit in the queue of the peer are dropped.
+func (t *GRPCTransport) Send(msg RaftMessage) {
+	queue, ok := t.queues[msg.To]
+	if !ok {
+		t.logger.Debug("dropping message to unknown peer", zap.Uint64("to", msg.To))
+		return
+	}
+	select {
+	case queue <- msg:
+	default:
+	}
+}
+
+// Close stops sending messages and waits for in flight messages to complete.
+func (t *GRPCTransport) Close() {
+	close(t.doneC)
+	t.wg.Wait()
+}
+
+func (t *GRPCTransport) sendLoop(client orderv1.RaftAPIClient, name string, queue <-chan RaftMessage) {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case msg := <-queue:
+			ctx, cancel := context.WithTimeout(context.Background(), raftSendTimeout)
+			_, err := client.Step(ctx, &orderv1.StepRequest{
+				TotalOrder: name,
+				Message:    raftMessageToProto(msg),
+			})
+			cancel()
+			if err != nil {
+				t.logger.Debug("failed to send raft message", zap.Uint64("to", msg.To), zap.Error(err))
+			}
+		case <-t.doneC:
+			return
+		}
+	}
+}
+
+var raftMessageTypes = map[RaftMessageType]orderv1.RaftMessageType{
+	MsgVote:     orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE,
+	MsgVoteResp: orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE_RESP,
+	MsgApp:      orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP,
+	MsgAppResp:  orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP_RESP,
+	MsgProp:     orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_PROP,
+}
+
+func raftMessageToProto(msg RaftMessage) *orderv1.RaftMessage {
+	entries := make([]*orderv1.RaftEntry, 0, len(msg.Entries))
+	for _, e := range msg.Entries {
+		entry := &orderv1.RaftEntry{Term: e.Term}
+		if len(e.Data.ID) != 0 {
+			entry.Entry = &orderv1.Entry{Txid: e.Data.ID, Hmac: e.Data.HMAC}
+		}
+		entries = append(entries, entry)
+	}
+
+	return &orderv1.RaftMessage{
+		Type:    raftMessageTypes[msg.Type],
+		From:    msg.From,
+		To:      msg.To,
+		Term:    msg.Term,
+		Index:   msg.Index,
+		LogTerm: msg.LogTerm,
+		Entries: entries,
+		Commit:  msg.Commit,
+		Reject:  msg.Reject,
+	}
+}
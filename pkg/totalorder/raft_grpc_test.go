@@ -0,0 +1,149 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	orderv1 "github.com/sykesm/batik/pkg/pb/order/v1"
+	. "github.com/sykesm/batik/pkg/tested/matcher"
+)
+
+type recordingRaftAPI struct {
+	orderv1.UnimplementedRaftAPIServer
+
+	mutex    sync.Mutex
+	requests []*orderv1.StepRequest
+}
+
+func (r *recordingRaftAPI) Step(ctx context.Context, req *orderv1.StepRequest) (*orderv1.StepResponse, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, req)
+	return &orderv1.StepResponse{}, nil
+}
+
+func (r *recordingRaftAPI) Requests() []*orderv1.StepRequest {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*orderv1.StepRequest{}, r.requests...)
+}
+
+func serveRaftAPI(t *testing.T, server orderv1.RaftAPIServer) *grpc.ClientConn {
+	gt := NewGomegaWithT(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	s := grpc.NewServer()
+	orderv1.RegisterRaftAPIServer(s, server)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	gt.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCTransport(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	peer2, peer3 := &recordingRaftAPI{}, &recordingRaftAPI{}
+	transport := NewGRPCTransport(zap.NewNop(), "raft", map[uint64]grpc.ClientConnInterface{
+		2: serveRaftAPI(t, peer2),
+		3: serveRaftAPI(t, peer3),
+	})
+	defer transport.Close()
+
+	transport.Send(RaftMessage{Type: MsgVote, From: 1, To: 2, Term: 2, Index: 5, LogTerm: 1})
+	transport.Send(RaftMessage{
+		Type:    MsgApp,
+		From:    1,
+		To:      3,
+		Term:    2,
+		Index:   5,
+		LogTerm: 1,
+		Entries: []RaftEntry{{Term: 2}, {Term: 2, Data: testEntry(0)}},
+		Commit:  5,
+	})
+	transport.Send(RaftMessage{Type: MsgAppResp, From: 1, To: 3, Term: 2, Index: 4, Reject: true})
+	transport.Send(RaftMessage{Type: MsgVote, From: 1, To: 4, Term: 2})
+
+	gt.Eventually(peer2.Requests).Should(HaveLen(1))
+	gt.Expect(peer2.Requests()[0]).To(ProtoEqual(&orderv1.StepRequest{
+		TotalOrder: "raft",
+		Message: &orderv1.RaftMessage{
+			Type:    orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_VOTE,
+			From:    1,
+			To:      2,
+			Term:    2,
+			Index:   5,
+			LogTerm: 1,
+		},
+	}))
+
+	gt.Eventually(peer3.Requests).Should(HaveLen(2))
+	gt.Expect(peer3.Requests()[0]).To(ProtoEqual(&orderv1.StepRequest{
+		TotalOrder: "raft",
+		Message: &orderv1.RaftMessage{
+			Type:    orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP,
+			From:    1,
+			To:      3,
+			Term:    2,
+			Index:   5,
+			LogTerm: 1,
+			Entries: []*orderv1.RaftEntry{
+				{Term: 2},
+				{Term: 2, Entry: &orderv1.Entry{Txid: testEntry(0).ID, Hmac: testEntry(0).HMAC}},
+			},
+			Commit: 5,
+		},
+	}))
+	gt.Expect(peer3.Requests()[1]).To(ProtoEqual(&orderv1.StepRequest{
+		TotalOrder: "raft",
+		Message: &orderv1.RaftMessage{
+			Type:   orderv1.RaftMessageType_RAFT_MESSAGE_TYPE_APP_RESP,
+			From:   1,
+			To:     3,
+			Term:   2,
+			Index:  4,
+			Reject: true,
+		},
+	}))
+}
+
+func TestGRPCTransportUnreachablePeer(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	address := lis.Addr().String()
+	lis.Close()
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer conn.Close()
+
+	peer3 := &recordingRaftAPI{}
+	transport := NewGRPCTransport(zap.NewNop(), "raft", map[uint64]grpc.ClientConnInterface{
+		2: conn,
+		3: serveRaftAPI(t, peer3),
+	})
+	defer transport.Close()
+
+	// Messages to an unreachable peer do not delay messages to the others.
+	for i := 0; i < 2*raftSendQueueSize; i++ {
+		transport.Send(RaftMessage{Type: MsgApp, From: 1, To: 2})
+	}
+	transport.Send(RaftMessage{Type: MsgApp, From: 1, To: 3})
+	gt.Eventually(peer3.Requests).Should(HaveLen(1))
+}
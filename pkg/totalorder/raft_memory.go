@@ -0,0 +1,78 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import "sync"
+
+// MemoryNetwork connects raft nodes that run in a single process. It is
+// primarily intended for testing and supports isolating nodes to simulate
+// network partitions.
+type MemoryNetwork struct {
+	mutex    sync.Mutex
+	nodes    map[uint64]*Raft
+	isolated map[uint64]bool
+}
+
+// NewMemoryNetwork creates an empty MemoryNetwork.
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		nodes:    map[uint64]*Raft{},
+		isolated: map[uint64]bool{},
+	}
+}
+
+// Transport returns a RaftTransport that sends messages over the network.
+func (n *MemoryNetwork) Transport() RaftTransport {
+	return memoryTransport{network: n}
+}
+
+// Register attaches a raft node to the network so that it receives the
+// messages addressed to id. Registering a new node with the same id
+// replaces the previous node.
+func (n *MemoryNetwork) Register(id uint64, r *Raft) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.nodes[id] = r
+}
+
+// Unregister detaches a raft node from the network.
+func (n *MemoryNetwork) Unregister(id uint64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.nodes, id)
+}
+
+// Isolate drops all messages sent to or from the node with the provided id
+// until it is healed.
+func (n *MemoryNetwork) Isolate(id uint64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.isolated[id] = true
+}
+
+// Heal reconnects a node that was previously isolated.
+func (n *MemoryNetwork) Heal(id uint64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.isolated, id)
+}
+
+func (n *MemoryNetwork) send(msg RaftMessage) {
+	n.mutex.Lock()
+	node, ok := n.nodes[msg.To]
+	dropped := n.isolated[msg.From] || n.isolated[msg.To]
+	n.mutex.Unlock()
+
+	if ok && !dropped {
+		node.Step(msg)
+	}
+}
+
+type memoryTransport struct {
+	network *MemoryNetwork
+}
+
+func (m memoryTransport) Send(msg RaftMessage) {
+	m.network.send(msg)
+}
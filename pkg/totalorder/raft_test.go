@@ -0,0 +1,416 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/sykesm/batik/pkg/store"
+	"github.com/sykesm/batik/pkg/tested"
+)
+
+type raftCluster struct {
+	t              *testing.T
+	network        *MemoryNetwork
+	peers          []uint64
+	compactEntries uint64
+	dbs            map[uint64]*store.LevelDBKV
+	nodes          map[uint64]*Raft
+	stopped        map[uint64]bool
+}
+
+func newRaftCluster(t *testing.T, size int) *raftCluster {
+	return newCompactingRaftCluster(t, size, compactEntries)
+}
+
+func newCompactingRaftCluster(t *testing.T, size int, compactEntries uint64) *raftCluster {
+	c := &raftCluster{
+		t:              t,
+		network:        NewMemoryNetwork(),
+		compactEntries: compactEntries,
+		dbs:            map[uint64]*store.LevelDBKV{},
+		nodes:          map[uint64]*Raft{},
+		stopped:        map[uint64]bool{},
+	}
+	for id := uint64(1); id <= uint64(size); id++ {
+		c.peers = append(c.peers, id)
+	}
+	for _, id := range c.peers {
+		db, err := store.NewLevelDB("")
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		c.dbs[id] = db
+		c.start(id)
+	}
+	return c
+}
+
+func (c *raftCluster) start(id uint64) {
+	gt := NewGomegaWithT(c.t)
+
+	orderStore, err := NewStore(crypto.SHA256, c.dbs[id])
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	r, err := NewRaft(testRaftConfig(id, c.peers), zap.NewNop(), orderStore, c.dbs[id], c.network.Transport())
+	gt.Expect(err).NotTo(HaveOccurred())
+	r.compactEntries = c.compactEntries
+
+	c.nodes[id] = r
+	c.stopped[id] = false
+	c.network.Register(id, r)
+	r.Start()
+}
+
+func (c *raftCluster) stop(id uint64) {
+	c.network.Unregister(id)
+	c.nodes[id].Stop()
+	c.stopped[id] = true
+}
+
+func (c *raftCluster) close() {
+	for id := range c.nodes {
+		if !c.stopped[id] {
+			c.stop(id)
+		}
+		tested.Close(c.t, c.dbs[id])
+	}
+}
+
+// leader waits for the nodes, excluding ignored nodes, to agree on a leader.
+func (c *raftCluster) leader(ignored ...uint64) uint64 {
+	var leader uint64
+	NewGomegaWithT(c.t).Eventually(func() bool {
+		leader = 0
+		for id, r := range c.nodes {
+			if containsID(ignored, id) {
+				continue
+			}
+			l := r.Leader()
+			if l == 0 || containsID(ignored, l) || (leader != 0 && l != leader) {
+				return false
+			}
+			leader = l
+		}
+		return true
+	}, 5*time.Second).Should(BeTrue())
+	return leader
+}
+
+func (c *raftCluster) follower(leader uint64) uint64 {
+	for _, id := range c.peers {
+		if id != leader {
+			return id
+		}
+	}
+	panic("no follower")
+}
+
+func testRaftConfig(id uint64, peers []uint64) RaftConfig {
+	return RaftConfig{
+		ID:             id,
+		Peers:          peers,
+		TickInterval:   5 * time.Millisecond,
+		ElectionTicks:  10,
+		HeartbeatTicks: 1,
+	}
+}
+
+func testEntry(i int) TXIDAndHMAC {
+	return TXIDAndHMAC{
+		ID:   sHash(fmt.Sprintf("tx%d", i)),
+		HMAC: sHash(fmt.Sprintf("tx%d", i) + "secret"),
+	}
+}
+
+func deliverAll(gt *GomegaWithT, r *Raft, count int) []TXIDAndHMAC {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var delivered []TXIDAndHMAC
+	for seq := 0; seq < count; seq++ {
+		tah, err := r.Deliver(ctx, uint64(seq))
+		gt.Expect(err).NotTo(HaveOccurred())
+		delivered = append(delivered, tah)
+	}
+	return delivered
+}
+
+func TestRaftSingleNode(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 1)
+	defer c.close()
+
+	for i := 0; i < 3; i++ {
+		err := c.nodes[1].Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	gt.Expect(deliverAll(gt, c.nodes[1], 3)).To(Equal([]TXIDAndHMAC{testEntry(0), testEntry(1), testEntry(2)}))
+	gt.Expect(c.leader()).To(Equal(uint64(1)))
+
+	c.stop(1)
+	c.nodes[1].Stop() // stopping again has no effect
+}
+
+func TestRaftReplication(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	leader := c.leader()
+	follower := c.follower(leader)
+
+	// Proposals are accepted by the leader and forwarded by followers.
+	for i := 0; i < 10; i++ {
+		node := c.nodes[leader]
+		if i%2 == 1 {
+			node = c.nodes[follower]
+		}
+		err := node.Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	expected := deliverAll(gt, c.nodes[leader], 10)
+	gt.Expect(expected).To(ConsistOf(func() []interface{} {
+		var entries []interface{}
+		for i := 0; i < 10; i++ {
+			entries = append(entries, testEntry(i))
+		}
+		return entries
+	}()...))
+
+	for _, r := range c.nodes {
+		gt.Expect(deliverAll(gt, r, 10)).To(Equal(expected))
+	}
+}
+
+func TestRaftBroadcastBeforeLeader(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	// Proposals made before a leader is known are held until one is elected.
+	err := c.nodes[2].Broadcast(context.Background(), testEntry(0))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, r := range c.nodes {
+		gt.Expect(deliverAll(gt, r, 1)).To(Equal([]TXIDAndHMAC{testEntry(0)}))
+	}
+}
+
+func TestRaftLeaderElection(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	leader := c.leader()
+	err := c.nodes[leader].Broadcast(context.Background(), testEntry(0))
+	gt.Expect(err).NotTo(HaveOccurred())
+	for _, r := range c.nodes {
+		deliverAll(gt, r, 1)
+	}
+
+	// Isolating the leader forces the remaining nodes to elect a new one.
+	c.network.Isolate(leader)
+	newLeader := c.leader(leader)
+	gt.Expect(newLeader).NotTo(Equal(leader))
+
+	err = c.nodes[newLeader].Broadcast(context.Background(), testEntry(1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(deliverAll(gt, c.nodes[newLeader], 2)).To(Equal([]TXIDAndHMAC{testEntry(0), testEntry(1)}))
+
+	// The old leader steps down and catches up once it rejoins.
+	c.network.Heal(leader)
+	gt.Expect(c.leader()).To(Equal(newLeader))
+	gt.Expect(deliverAll(gt, c.nodes[leader], 2)).To(Equal([]TXIDAndHMAC{testEntry(0), testEntry(1)}))
+}
+
+func TestRaftRejectsInvalidMessages(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	leader := c.leader()
+	follower := c.follower(leader)
+	for i := 0; i < 3; i++ {
+		err := c.nodes[leader].Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	expected := deliverAll(gt, c.nodes[follower], 3)
+
+	// An append that replaces committed entries and an acknowledgement of
+	// entries the leader does not have are dropped without halting the node.
+	c.nodes[follower].Step(RaftMessage{
+		Type:    MsgApp,
+		From:    leader,
+		To:      follower,
+		Term:    100,
+		Entries: []RaftEntry{{Term: 99, Data: testEntry(99)}},
+	})
+	c.nodes[leader].Step(RaftMessage{Type: MsgAppResp, From: follower, To: leader, Term: 100, Index: 1000})
+
+	leader = c.leader()
+	err := c.nodes[leader].Broadcast(context.Background(), testEntry(3))
+	gt.Expect(err).NotTo(HaveOccurred())
+	expected = append(expected, testEntry(3))
+	for _, r := range c.nodes {
+		gt.Expect(deliverAll(gt, r, 4)).To(Equal(expected))
+	}
+}
+
+func TestRaftFollowerCatchUp(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	leader := c.leader()
+	follower := c.follower(leader)
+
+	c.network.Isolate(follower)
+	count := 3*maxAppendEntries + 1
+	for i := 0; i < count; i++ {
+		err := c.nodes[leader].Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	expected := deliverAll(gt, c.nodes[leader], count)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.nodes[follower].Deliver(ctx, 0)
+	gt.Expect(err).To(MatchError(context.DeadlineExceeded))
+
+	c.network.Heal(follower)
+	gt.Expect(deliverAll(gt, c.nodes[follower], count)).To(Equal(expected))
+}
+
+func TestRaftRestart(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newRaftCluster(t, 3)
+	defer c.close()
+
+	leader := c.leader()
+	for i := 0; i < 5; i++ {
+		err := c.nodes[leader].Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	expected := deliverAll(gt, c.nodes[leader], 5)
+
+	for _, r := range c.nodes {
+		deliverAll(gt, r, 5)
+	}
+
+	// Restart every node from its persisted state.
+	for _, id := range c.peers {
+		c.stop(id)
+	}
+	for _, id := range c.peers {
+		c.start(id)
+	}
+
+	leader = c.leader()
+	err := c.nodes[leader].Broadcast(context.Background(), testEntry(5))
+	gt.Expect(err).NotTo(HaveOccurred())
+	expected = append(expected, testEntry(5))
+
+	for _, r := range c.nodes {
+		gt.Expect(deliverAll(gt, r, 6)).To(Equal(expected))
+		gt.Expect(r.store.length()).To(Equal(uint64(6)))
+	}
+}
+
+func TestRaftLogCompaction(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	c := newCompactingRaftCluster(t, 3, 8)
+	defer c.close()
+
+	leader := c.leader()
+	follower := c.follower(leader)
+
+	c.network.Isolate(follower)
+	count := 3*maxAppendEntries + 1
+	for i := 0; i < count; i++ {
+		err := c.nodes[leader].Broadcast(context.Background(), testEntry(i))
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	expected := deliverAll(gt, c.nodes[leader], count)
+
+	// Applied entries are removed from the raft log of the leader.
+	gt.Eventually(func() error {
+		_, err := c.dbs[leader].Get(raftEntryKey(uint64(count - 8)))
+		return err
+	}).Should(MatchError(ContainSubstring("not found")))
+	_, err := c.dbs[leader].Get(raftEntryKey(1))
+	gt.Expect(store.IsNotFound(err)).To(BeTrue())
+
+	// The follower catches up from the entries in the store of the leader.
+	c.network.Heal(follower)
+	gt.Expect(deliverAll(gt, c.nodes[follower], count)).To(Equal(expected))
+
+	// Nodes restart from the compacted log.
+	for _, id := range c.peers {
+		c.stop(id)
+	}
+	for _, id := range c.peers {
+		c.start(id)
+	}
+
+	leader = c.leader()
+	err = c.nodes[leader].Broadcast(context.Background(), testEntry(count))
+	gt.Expect(err).NotTo(HaveOccurred())
+	expected = append(expected, testEntry(count))
+
+	for _, r := range c.nodes {
+		gt.Expect(deliverAll(gt, r, count+1)).To(Equal(expected))
+		gt.Expect(r.store.length()).To(Equal(uint64(count + 1)))
+	}
+}
+
+func TestNewRaftConfig(t *testing.T) {
+	tests := map[string]struct {
+		setup       func(*RaftConfig)
+		errContains string
+	}{
+		"zero id": {
+			setup:       func(c *RaftConfig) { c.ID = 0 },
+			errContains: "raft node id must not be 0",
+		},
+		"not a peer": {
+			setup:       func(c *RaftConfig) { c.Peers = []uint64{2, 3} },
+			errContains: "raft node 1 is not a member of peers [2 3]",
+		},
+		"no tick interval": {
+			setup:       func(c *RaftConfig) { c.TickInterval = 0 },
+			errContains: "must be positive",
+		},
+		"heartbeat exceeds election": {
+			setup:       func(c *RaftConfig) { c.HeartbeatTicks = 10 },
+			errContains: "raft heartbeat ticks (10) must be less than election ticks (10)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			db, err := store.NewLevelDB("")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer tested.Close(t, db)
+
+			orderStore, err := NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			config := testRaftConfig(1, []uint64{1, 2, 3})
+			tt.setup(&config)
+
+			_, err = NewRaft(config, zap.NewNop(), orderStore, db, NewMemoryNetwork().Transport())
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package totalorder
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"hash"
@@ -20,35 +21,135 @@ type Hasher interface {
 
 var (
 	// Global prefixes.
-	keyMetadata  = [...]byte{0x1}
-	keySequences = [...]byte{0x2}
+	keyMetadata     = [...]byte{0x1}
+	keySequences    = [...]byte{0x2}
+	keyAccumulators = [...]byte{0x4}
+	keyBlocks       = [...]byte{0x5}
 
 	// MD keys
 	keyMetadataLastCommitted = append(keyMetadata[:], 0x1)
 	keyMetadataAccumulator   = append(keyMetadata[:], 0x2)
+	keyMetadataLastBlock     = append(keyMetadata[:], 0x3)
 
 	// Statically defined keys
 	// TODO, probably make this a more concise byte string?
 	keyLastCommittedSeq = []byte("last-committed")
 )
 
+// ErrInconsistent indicates that the persisted log does not agree with the
+// persisted metadata that describes it.
+var ErrInconsistent = errors.New("total order store is inconsistent")
+
 type Store struct {
 	mutex        sync.Mutex
 	hasher       Hasher
 	kv           store.KV
 	nextSequence uint64
+	nextBlock    uint64
 	accumulator  []byte
 	waitCs       map[uint64]chan struct{}
 }
 
-func NewStore(hasher Hasher, kv store.KV) *Store {
-	// TODO, crash recovery
-
-	return &Store{
+// NewStore creates a Store backed by the provided KV. When the KV holds a
+// previously persisted log, the next sequence number and accumulator are
+// restored from the metadata and checked against the log. An error wrapping
+// ErrInconsistent is returned when they do not agree.
+func NewStore(hasher Hasher, kv store.KV) (*Store, error) {
+	s := &Store{
 		kv:     kv,
 		hasher: hasher,
 		waitCs: map[uint64]chan struct{}{},
 	}
+
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// recover restores the position and accumulator of the log from the metadata
+// persisted by Append.
+func (s *Store) recover() error {
+	lastCommitted, err := s.kv.Get(keyMetadataLastCommitted)
+	if store.IsNotFound(err) {
+		return s.checkEmpty()
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not read last committed sequence")
+	}
+	if len(lastCommitted) != 8 {
+		return errors.WithMessagef(ErrInconsistent, "last committed sequence has invalid length %d", len(lastCommitted))
+	}
+	lastSeq := bytesToUint64(lastCommitted)
+
+	accumulator, err := s.kv.Get(keyMetadataAccumulator)
+	if store.IsNotFound(err) {
+		return errors.WithMessagef(ErrInconsistent, "accumulator missing for last committed sequence %d", lastSeq)
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not read accumulator")
+	}
+	if len(accumulator) != s.hasher.New().Size() {
+		return errors.WithMessagef(ErrInconsistent, "accumulator has invalid length %d", len(accumulator))
+	}
+
+	value, err := s.kv.Get(txKey(lastSeq))
+	if store.IsNotFound(err) {
+		return errors.WithMessagef(ErrInconsistent, "entry missing for last committed sequence %d", lastSeq)
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "could not get key for seq %d", lastSeq)
+	}
+	if len(value) != 64 {
+		return errors.WithMessagef(ErrInconsistent, "entry for sequence %d has invalid length %d", lastSeq, len(value))
+	}
+
+	checkpoint, err := s.kv.Get(accumulatorKey(lastSeq))
+	if err == nil && !bytes.Equal(checkpoint, accumulator) {
+		return errors.WithMessagef(ErrInconsistent, "accumulator does not match checkpoint for sequence %d", lastSeq)
+	}
+	if err != nil && !store.IsNotFound(err) {
+		return errors.WithMessagef(err, "could not read checkpoint for seq %d", lastSeq)
+	}
+
+	_, err = s.kv.Get(txKey(lastSeq + 1))
+	if err == nil {
+		return errors.WithMessagef(ErrInconsistent, "entry found beyond last committed sequence %d", lastSeq)
+	}
+	if !store.IsNotFound(err) {
+		return errors.WithMessagef(err, "could not get key for seq %d", lastSeq+1)
+	}
+
+	if err := s.recoverBlocks(lastSeq); err != nil {
+		return err
+	}
+
+	s.nextSequence = lastSeq + 1
+	s.accumulator = accumulator
+	return nil
+}
+
+// checkEmpty ensures that a store without a last committed sequence does not
+// hold any log state.
+func (s *Store) checkEmpty() error {
+	_, err := s.kv.Get(keyMetadataAccumulator)
+	if err == nil {
+		return errors.WithMessage(ErrInconsistent, "accumulator found without a last committed sequence")
+	}
+	if !store.IsNotFound(err) {
+		return errors.WithMessage(err, "could not read accumulator")
+	}
+
+	_, err = s.kv.Get(txKey(0))
+	if err == nil {
+		return errors.WithMessage(ErrInconsistent, "entry found without a last committed sequence")
+	}
+	if !store.IsNotFound(err) {
+		return errors.WithMessage(err, "could not get key for seq 0")
+	}
+
+	return nil
 }
 
 func (s *Store) Append(t TXIDAndHMAC) error {
@@ -64,6 +165,7 @@ func (s *Store) Append(t TXIDAndHMAC) error {
 
 	batch := s.kv.NewWriteBatch()
 	batch.Put(txKey(s.nextSequence), tahBytes)
+	batch.Put(accumulatorKey(s.nextSequence), nextAccumulator)
 	batch.Put(keyMetadataLastCommitted, uint64ToBytes(s.nextSequence))
 	batch.Put(keyMetadataAccumulator, nextAccumulator)
 	if err := batch.Commit(); err != nil {
@@ -93,6 +195,25 @@ func (s *Store) Get(ctx context.Context, seq uint64) (TXIDAndHMAC, error) {
 	}
 }
 
+// entry returns the entry at seq without waiting for it to be appended.
+func (s *Store) entry(seq uint64) (TXIDAndHMAC, error) {
+	if seq >= s.length() {
+		return TXIDAndHMAC{}, errors.WithMessagef(ErrUncommitted, "no entry for seq %d", seq)
+	}
+	value, err := s.kv.Get(txKey(seq))
+	if err != nil {
+		return TXIDAndHMAC{}, errors.WithMessagef(err, "could not get key for seq %d", seq)
+	}
+	return txidAndHMACFromBytes(value), nil
+}
+
+// length returns the number of entries in the log.
+func (s *Store) length() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.nextSequence
+}
+
 func (s *Store) waitC(seq uint64) <-chan struct{} {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -127,3 +248,7 @@ func txKey(sequence uint64) []byte {
 	binary.BigEndian.PutUint64(byteValue[1:], sequence)
 	return byteValue
 }
+
+func accumulatorKey(sequence uint64) []byte {
+	return append(keyAccumulators[:], uint64ToBytes(sequence)...)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/sha256"
+	"errors"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -19,14 +20,11 @@ import (
 func TestStore(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
-	path, cleanup := tested.TempDir(t, "", "totalorder-store")
-	defer cleanup()
-
-	db, err := store.NewLevelDB(path)
-	gt.Expect(err).NotTo(HaveOccurred())
+	db := store.NewMemoryKV()
 	defer tested.Close(t, db)
 
-	orderStore := NewStore(crypto.SHA256, db)
+	orderStore, err := NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
 
 	err = orderStore.Append(TXIDAndHMAC{
 		ID:   transaction.ID(sHash("tx1")),
@@ -132,6 +130,120 @@ func TestStore(t *testing.T) {
 	gt.Expect(accumulator).To(Equal(acculatorAfterTx2))
 }
 
+func TestStoreRecover(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	path, cleanup := tested.TempDir(t, "", "totalorder-store")
+	defer cleanup()
+
+	db, err := store.NewLevelDB(path)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orderStore, err := NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	for _, tx := range []string{"tx1", "tx2"} {
+		err = orderStore.Append(TXIDAndHMAC{ID: sHash(tx), HMAC: sHash(tx + "secret")})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	accumulator := orderStore.accumulator
+	tested.Close(t, db)
+
+	db, err = store.NewLevelDB(path)
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer tested.Close(t, db)
+
+	orderStore, err = NewStore(crypto.SHA256, db)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orderStore.nextSequence).To(Equal(uint64(2)))
+	gt.Expect(orderStore.accumulator).To(Equal(accumulator))
+
+	tah, err := orderStore.Get(context.Background(), 1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(TXIDAndHMAC{ID: sHash("tx2"), HMAC: sHash("tx2" + "secret")}))
+
+	tx3 := TXIDAndHMAC{ID: sHash("tx3"), HMAC: sHash("tx3" + "secret")}
+	err = orderStore.Append(tx3)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tah, err = orderStore.Get(context.Background(), 2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(tah).To(Equal(tx3))
+
+	persisted, err := db.Get(keyMetadataAccumulator)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(persisted).To(Equal(bHash(append(accumulator, tx3.serialize()...))))
+}
+
+func TestStoreRecoverInconsistent(t *testing.T) {
+	tests := map[string]struct {
+		corrupt     func(kv store.KV)
+		errContains string
+	}{
+		"missing last entry": {
+			corrupt:     func(kv store.KV) { kv.Delete(txKey(1)) },
+			errContains: "entry missing for last committed sequence 1",
+		},
+		"entry beyond last committed": {
+			corrupt:     func(kv store.KV) { kv.Put(txKey(2), make([]byte, 64)) },
+			errContains: "entry found beyond last committed sequence 1",
+		},
+		"truncated last entry": {
+			corrupt:     func(kv store.KV) { kv.Put(txKey(1), make([]byte, 12)) },
+			errContains: "entry for sequence 1 has invalid length 12",
+		},
+		"missing accumulator": {
+			corrupt:     func(kv store.KV) { kv.Delete(keyMetadataAccumulator) },
+			errContains: "accumulator missing for last committed sequence 1",
+		},
+		"truncated accumulator": {
+			corrupt:     func(kv store.KV) { kv.Put(keyMetadataAccumulator, []byte("short")) },
+			errContains: "accumulator has invalid length 5",
+		},
+		"truncated last committed": {
+			corrupt:     func(kv store.KV) { kv.Put(keyMetadataLastCommitted, []byte{0x1}) },
+			errContains: "last committed sequence has invalid length 1",
+		},
+		"mismatched checkpoint": {
+			corrupt:     func(kv store.KV) { kv.Put(accumulatorKey(1), sHash("forged")) },
+			errContains: "accumulator does not match checkpoint for sequence 1",
+		},
+		"missing last committed": {
+			corrupt:     func(kv store.KV) { kv.Delete(keyMetadataLastCommitted) },
+			errContains: "accumulator found without a last committed sequence",
+		},
+		"entries without metadata": {
+			corrupt: func(kv store.KV) {
+				kv.Delete(keyMetadataLastCommitted)
+				kv.Delete(keyMetadataAccumulator)
+			},
+			errContains: "entry found without a last committed sequence",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			db, err := store.NewLevelDB("")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer tested.Close(t, db)
+
+			orderStore, err := NewStore(crypto.SHA256, db)
+			gt.Expect(err).NotTo(HaveOccurred())
+			for _, tx := range []string{"tx1", "tx2"} {
+				err = orderStore.Append(TXIDAndHMAC{ID: sHash(tx), HMAC: sHash(tx + "secret")})
+				gt.Expect(err).NotTo(HaveOccurred())
+			}
+
+			tt.corrupt(db)
+
+			_, err = NewStore(crypto.SHA256, db)
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.errContains)))
+			gt.Expect(errors.Is(err, ErrInconsistent)).To(BeTrue())
+		})
+	}
+}
+
 func sHash(value string) []byte {
 	return bHash([]byte(value))
 }
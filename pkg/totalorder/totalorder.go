@@ -3,8 +3,13 @@
 
 package totalorder
 
-// TXIDAndHMAC is a pair of txid, and an HMAC computed using
-// the namespace name, a secret, and the txid.  This allows for
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// TXIDAndHMAC is a pair of txid, and an HMAC of the txid computed
+// using the secret shared by the namespace members.  This allows for
 // namespace members to detect transactions for their namespace
 // while other namespace members can only discern it is not
 // for a namespcae they care about.
@@ -13,6 +18,27 @@ type TXIDAndHMAC struct {
 	HMAC []byte
 }
 
+// NewTXIDAndHMAC creates the entry for txid with an HMAC computed over txid
+// using the provided namespace secret.
+func NewTXIDAndHMAC(secret, txid []byte) TXIDAndHMAC {
+	return TXIDAndHMAC{
+		ID:   txid,
+		HMAC: computeHMAC(secret, txid),
+	}
+}
+
+// Verify reports whether the HMAC of the entry was computed over its txid
+// with the provided namespace secret.
+func (t *TXIDAndHMAC) Verify(secret []byte) bool {
+	return hmac.Equal(t.HMAC, computeHMAC(secret, t.ID))
+}
+
+func computeHMAC(secret, txid []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(txid)
+	return h.Sum(nil)
+}
+
 func (t *TXIDAndHMAC) serialize() []byte {
 	if len(t.ID) != 32 || len(t.HMAC) != 32 {
 		// XXX we should probably define a better serialization?
@@ -0,0 +1,31 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package totalorder
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTXIDAndHMAC(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	txid := sHash("tx1")
+	tah := NewTXIDAndHMAC([]byte("secret"), txid)
+	gt.Expect(tah.ID).To(Equal(txid))
+
+	h := hmac.New(sha256.New, []byte("secret"))
+	h.Write(txid)
+	gt.Expect(tah.HMAC).To(Equal(h.Sum(nil)))
+
+	gt.Expect(tah.Verify([]byte("secret"))).To(BeTrue())
+	gt.Expect(tah.Verify([]byte("other-secret"))).To(BeFalse())
+	gt.Expect(tah.Verify(nil)).To(BeFalse())
+
+	forged := TXIDAndHMAC{ID: sHash("tx2"), HMAC: tah.HMAC}
+	gt.Expect(forged.Verify([]byte("secret"))).To(BeFalse())
+}
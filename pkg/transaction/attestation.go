@@ -0,0 +1,117 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/sykesm/batik/pkg/ecdsautil"
+)
+
+// attestationDomain separates attestation digests from other signed data.
+const attestationDomain = "batik/commit-attestation/v1"
+
+// ErrInvalidAttestation is returned when an attestation fails verification.
+var ErrInvalidAttestation = errors.New("invalid attestation")
+
+// An Attestation is a statement signed by a node that a transaction was
+// committed in a namespace at a particular sequence. The accumulator of the
+// total order at that sequence, when available, binds the commit to the
+// contents of the ordered log.
+//
+// The public key is the PKIX, ASN.1 DER form of the key that verifies the
+// signature.
+type Attestation struct {
+	Namespace   string `json:"namespace"`
+	SeqNo       uint64 `json:"seq_no"`
+	TxID        ID     `json:"txid"`
+	ReceiptID   []byte `json:"receipt_id"`
+	Accumulator []byte `json:"accumulator,omitempty"`
+	PublicKey   []byte `json:"public_key"`
+	Signature   []byte `json:"signature"`
+}
+
+// NewAttestation creates an attestation for the commit of a transaction and
+// signs it with the provided key.
+func NewAttestation(key *ecdsa.PrivateKey, namespace string, seqNo uint64, txID ID, receiptID, accumulator []byte) (*Attestation, error) {
+	pub, err := ecdsautil.MarshalPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal attestation public key")
+	}
+
+	a := &Attestation{
+		Namespace:   namespace,
+		SeqNo:       seqNo,
+		TxID:        txID,
+		ReceiptID:   receiptID,
+		Accumulator: accumulator,
+		PublicKey:   pub,
+	}
+	a.Signature, err = ecdsautil.Sign(rand.Reader, key, a.Digest())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign attestation")
+	}
+	return a, nil
+}
+
+// Digest returns the SHA-256 digest of the attested fields. Each field is
+// protowire encoded with a distinct field number so the digest is
+// unambiguous.
+func (a *Attestation) Digest() []byte {
+	var b []byte
+	b = append(b, attestationDomain...)
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, a.Namespace)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, a.SeqNo)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendBytes(b, a.TxID)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, a.ReceiptID)
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, a.Accumulator)
+
+	digest := sha256.Sum256(b)
+	return digest[:]
+}
+
+// Signer returns the public key embedded in the attestation.
+func (a *Attestation) Signer() (*ecdsa.PublicKey, error) {
+	pub, err := ecdsautil.UnmarshalPublicKey(a.PublicKey)
+	if err != nil {
+		return nil, errors.WithMessagef(ErrInvalidAttestation, "bad public key: %s", err)
+	}
+	return pub, nil
+}
+
+// Verify checks that the attestation was signed by the node that holds the
+// private key for pub. The embedded public key must match pub; it only
+// identifies the signer and is not trusted on its own.
+func (a *Attestation) Verify(pub *ecdsa.PublicKey) error {
+	if pub == nil {
+		return errors.WithMessage(ErrInvalidAttestation, "public key is required")
+	}
+	expected, err := ecdsautil.MarshalPublicKey(pub)
+	if err != nil {
+		return errors.WithMessage(err, "failed to marshal public key")
+	}
+	if !bytes.Equal(expected, a.PublicKey) {
+		return errors.WithMessage(ErrInvalidAttestation, "signed by a different key")
+	}
+
+	valid, err := ecdsautil.Verify(pub, a.Signature, a.Digest())
+	if err != nil {
+		return errors.WithMessagef(ErrInvalidAttestation, "bad signature: %s", err)
+	}
+	if !valid {
+		return errors.WithMessage(ErrInvalidAttestation, "signature does not match")
+	}
+	return nil
+}
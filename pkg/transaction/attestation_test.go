@@ -0,0 +1,83 @@
+// Copyright IBM Corp. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transaction
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/sykesm/batik/pkg/ecdsautil"
+)
+
+func TestAttestation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	key, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	a, err := NewAttestation(key, "ns", 3, ID("txid"), []byte("receipt-id"), []byte("accumulator"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(a.Namespace).To(Equal("ns"))
+	gt.Expect(a.SeqNo).To(Equal(uint64(3)))
+	gt.Expect(a.Verify(&key.PublicKey)).To(Succeed())
+
+	signer, err := a.Signer()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signer).To(Equal(&key.PublicKey))
+
+	encoded, err := json.Marshal(a)
+	gt.Expect(err).NotTo(HaveOccurred())
+	var decoded Attestation
+	gt.Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+	gt.Expect(&decoded).To(Equal(a))
+	gt.Expect(decoded.Verify(&key.PublicKey)).To(Succeed())
+}
+
+func TestAttestationVerifyFailures(t *testing.T) {
+	key, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	other, err := ecdsautil.GenerateKey(elliptic.P256(), rand.Reader)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := map[string]struct {
+		tamper   func(*Attestation)
+		pubKey   func() *ecdsa.PublicKey
+		errMatch string
+	}{
+		"no key":         {pubKey: func() *ecdsa.PublicKey { return nil }, errMatch: "public key is required: invalid attestation"},
+		"other key":      {pubKey: func() *ecdsa.PublicKey { return &other.PublicKey }, errMatch: "signed by a different key: invalid attestation"},
+		"namespace":      {tamper: func(a *Attestation) { a.Namespace = "other" }, errMatch: "signature does not match: invalid attestation"},
+		"seq":            {tamper: func(a *Attestation) { a.SeqNo++ }, errMatch: "signature does not match: invalid attestation"},
+		"txid":           {tamper: func(a *Attestation) { a.TxID = ID("other") }, errMatch: "signature does not match: invalid attestation"},
+		"receipt":        {tamper: func(a *Attestation) { a.ReceiptID = nil }, errMatch: "signature does not match: invalid attestation"},
+		"accumulator":    {tamper: func(a *Attestation) { a.Accumulator = []byte("other") }, errMatch: "signature does not match: invalid attestation"},
+		"bad signature":  {tamper: func(a *Attestation) { a.Signature = []byte("garbage") }, errMatch: "bad signature: .*: invalid attestation"},
+		"substitute key": {tamper: func(a *Attestation) { a.PublicKey, _ = ecdsautil.MarshalPublicKey(&other.PublicKey) }, errMatch: "signed by a different key: invalid attestation"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			a, err := NewAttestation(key, "ns", 3, ID("txid"), []byte("receipt-id"), []byte("accumulator"))
+			gt.Expect(err).NotTo(HaveOccurred())
+			if tt.tamper != nil {
+				tt.tamper(a)
+			}
+			pub := &key.PublicKey
+			if tt.pubKey != nil {
+				pub = tt.pubKey()
+			}
+
+			err = a.Verify(pub)
+			gt.Expect(err).To(MatchError(ErrInvalidAttestation))
+			gt.Expect(err).To(MatchError(MatchRegexp(tt.errMatch)))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package transaction
 import (
 	"crypto/hmac"
 	"errors"
+	"time"
 
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
@@ -126,10 +127,32 @@ func NewReceipt(hasher merkle.Hasher, txid ID, sigs []*Signature) *Receipt {
 }
 
 // Committed indicates that a transaction successfull committed at a particular
-// sequence for a given transaction receipt.
+// sequence for a given transaction receipt. The names of the validators that
+// accepted the transaction are recorded in the order they ran. When the node
+// has an identity, the commit carries the node's signed attestation.
 type Committed struct {
-	ReceiptID []byte `json:"receipt_id"`
-	SeqNo     uint64 `json:"seq_no"`
+	ReceiptID   []byte       `json:"receipt_id"`
+	SeqNo       uint64       `json:"seq_no"`
+	Validators  []string     `json:"validators,omitempty"`
+	Attestation *Attestation `json:"attestation,omitempty"`
+}
+
+// Rejected indicates that a transaction receipt ordered at a particular
+// sequence failed commit processing and records the reason it was rejected.
+// When the transaction was rejected by validators, the names of the
+// validators and the error message from their responses are also recorded.
+// When an input or reference could not be resolved, the missing state is
+// recorded along with whether, and by which transaction, it was consumed.
+type Rejected struct {
+	ReceiptID    []byte    `json:"receipt_id"`
+	SeqNo        uint64    `json:"seq_no"`
+	Reason       string    `json:"reason"`
+	Validators   []string  `json:"validators,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	MissingState *StateID  `json:"missing_state,omitempty"`
+	Consumed     bool      `json:"consumed,omitempty"`
+	ConsumedBy   ID        `json:"consumed_by,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
 }
 
 // encodedElement returns the encoded pieces of each message in a transaction.
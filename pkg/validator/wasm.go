@@ -14,23 +14,25 @@ import (
 )
 
 type WASM struct {
-	store  *wasmtime.Store
+	engine *wasmtime.Engine
 	module *wasmtime.Module
 }
 
 func NewWASM(engine *wasmtime.Engine, asm []byte) (*WASM, error) {
-	store := wasmtime.NewStore(engine)
 	module, err := wasmtime.NewModule(engine, asm)
 	if err != nil {
 		return nil, err
 	}
-	return &WASM{store: store, module: module}, nil
+	return &WASM{engine: engine, module: module}, nil
 }
 
+// Validate instantiates the module in a new store for each request. Stores
+// can not be shared across goroutines so this allows requests to be validated
+// concurrently.
 func (w *WASM) Validate(req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
 	v := &UTXOValidator{
 		adapter: &adapter{},
-		store:   w.store,
+		store:   wasmtime.NewStore(w.engine),
 		module:  w.module,
 	}
 	return v.Validate(req)